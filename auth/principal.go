@@ -0,0 +1,46 @@
+// Package auth carries the authenticated user through context.Context, so
+// services can read it without depending on gin.
+package auth
+
+import (
+	"context"
+
+	"dailyworkerroster/model"
+)
+
+// Principal is the user a request is made by, as read from its token.
+type Principal struct {
+	UserID    int64
+	Name      string
+	Role      string // ADMIN, WORKER
+	TokenID   string // jti claim of the access token
+	SessionID string // sid claim, the login session the token belongs to
+}
+
+func (p *Principal) IsAdmin() bool {
+	return p.Role == model.ROLE_ADMIN
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying p.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal stored in ctx, if any.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// UserID returns the ID of the principal in ctx, or nil when the call is not
+// made on behalf of a user (background jobs, migrations).
+func UserID(ctx context.Context) *int64 {
+	p, ok := FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	id := p.UserID
+	return &id
+}
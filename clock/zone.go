@@ -0,0 +1,26 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+var zones sync.Map // zone name -> *time.Location
+
+// LoadLocation is time.LoadLocation with a cache, as zones are looked up for
+// every shift read. An empty name is fallback, the zone configured for
+// locations without one.
+func LoadLocation(name string, fallback *time.Location) (*time.Location, error) {
+	if name == "" {
+		return fallback, nil
+	}
+	if loc, ok := zones.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	zones.Store(name, loc)
+	return loc, nil
+}
@@ -0,0 +1,481 @@
+// Package config loads the server configuration: built-in defaults, then an
+// optional JSON file, then environment variables, validated once at startup.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	EnvDev        = "dev"
+	EnvProduction = "production"
+
+	// DefaultJWTSecret is only accepted in dev mode.
+	DefaultJWTSecret = "FROMCONFIG"
+
+	devDatabaseDSN = "root:password@tcp(127.0.0.1:3306)/dailyworkerroster?parseTime=true"
+)
+
+type Config struct {
+	Env        string           `json:"env"` // dev, production
+	Port       string           `json:"port"`
+	TimeZone   string           `json:"time_zone"` // IANA zone for locations without one; worker dashboards count weeks in it
+	Database   DatabaseConfig   `json:"database"`
+	Auth       AuthConfig       `json:"auth"`
+	Shift      ShiftConfig      `json:"shift"`
+	Rules      RulesConfig      `json:"rules"`
+	Fairness   FairnessConfig   `json:"fairness"`
+	Attendance AttendanceConfig `json:"attendance"`
+	Scheduler  SchedulerConfig  `json:"scheduler"`
+}
+
+type DatabaseConfig struct {
+	// "sqlite://<path>" selects the embedded SQLite backend, anything else is MySQL
+	DSN             string   `json:"dsn"`
+	AutoMigrate     bool     `json:"auto_migrate"`
+	MaxOpenConns    int      `json:"max_open_conns"` // MySQL only, 0 means unlimited
+	MaxIdleConns    int      `json:"max_idle_conns"` // MySQL only
+	ConnMaxLifetime Duration `json:"conn_max_lifetime"`
+}
+
+type AuthConfig struct {
+	JWTSecret       string   `json:"jwt_secret"`
+	AccessTokenTTL  Duration `json:"access_token_ttl"`
+	RefreshTokenTTL Duration `json:"refresh_token_ttl"`
+}
+
+type ShiftConfig struct {
+	// How long before the start a worker may still cancel an approved shift
+	CancellationNotice Duration `json:"cancellation_notice"`
+	// How long a waitlisted worker has to accept a vacated place, cut short
+	// by the start of the shift
+	OfferTTL Duration `json:"offer_ttl"`
+}
+
+// RulesConfig sets the labour rules checked when a worker requests, is
+// approved for, or takes over a shift. Role settings override the defaults
+// and location settings override both; a field left out keeps the value
+// from the level below.
+type RulesConfig struct {
+	Default   RuleSet            `json:"default"`
+	Roles     map[string]RuleSet `json:"roles"`
+	Locations map[string]RuleSet `json:"locations"`
+}
+
+// RuleSet holds the rule limits. A nil or zero limit disables the rule,
+// except the overlap check which always applies.
+type RuleSet struct {
+	MaxShiftsPerDay    *int        `json:"max_shifts_per_day"`
+	MaxShiftsPerWeek   *int        `json:"max_shifts_per_week"` // Monday to Sunday
+	MaxHoursPerDay     *float64    `json:"max_hours_per_day"`   // per calendar day worked, a night shift counts towards both days
+	MaxHoursPerWeek    *float64    `json:"max_hours_per_week"`  // Monday to Sunday
+	MinRestHours       *float64    `json:"min_rest_hours"`      // between the end of one shift and the start of the next
+	MaxConsecutiveDays *int        `json:"max_consecutive_days"`
+	MinorCurfew        *CurfewRule `json:"minor_curfew"`
+}
+
+// CurfewRule keeps workers younger than MinAge out of shifts overlapping
+// the From-Until window ("22:00" to "06:00" wraps past midnight).
+type CurfewRule struct {
+	MinAge int    `json:"min_age"`
+	From   string `json:"from"`
+	Until  string `json:"until"`
+}
+
+// Merge returns s with the fields set in override replaced.
+func (s RuleSet) Merge(override RuleSet) RuleSet {
+	if override.MaxShiftsPerDay != nil {
+		s.MaxShiftsPerDay = override.MaxShiftsPerDay
+	}
+	if override.MaxShiftsPerWeek != nil {
+		s.MaxShiftsPerWeek = override.MaxShiftsPerWeek
+	}
+	if override.MaxHoursPerDay != nil {
+		s.MaxHoursPerDay = override.MaxHoursPerDay
+	}
+	if override.MaxHoursPerWeek != nil {
+		s.MaxHoursPerWeek = override.MaxHoursPerWeek
+	}
+	if override.MinRestHours != nil {
+		s.MinRestHours = override.MinRestHours
+	}
+	if override.MaxConsecutiveDays != nil {
+		s.MaxConsecutiveDays = override.MaxConsecutiveDays
+	}
+	if override.MinorCurfew != nil {
+		s.MinorCurfew = override.MinorCurfew
+	}
+	return s
+}
+
+// For returns the rule set that applies to a shift with the given role and
+// location. Location names match regardless of case, like locations do.
+func (c RulesConfig) For(role, location string) RuleSet {
+	set := c.Default
+	if override, ok := c.Roles[role]; ok {
+		set = set.Merge(override)
+	}
+	for name, override := range c.Locations {
+		if strings.EqualFold(name, location) {
+			set = set.Merge(override)
+			break
+		}
+	}
+	return set
+}
+
+// FairnessConfig ranks the applicants of a shift. Each factor is scaled
+// between the applicants, from 0 for the least favoured to 1 for the most,
+// and multiplied by its weight; a zero weight ignores the factor.
+type FairnessConfig struct {
+	Weights FairnessWeights `json:"weights"`
+	// Period hours count the days up to the end of the shift's week, and
+	// cancellations and no-shows the days since as many days ago
+	PeriodDays int `json:"period_days"`
+	// Open shifts starting within this are filled with their top ranked
+	// eligible applicants by the scheduler; 0 leaves the choice to admins
+	AutoApproveBefore Duration `json:"auto_approve_before"`
+}
+
+type FairnessWeights struct {
+	WeekHours     float64 `json:"week_hours"`    // fewer hours booked in the shift's week
+	PeriodHours   float64 `json:"period_hours"`  // fewer hours booked in the period
+	Seniority     float64 `json:"seniority"`     // signed up earlier
+	Cancellations float64 `json:"cancellations"` // fewer approved shifts cancelled lately
+	NoShows       float64 `json:"no_shows"`      // fewer no-shows lately
+	RequestTime   float64 `json:"request_time"`  // requested the shift earlier
+}
+
+// AttendanceConfig sets when workers may clock in and out of their approved
+// shifts.
+type AttendanceConfig struct {
+	// How long before the start a worker may clock in
+	ClockInEarly Duration `json:"clock_in_early"`
+	// Clocking in more than this after the start counts as late
+	LateTolerance Duration `json:"late_tolerance"`
+	// Clocking out more than this before the end counts as leaving early
+	EarlyLeaveTolerance Duration `json:"early_leave_tolerance"`
+	// How long after the end a worker may still clock out; then the
+	// scheduler clocks them out at the end, or marks a no-show when they
+	// never clocked in
+	ClockOutGrace Duration `json:"clock_out_grace"`
+}
+
+type SchedulerConfig struct {
+	Interval Duration `json:"interval"` // 0 disables background jobs
+}
+
+// Duration is a time.Duration written as a Go duration string ("15m") in
+// the config file.
+type Duration time.Duration
+
+func (d Duration) Std() time.Duration {
+	return time.Duration(d)
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"15m\": %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Default returns the built-in configuration.
+func Default() *Config {
+	return &Config{
+		Env:      EnvProduction,
+		Port:     "8080",
+		TimeZone: "UTC",
+		Database: DatabaseConfig{
+			MaxIdleConns:    2,
+			ConnMaxLifetime: Duration(time.Hour),
+		},
+		Auth: AuthConfig{
+			JWTSecret:       DefaultJWTSecret,
+			AccessTokenTTL:  Duration(15 * time.Minute),
+			RefreshTokenTTL: Duration(30 * 24 * time.Hour),
+		},
+		Shift: ShiftConfig{
+			CancellationNotice: Duration(24 * time.Hour),
+			OfferTTL:           Duration(12 * time.Hour),
+		},
+		Rules: RulesConfig{
+			Default: RuleSet{
+				MaxShiftsPerDay:  intPtr(1),
+				MaxShiftsPerWeek: intPtr(5),
+				MaxHoursPerDay:   floatPtr(12),
+				MaxHoursPerWeek:  floatPtr(40),
+			},
+		},
+		Fairness: FairnessConfig{
+			Weights: FairnessWeights{
+				WeekHours:     3,
+				PeriodHours:   2,
+				Seniority:     1,
+				Cancellations: 2,
+				NoShows:       3,
+				RequestTime:   1,
+			},
+			PeriodDays: 28,
+		},
+		Attendance: AttendanceConfig{
+			ClockInEarly:        Duration(30 * time.Minute),
+			LateTolerance:       Duration(5 * time.Minute),
+			EarlyLeaveTolerance: Duration(5 * time.Minute),
+			ClockOutGrace:       Duration(2 * time.Hour),
+		},
+		Scheduler: SchedulerConfig{
+			Interval: Duration(time.Minute),
+		},
+	}
+}
+
+// Load builds the configuration from the defaults, the JSON file at path
+// (skipped when path is empty) and the environment, and validates it.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("config: %w", err)
+		}
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(cfg); err != nil {
+			return nil, fmt.Errorf("config %s: %w", path, err)
+		}
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+	if cfg.Env == EnvDev && cfg.Database.DSN == "" {
+		cfg.Database.DSN = devDatabaseDSN
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// applyEnv overrides the file values with the environment variables that
+// are set.
+func (c *Config) applyEnv() error {
+	var errs []error
+	setString := func(name string, dst *string) {
+		if value, ok := os.LookupEnv(name); ok {
+			*dst = value
+		}
+	}
+	setBool := func(name string, dst *bool) {
+		if value, ok := os.LookupEnv(name); ok {
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+				return
+			}
+			*dst = b
+		}
+	}
+	setInt := func(name string, dst *int) {
+		if value, ok := os.LookupEnv(name); ok {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+				return
+			}
+			*dst = n
+		}
+	}
+	setIntPtr := func(name string, dst **int) {
+		if value, ok := os.LookupEnv(name); ok {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+				return
+			}
+			*dst = &n
+		}
+	}
+	setFloatPtr := func(name string, dst **float64) {
+		if value, ok := os.LookupEnv(name); ok {
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+				return
+			}
+			*dst = &f
+		}
+	}
+	setDuration := func(name string, dst *Duration) {
+		if value, ok := os.LookupEnv(name); ok {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+				return
+			}
+			*dst = Duration(d)
+		}
+	}
+
+	setString("APP_ENV", &c.Env)
+	setString("PORT", &c.Port)
+	setString("DEFAULT_TIME_ZONE", &c.TimeZone)
+	setString("DATABASE_DSN", &c.Database.DSN)
+	setBool("AUTO_MIGRATE", &c.Database.AutoMigrate)
+	setInt("DATABASE_MAX_OPEN_CONNS", &c.Database.MaxOpenConns)
+	setInt("DATABASE_MAX_IDLE_CONNS", &c.Database.MaxIdleConns)
+	setDuration("DATABASE_CONN_MAX_LIFETIME", &c.Database.ConnMaxLifetime)
+	setString("JWT_SECRET", &c.Auth.JWTSecret)
+	setDuration("ACCESS_TOKEN_TTL", &c.Auth.AccessTokenTTL)
+	setDuration("REFRESH_TOKEN_TTL", &c.Auth.RefreshTokenTTL)
+	setIntPtr("MAX_SHIFTS_PER_WEEK", &c.Rules.Default.MaxShiftsPerWeek)
+	setFloatPtr("MAX_HOURS_PER_DAY", &c.Rules.Default.MaxHoursPerDay)
+	setFloatPtr("MAX_HOURS_PER_WEEK", &c.Rules.Default.MaxHoursPerWeek)
+	setDuration("CANCELLATION_NOTICE", &c.Shift.CancellationNotice)
+	setDuration("OFFER_TTL", &c.Shift.OfferTTL)
+	setDuration("AUTO_APPROVE_BEFORE", &c.Fairness.AutoApproveBefore)
+	setDuration("CLOCK_IN_EARLY", &c.Attendance.ClockInEarly)
+	setDuration("LATE_TOLERANCE", &c.Attendance.LateTolerance)
+	setDuration("EARLY_LEAVE_TOLERANCE", &c.Attendance.EarlyLeaveTolerance)
+	setDuration("CLOCK_OUT_GRACE", &c.Attendance.ClockOutGrace)
+	setDuration("SCHEDULER_INTERVAL", &c.Scheduler.Interval)
+
+	return errors.Join(errs...)
+}
+
+// Validate reports every invalid setting at once.
+func (c *Config) Validate() error {
+	var errs []error
+	if c.Env != EnvDev && c.Env != EnvProduction {
+		errs = append(errs, fmt.Errorf("env must be %q or %q, got %q", EnvDev, EnvProduction, c.Env))
+	}
+	if c.Port == "" {
+		errs = append(errs, errors.New("port is required"))
+	}
+	if _, err := time.LoadLocation(c.TimeZone); err != nil {
+		errs = append(errs, fmt.Errorf("time zone: %w", err))
+	}
+	if c.Database.DSN == "" {
+		errs = append(errs, errors.New("database dsn is required (DATABASE_DSN)"))
+	}
+	if c.Auth.JWTSecret == "" {
+		errs = append(errs, errors.New("jwt secret is required (JWT_SECRET)"))
+	}
+	if c.Env != EnvDev && c.Auth.JWTSecret == DefaultJWTSecret {
+		errs = append(errs, errors.New("refusing to start with the default jwt secret outside dev mode, set JWT_SECRET"))
+	}
+	if c.Auth.AccessTokenTTL <= 0 || c.Auth.RefreshTokenTTL <= 0 {
+		errs = append(errs, errors.New("token ttls must be positive"))
+	}
+	if c.Auth.RefreshTokenTTL < c.Auth.AccessTokenTTL {
+		errs = append(errs, errors.New("refresh token ttl must not be shorter than the access token ttl"))
+	}
+	errs = append(errs, c.Rules.Default.validate("rules.default"))
+	for role, set := range c.Rules.Roles {
+		errs = append(errs, set.validate("rules.roles."+role))
+	}
+	for location, set := range c.Rules.Locations {
+		errs = append(errs, set.validate("rules.locations."+location))
+	}
+	if c.Shift.CancellationNotice < 0 {
+		errs = append(errs, errors.New("cancellation notice must not be negative"))
+	}
+	if c.Shift.OfferTTL <= 0 {
+		errs = append(errs, errors.New("offer ttl must be positive"))
+	}
+	errs = append(errs, c.Fairness.validate())
+	if c.Attendance.ClockInEarly < 0 || c.Attendance.LateTolerance < 0 ||
+		c.Attendance.EarlyLeaveTolerance < 0 || c.Attendance.ClockOutGrace < 0 {
+		errs = append(errs, errors.New("attendance durations must not be negative"))
+	}
+	if c.Scheduler.Interval < 0 {
+		errs = append(errs, errors.New("scheduler interval must not be negative"))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	return nil
+}
+
+func (s RuleSet) validate(name string) error {
+	var errs []error
+	for field, value := range map[string]*int{
+		"max_shifts_per_day":   s.MaxShiftsPerDay,
+		"max_shifts_per_week":  s.MaxShiftsPerWeek,
+		"max_consecutive_days": s.MaxConsecutiveDays,
+	} {
+		if value != nil && *value < 0 {
+			errs = append(errs, fmt.Errorf("%s.%s must not be negative", name, field))
+		}
+	}
+	for field, value := range map[string]*float64{
+		"max_hours_per_day":  s.MaxHoursPerDay,
+		"max_hours_per_week": s.MaxHoursPerWeek,
+		"min_rest_hours":     s.MinRestHours,
+	} {
+		if value != nil && *value < 0 {
+			errs = append(errs, fmt.Errorf("%s.%s must not be negative", name, field))
+		}
+	}
+	if curfew := s.MinorCurfew; curfew != nil {
+		if curfew.MinAge < 1 {
+			errs = append(errs, fmt.Errorf("%s.minor_curfew.min_age must be at least 1", name))
+		}
+		for _, clock := range []string{curfew.From, curfew.Until} {
+			if _, err := time.Parse("15:04", clock); err != nil {
+				errs = append(errs, fmt.Errorf("%s.minor_curfew: %q is not HH:MM", name, clock))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (f FairnessConfig) validate() error {
+	var errs []error
+	for field, value := range map[string]float64{
+		"week_hours":    f.Weights.WeekHours,
+		"period_hours":  f.Weights.PeriodHours,
+		"seniority":     f.Weights.Seniority,
+		"cancellations": f.Weights.Cancellations,
+		"no_shows":      f.Weights.NoShows,
+		"request_time":  f.Weights.RequestTime,
+	} {
+		if value < 0 {
+			errs = append(errs, fmt.Errorf("fairness.weights.%s must not be negative", field))
+		}
+	}
+	if f.PeriodDays < 7 {
+		errs = append(errs, errors.New("fairness.period_days must be at least 7"))
+	}
+	if f.AutoApproveBefore < 0 {
+		errs = append(errs, errors.New("fairness.auto_approve_before must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
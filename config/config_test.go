@@ -0,0 +1,165 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"dailyworkerroster/config"
+)
+
+var envNames = []string{
+	"APP_ENV", "PORT", "DEFAULT_TIME_ZONE", "DATABASE_DSN", "AUTO_MIGRATE",
+	"DATABASE_MAX_OPEN_CONNS", "DATABASE_MAX_IDLE_CONNS", "DATABASE_CONN_MAX_LIFETIME",
+	"JWT_SECRET", "ACCESS_TOKEN_TTL", "REFRESH_TOKEN_TTL",
+	"MAX_SHIFTS_PER_WEEK", "MAX_HOURS_PER_DAY", "MAX_HOURS_PER_WEEK",
+	"CANCELLATION_NOTICE", "OFFER_TTL", "AUTO_APPROVE_BEFORE",
+	"CLOCK_IN_EARLY", "LATE_TOLERANCE", "EARLY_LEAVE_TOLERANCE", "CLOCK_OUT_GRACE",
+	"SCHEDULER_INTERVAL",
+}
+
+// clearEnv unsets the variables Load reads for the rest of the test.
+func clearEnv(t *testing.T) {
+	t.Helper()
+
+	for _, name := range envNames {
+		t.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+}
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	return path
+}
+
+func TestLoadExample(t *testing.T) {
+	clearEnv(t)
+
+	if _, err := config.Load("../config.example.json"); err != nil {
+		t.Errorf("Load of the example config: %v", err)
+	}
+}
+
+func TestLoadEnvOverridesFile(t *testing.T) {
+	clearEnv(t)
+	path := writeConfig(t, `{
+		"port": "9000",
+		"database": {"dsn": "sqlite://file.db"},
+		"auth": {"jwt_secret": "from-file"},
+		"rules": {"default": {"max_hours_per_day": 10}}
+	}`)
+	t.Setenv("PORT", "9100")
+	t.Setenv("MAX_HOURS_PER_DAY", "9.5")
+	t.Setenv("OFFER_TTL", "2h")
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Port != "9100" {
+		t.Errorf("port %q, want the environment's 9100", cfg.Port)
+	}
+	if cfg.Database.DSN != "sqlite://file.db" || cfg.Auth.JWTSecret != "from-file" {
+		t.Errorf("dsn %q and secret %q, want the file's", cfg.Database.DSN, cfg.Auth.JWTSecret)
+	}
+	if got := *cfg.Rules.Default.MaxHoursPerDay; got != 9.5 {
+		t.Errorf("max hours per day %v, want 9.5", got)
+	}
+	if got := cfg.Shift.OfferTTL.Std(); got != 2*time.Hour {
+		t.Errorf("offer ttl %v, want 2h", got)
+	}
+	// Not in the file nor the environment
+	if got := *cfg.Rules.Default.MaxHoursPerWeek; got != 40 {
+		t.Errorf("max hours per week %v, want the default 40", got)
+	}
+}
+
+func TestLoadRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		env  map[string]string
+		want string
+	}{
+		{
+			name: "unknown field",
+			file: `{"database": {"dns": "sqlite://file.db"}}`,
+			want: `unknown field "dns"`,
+		},
+		{
+			name: "malformed duration",
+			file: `{"shift": {"offer_ttl": "soon"}}`,
+			want: `invalid duration "soon"`,
+		},
+		{
+			name: "malformed environment variable",
+			env:  map[string]string{"DATABASE_DSN": "sqlite://file.db", "JWT_SECRET": "s", "MAX_HOURS_PER_DAY": "ten"},
+			want: "MAX_HOURS_PER_DAY",
+		},
+		{
+			name: "default secret outside dev",
+			env:  map[string]string{"DATABASE_DSN": "sqlite://file.db"},
+			want: "default jwt secret",
+		},
+		{
+			name: "missing dsn outside dev",
+			env:  map[string]string{"JWT_SECRET": "s"},
+			want: "database dsn is required",
+		},
+		{
+			name: "negative rule",
+			file: `{"rules": {"roles": {"COOK": {"min_rest_hours": -1}}}}`,
+			env:  map[string]string{"DATABASE_DSN": "sqlite://file.db", "JWT_SECRET": "s"},
+			want: "rules.roles.COOK.min_rest_hours",
+		},
+		{
+			name: "refresh shorter than access",
+			env: map[string]string{
+				"DATABASE_DSN": "sqlite://file.db", "JWT_SECRET": "s",
+				"ACCESS_TOKEN_TTL": "1h", "REFRESH_TOKEN_TTL": "30m",
+			},
+			want: "refresh token ttl",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			path := ""
+			if tt.file != "" {
+				path = writeConfig(t, tt.file)
+			}
+
+			_, err := config.Load(path)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load: err = %v, want one mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadDevDefaults(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("APP_ENV", config.EnvDev)
+
+	cfg, err := config.Load("")
+	if err != nil {
+		t.Fatalf("Load in dev mode: %v", err)
+	}
+	if cfg.Auth.JWTSecret != config.DefaultJWTSecret {
+		t.Errorf("jwt secret %q, want the default in dev mode", cfg.Auth.JWTSecret)
+	}
+	if cfg.Database.DSN == "" {
+		t.Error("dev mode has no database dsn")
+	}
+}
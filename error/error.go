@@ -1,8 +1,80 @@
 package error
 
+import "errors"
+
 const (
 
 	// Error message
 	ERR_MAXIMUM_WORKER_SHIFT_WEEK = "Maximum shift in week reached"
 	ERR_WORKER_SHIFT_ON_DAY       = "Maximum shift on day reached"
 )
+
+var (
+	ErrUserNotFound        = errors.New("user not found")
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrInvalidDateOfBirth  = errors.New("date_of_birth must be a past date formatted as YYYY-MM-DD")
+
+	ErrShiftNotFound        = errors.New("shift not found")
+	ErrShiftHasAssignments  = errors.New("shift has or had workers assigned")
+	ErrShiftHasOpenRequests = errors.New("shift has pending or waitlisted requests, reject them first")
+	ErrInvalidHeadcount     = errors.New("headcount must be at least 1")
+	ErrInvalidShiftTime     = errors.New("invalid shift time")
+	ErrHeadcountBelowFilled = errors.New("headcount is below the number of approved workers")
+	ErrNoPendingRequest     = errors.New("no pending request for this worker")
+	ErrNoApprovedShift      = errors.New("shift is not assigned to this worker")
+	ErrCancellationTooLate  = errors.New("shift starts within the cancellation notice period, contact an admin")
+
+	ErrShiftTemplateNotFound = errors.New("shift template not found")
+	ErrShiftTemplateInactive = errors.New("shift template is inactive")
+	ErrInvalidShiftTemplate  = errors.New("invalid shift template")
+	ErrTemplateDateTaken     = errors.New("the template already has a shift on this date")
+
+	ErrWorkerShiftNotFound     = errors.New("worker shift not found")
+	ErrShiftTransferNotFound   = errors.New("shift transfer not found")
+	ErrShiftTransferState      = errors.New("shift transfer is not in a valid state for this action")
+	ErrShiftTransferNotAllowed = errors.New("worker is not a party to this shift transfer")
+	ErrInvalidShiftTransfer    = errors.New("invalid shift transfer")
+
+	ErrRuleViolation = errors.New("worker is not eligible for this shift")
+
+	ErrLocationNotFound = errors.New("location not found")
+	ErrLocationExists   = errors.New("location already exists")
+	ErrLocationInUse    = errors.New("location is used by shifts or templates")
+	ErrInvalidLocation  = errors.New("invalid location")
+
+	ErrSkillNotFound       = errors.New("skill not found")
+	ErrSkillExists         = errors.New("skill already exists")
+	ErrSkillInUse          = errors.New("skill is required by shifts or templates")
+	ErrInvalidSkill        = errors.New("invalid skill")
+	ErrWorkerSkillNotFound = errors.New("worker does not have this skill")
+
+	ErrInvalidAvailability     = errors.New("invalid availability")
+	ErrUnavailableDateNotFound = errors.New("date is not marked unavailable")
+
+	ErrTimeOffNotFound = errors.New("time off not found")
+	ErrInvalidTimeOff  = errors.New("invalid time off")
+	ErrTimeOffOverlap  = errors.New("time off overlaps other time off")
+	ErrTimeOffState    = errors.New("time off is not pending")
+
+	ErrInvalidRoster = errors.New("invalid roster")
+	ErrRosterStale   = errors.New("roster proposal is out of date")
+
+	ErrShiftNotOpen        = errors.New("shift is not open or already full")
+	ErrAlreadyOnShift      = errors.New("worker already requested or holds a place on this shift")
+	ErrShiftStarted        = errors.New("shift has already started")
+	ErrNoEligibleApplicant = errors.New("no eligible applicant for this shift")
+	ErrNoShowNotAllowed    = errors.New("only an approved or done shift that has started and was not clocked in for can be marked as a no-show")
+
+	ErrNoOffer      = errors.New("no open offer for this worker")
+	ErrOfferExpired = errors.New("offer has expired")
+
+	ErrTimeEntryNotFound      = errors.New("time entry not found")
+	ErrInvalidTimeEntry       = errors.New("invalid time entry")
+	ErrTimeEntryState         = errors.New("only approved, done or no-show shifts have a time entry")
+	ErrClockInNotOpen         = errors.New("clock-in is not open for this shift")
+	ErrAlreadyClockedIn       = errors.New("already clocked in for this shift")
+	ErrNotClockedIn           = errors.New("not clocked in for this shift")
+	ErrBreakInProgress        = errors.New("a break is already in progress")
+	ErrNoBreakInProgress      = errors.New("no break in progress")
+	ErrInvalidAttendanceQuery = errors.New("invalid attendance query")
+)
@@ -7,7 +7,7 @@ toolchain go1.23.5
 require (
 	github.com/gin-gonic/gin v1.10.0
 	github.com/go-sql-driver/mysql v1.9.2
-	github.com/spf13/cast v1.8.0
+	github.com/mattn/go-sqlite3 v1.14.22
 	github.com/swaggo/swag v1.8.12
 )
 
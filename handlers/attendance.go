@@ -0,0 +1,232 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"dailyworkerroster/model"
+	"dailyworkerroster/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AttendanceHandler handles clocking in and out of shifts
+type AttendanceHandler struct {
+	AttendanceService service.AttendanceServiceItf
+}
+
+// NewAttendanceHandler creates a new AttendanceHandler
+func NewAttendanceHandler(attendanceService service.AttendanceServiceItf) *AttendanceHandler {
+	return &AttendanceHandler{AttendanceService: attendanceService}
+}
+
+// ClockIn godoc
+// @Summary      Clock in for an approved shift
+// @Description  Allowed from the configured time before the start until the end of the shift. Clocking in after the late tolerance counts as late.
+// @Tags         attendance
+// @Produce      json
+// @Security     BearerAuth
+// @Param        shiftID   path      int  true  "Shift ID"
+// @Param        workerID  path      int  true  "Worker ID"
+// @Success      200  {object}  model.TimeEntry
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /shift/{shiftID}/clock-in/{workerID} [post]
+func (h *AttendanceHandler) ClockIn(c *gin.Context) {
+	h.clock(c, h.AttendanceService.ClockIn)
+}
+
+// ClockOut godoc
+// @Summary      Clock out of an approved shift
+// @Description  Ends a break in progress and marks the shift DONE.
+// @Tags         attendance
+// @Produce      json
+// @Security     BearerAuth
+// @Param        shiftID   path      int  true  "Shift ID"
+// @Param        workerID  path      int  true  "Worker ID"
+// @Success      200  {object}  model.TimeEntry
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /shift/{shiftID}/clock-out/{workerID} [post]
+func (h *AttendanceHandler) ClockOut(c *gin.Context) {
+	h.clock(c, h.AttendanceService.ClockOut)
+}
+
+// StartBreak godoc
+// @Summary      Start a break
+// @Tags         attendance
+// @Produce      json
+// @Security     BearerAuth
+// @Param        shiftID   path      int  true  "Shift ID"
+// @Param        workerID  path      int  true  "Worker ID"
+// @Success      200  {object}  model.TimeEntry
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /shift/{shiftID}/break-start/{workerID} [post]
+func (h *AttendanceHandler) StartBreak(c *gin.Context) {
+	h.clock(c, h.AttendanceService.StartBreak)
+}
+
+// EndBreak godoc
+// @Summary      End the break in progress
+// @Tags         attendance
+// @Produce      json
+// @Security     BearerAuth
+// @Param        shiftID   path      int  true  "Shift ID"
+// @Param        workerID  path      int  true  "Worker ID"
+// @Success      200  {object}  model.TimeEntry
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /shift/{shiftID}/break-end/{workerID} [post]
+func (h *AttendanceHandler) EndBreak(c *gin.Context) {
+	h.clock(c, h.AttendanceService.EndBreak)
+}
+
+// clock runs a clock action for the shift and worker in the path.
+func (h *AttendanceHandler) clock(c *gin.Context, action func(ctx context.Context, shiftID, workerID int64) (*model.TimeEntry, error)) {
+	shiftID, _ := strconv.ParseInt(c.Param("shiftID"), 10, 64)
+	workerID, _ := strconv.ParseInt(c.Param("workerID"), 10, 64)
+	ctx := c.Request.Context()
+	result, err := action(ctx, shiftID, workerID)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetTimeEntry godoc
+// @Summary      Get the time entry of an assignment
+// @Tags         attendance
+// @Produce      json
+// @Security     BearerAuth
+// @Param        workerShiftID  path      int  true  "Worker shift ID"
+// @Success      200  {object}  model.TimeEntry
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/worker-shift/{workerShiftID}/time-entry [get]
+func (h *AttendanceHandler) GetTimeEntry(c *gin.Context) {
+	workerShiftID, _ := strconv.ParseInt(c.Param("workerShiftID"), 10, 64)
+	ctx := c.Request.Context()
+	result, err := h.AttendanceService.GetTimeEntry(ctx, workerShiftID)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// TimeEntryBreakRequest is a break in a time entry correction
+type TimeEntryBreakRequest struct {
+	StartAt time.Time  `json:"start_at" binding:"required"`
+	EndAt   *time.Time `json:"end_at"` // left out while the break lasts
+}
+
+// CorrectTimeEntryRequest is the body of a time entry correction
+type CorrectTimeEntryRequest struct {
+	ClockInAt  time.Time               `json:"clock_in_at" binding:"required"`
+	ClockOutAt *time.Time              `json:"clock_out_at"` // left out while the worker is clocked in
+	Breaks     []TimeEntryBreakRequest `json:"breaks"`       // replaces the breaks when given
+	Reason     string                  `json:"reason" binding:"required"`
+}
+
+// CorrectTimeEntry godoc
+// @Summary      Correct the time entry of an assignment
+// @Description  Sets the clock-in, clock-out and break times of an approved, done or no-show assignment, recording the admin and the reason. With a clock-out time the assignment is DONE, otherwise APPROVED.
+// @Tags         attendance
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        workerShiftID  path      int                      true  "Worker shift ID"
+// @Param        correction     body      CorrectTimeEntryRequest  true  "Corrected times"
+// @Success      200  {object}  model.TimeEntry
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/worker-shift/{workerShiftID}/time-entry [put]
+func (h *AttendanceHandler) CorrectTimeEntry(c *gin.Context) {
+	workerShiftID, _ := strconv.ParseInt(c.Param("workerShiftID"), 10, 64)
+	var req CorrectTimeEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	correction := &model.TimeEntryCorrection{
+		ClockInAt:  req.ClockInAt,
+		ClockOutAt: req.ClockOutAt,
+		Reason:     req.Reason,
+	}
+	if req.Breaks != nil {
+		correction.Breaks = make([]*model.TimeEntryBreak, 0, len(req.Breaks))
+		for _, b := range req.Breaks {
+			correction.Breaks = append(correction.Breaks, &model.TimeEntryBreak{StartAt: b.StartAt, EndAt: b.EndAt})
+		}
+	}
+	ctx := c.Request.Context()
+	result, err := h.AttendanceService.CorrectTimeEntry(ctx, workerShiftID, correction)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetAttendanceStats godoc
+// @Summary      Get attendance statistics
+// @Description  Per worker, the done and no-show shifts dated in the range: shifts clocked in for, late clock-ins, no-shows, and hours worked against hours scheduled.
+// @Tags         attendance
+// @Produce      json
+// @Security     BearerAuth
+// @Param        from             query     string  true   "First day (YYYY-MM-DD)"
+// @Param        to               query     string  true   "Last day (YYYY-MM-DD), at most 366 days after from"
+// @Param        user_account_id  query     int     false  "Only this worker"
+// @Success      200  {object}  model.AttendanceStats
+// @Failure      400  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/attendance [get]
+func (h *AttendanceHandler) GetAttendanceStats(c *gin.Context) {
+	query := model.AttendanceQuery{DateFrom: c.Query("from"), DateTo: c.Query("to")}
+	if user := c.Query("user_account_id"); user != "" {
+		userID, err := strconv.ParseInt(user, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_account_id"})
+			return
+		}
+		query.UserAccountID = &userID
+	}
+	h.attendanceStats(c, query)
+}
+
+// GetWorkerAttendance godoc
+// @Summary      Get a worker's attendance statistics
+// @Tags         attendance
+// @Produce      json
+// @Security     BearerAuth
+// @Param        workerID  path      int     true  "Worker ID"
+// @Param        from      query     string  true  "First day (YYYY-MM-DD)"
+// @Param        to        query     string  true  "Last day (YYYY-MM-DD), at most 366 days after from"
+// @Success      200  {object}  model.AttendanceStats
+// @Failure      400  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /worker/attendance/{workerID} [get]
+func (h *AttendanceHandler) GetWorkerAttendance(c *gin.Context) {
+	workerID, _ := strconv.ParseInt(c.Param("workerID"), 10, 64)
+	h.attendanceStats(c, model.AttendanceQuery{DateFrom: c.Query("from"), DateTo: c.Query("to"), UserAccountID: &workerID})
+}
+
+func (h *AttendanceHandler) attendanceStats(c *gin.Context, query model.AttendanceQuery) {
+	ctx := c.Request.Context()
+	result, err := h.AttendanceService.GetAttendanceStats(ctx, query)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"dailyworkerroster/model"
+	"dailyworkerroster/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AvailabilityHandler handles the workers' availability calendars
+type AvailabilityHandler struct {
+	AvailabilityService service.AvailabilityServiceItf
+}
+
+// NewAvailabilityHandler creates a new AvailabilityHandler
+func NewAvailabilityHandler(availabilityService service.AvailabilityServiceItf) *AvailabilityHandler {
+	return &AvailabilityHandler{AvailabilityService: availabilityService}
+}
+
+// GetAvailability godoc
+// @Summary      Get a worker's availability
+// @Description  Weekly windows, upcoming unavailable dates and preferred locations and roles.
+// @Tags         availability
+// @Produce      json
+// @Security     BearerAuth
+// @Param        workerID  path      int  true  "Worker ID"
+// @Success      200  {object}  model.WorkerAvailability
+// @Failure      500  {object}  map[string]string
+// @Router       /worker/availability/{workerID} [get]
+func (h *AvailabilityHandler) GetAvailability(c *gin.Context) {
+	workerID, _ := strconv.ParseInt(c.Param("workerID"), 10, 64)
+	ctx := c.Request.Context()
+	result, err := h.AvailabilityService.GetAvailability(ctx, workerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// AvailabilityRequest is the body of a weekly availability. A worker
+// without windows is available on every day not marked unavailable.
+type AvailabilityRequest struct {
+	Windows              []*model.AvailabilityWindow `json:"windows"`
+	PreferredLocationIDs []int64                     `json:"preferred_location_ids"`
+	PreferredRoles       []string                    `json:"preferred_roles"` // skill codes
+}
+
+// SetAvailability godoc
+// @Summary      Set a worker's weekly availability
+// @Description  Replaces the weekly windows and the preferred locations and roles. Window times are wall clock times at the shift's location; an end at or before the start runs into the next day.
+// @Tags         availability
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        workerID      path      int                  true  "Worker ID"
+// @Param        availability  body      AvailabilityRequest  true  "Availability"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /worker/availability/{workerID} [put]
+func (h *AvailabilityHandler) SetAvailability(c *gin.Context) {
+	workerID, _ := strconv.ParseInt(c.Param("workerID"), 10, 64)
+	var req AvailabilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx := c.Request.Context()
+	availability := &model.WorkerAvailability{
+		UserAccountID:        workerID,
+		Windows:              req.Windows,
+		PreferredLocationIDs: req.PreferredLocationIDs,
+		PreferredRoles:       req.PreferredRoles,
+	}
+	if err := h.AvailabilityService.SetAvailability(ctx, availability); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Availability updated"})
+}
+
+// UnavailableDateRequest is the body of a day off
+type UnavailableDateRequest struct {
+	Date   string `json:"date" binding:"required"` // YYYY-MM-DD
+	Reason string `json:"reason"`
+}
+
+// MarkUnavailable godoc
+// @Summary      Mark a day the worker cannot work
+// @Description  Marking a day again replaces its reason.
+// @Tags         availability
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        workerID     path      int                     true  "Worker ID"
+// @Param        unavailable  body      UnavailableDateRequest  true  "Unavailable date"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /worker/unavailable/{workerID} [post]
+func (h *AvailabilityHandler) MarkUnavailable(c *gin.Context) {
+	workerID, _ := strconv.ParseInt(c.Param("workerID"), 10, 64)
+	var req UnavailableDateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx := c.Request.Context()
+	unavailable := &model.UnavailableDate{UserAccountID: workerID, Date: req.Date, Reason: req.Reason}
+	if err := h.AvailabilityService.MarkUnavailable(ctx, unavailable); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Date marked unavailable"})
+}
+
+// ClearUnavailable godoc
+// @Summary      Make a day available again
+// @Tags         availability
+// @Produce      json
+// @Security     BearerAuth
+// @Param        workerID  path      int     true  "Worker ID"
+// @Param        date      path      string  true  "Date (YYYY-MM-DD)"
+// @Success      200  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /worker/unavailable/{workerID}/{date} [delete]
+func (h *AvailabilityHandler) ClearUnavailable(c *gin.Context) {
+	workerID, _ := strconv.ParseInt(c.Param("workerID"), 10, 64)
+	ctx := c.Request.Context()
+	if err := h.AvailabilityService.ClearUnavailable(ctx, workerID, c.Param("date")); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Date available again"})
+}
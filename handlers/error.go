@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	errs "dailyworkerroster/error"
+	"dailyworkerroster/rules"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errorStatus maps known service errors to an HTTP status, falling back to
+// the given status for anything else.
+func errorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, errs.ErrInvalidRefreshToken):
+		return http.StatusUnauthorized
+	case errors.Is(err, errs.ErrShiftNotFound),
+		errors.Is(err, errs.ErrUserNotFound),
+		errors.Is(err, errs.ErrShiftTemplateNotFound),
+		errors.Is(err, errs.ErrWorkerShiftNotFound),
+		errors.Is(err, errs.ErrShiftTransferNotFound),
+		errors.Is(err, errs.ErrNoPendingRequest),
+		errors.Is(err, errs.ErrNoApprovedShift),
+		errors.Is(err, errs.ErrLocationNotFound),
+		errors.Is(err, errs.ErrSkillNotFound),
+		errors.Is(err, errs.ErrWorkerSkillNotFound),
+		errors.Is(err, errs.ErrUnavailableDateNotFound),
+		errors.Is(err, errs.ErrTimeOffNotFound),
+		errors.Is(err, errs.ErrNoOffer),
+		errors.Is(err, errs.ErrTimeEntryNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, errs.ErrShiftTransferNotAllowed):
+		return http.StatusForbidden
+	case errors.Is(err, errs.ErrShiftHasAssignments),
+		errors.Is(err, errs.ErrShiftHasOpenRequests),
+		errors.Is(err, errs.ErrHeadcountBelowFilled),
+		errors.Is(err, errs.ErrShiftTemplateInactive),
+		errors.Is(err, errs.ErrTemplateDateTaken),
+		errors.Is(err, errs.ErrShiftTransferState),
+		errors.Is(err, errs.ErrCancellationTooLate),
+		errors.Is(err, errs.ErrLocationExists),
+		errors.Is(err, errs.ErrLocationInUse),
+		errors.Is(err, errs.ErrSkillExists),
+		errors.Is(err, errs.ErrSkillInUse),
+		errors.Is(err, errs.ErrTimeOffOverlap),
+		errors.Is(err, errs.ErrTimeOffState),
+		errors.Is(err, errs.ErrRosterStale),
+		errors.Is(err, errs.ErrShiftNotOpen),
+		errors.Is(err, errs.ErrAlreadyOnShift),
+		errors.Is(err, errs.ErrShiftStarted),
+		errors.Is(err, errs.ErrNoEligibleApplicant),
+		errors.Is(err, errs.ErrNoShowNotAllowed),
+		errors.Is(err, errs.ErrOfferExpired),
+		errors.Is(err, errs.ErrTimeEntryState),
+		errors.Is(err, errs.ErrClockInNotOpen),
+		errors.Is(err, errs.ErrAlreadyClockedIn),
+		errors.Is(err, errs.ErrNotClockedIn),
+		errors.Is(err, errs.ErrBreakInProgress),
+		errors.Is(err, errs.ErrNoBreakInProgress):
+		return http.StatusConflict
+	case errors.Is(err, errs.ErrRuleViolation):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, errs.ErrInvalidHeadcount),
+		errors.Is(err, errs.ErrInvalidShiftTime),
+		errors.Is(err, errs.ErrInvalidDateOfBirth),
+		errors.Is(err, errs.ErrInvalidShiftTemplate),
+		errors.Is(err, errs.ErrInvalidShiftTransfer),
+		errors.Is(err, errs.ErrInvalidLocation),
+		errors.Is(err, errs.ErrInvalidSkill),
+		errors.Is(err, errs.ErrInvalidAvailability),
+		errors.Is(err, errs.ErrInvalidTimeOff),
+		errors.Is(err, errs.ErrInvalidRoster),
+		errors.Is(err, errs.ErrInvalidTimeEntry),
+		errors.Is(err, errs.ErrInvalidAttendanceQuery):
+		return http.StatusBadRequest
+	default:
+		return fallback
+	}
+}
+
+// errorBody is the JSON body for a failed request. Rule violations are
+// listed one by one so clients can act on their codes.
+func errorBody(err error) gin.H {
+	body := gin.H{"error": err.Error()}
+	if violations, ok := rules.AsViolations(err); ok {
+		body["violations"] = violations
+	}
+	return body
+}
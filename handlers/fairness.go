@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"dailyworkerroster/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FairnessHandler handles the ranking of shift applicants
+type FairnessHandler struct {
+	FairnessService service.FairnessServiceItf
+}
+
+// NewFairnessHandler creates a new FairnessHandler
+func NewFairnessHandler(fairnessService service.FairnessServiceItf) *FairnessHandler {
+	return &FairnessHandler{FairnessService: fairnessService}
+}
+
+// RankApplicants godoc
+// @Summary      Rank the applicants of a shift
+// @Description  Scores each pending request by hours booked in the shift's week and the period, seniority, cancellations and no-shows in the period, and request time, with the configured weights. Applicants the labour rules keep off the shift rank last.
+// @Tags         shifts
+// @Produce      json
+// @Security     BearerAuth
+// @Param        shiftID  path      int  true  "Shift ID"
+// @Success      200  {object}  model.ApplicantRanking
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/shift/{shiftID}/applicants [get]
+func (h *FairnessHandler) RankApplicants(c *gin.Context) {
+	shiftID, _ := strconv.ParseInt(c.Param("shiftID"), 10, 64)
+	ctx := c.Request.Context()
+	result, err := h.FairnessService.RankApplicants(ctx, shiftID)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// ApproveTopApplicants godoc
+// @Summary      Approve the top ranked applicants of a shift
+// @Description  Approves eligible applicants in rank order until the shift is full.
+// @Tags         shifts
+// @Produce      json
+// @Security     BearerAuth
+// @Param        shiftID  path      int  true  "Shift ID"
+// @Success      200  {array}   model.ApplicantScore
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      422  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/shift/{shiftID}/approve-top [put]
+func (h *FairnessHandler) ApproveTopApplicants(c *gin.Context) {
+	shiftID, _ := strconv.ParseInt(c.Param("shiftID"), 10, 64)
+	ctx := c.Request.Context()
+	result, err := h.FairnessService.ApproveTopApplicants(ctx, shiftID)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"dailyworkerroster/model"
+	"dailyworkerroster/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LocationHandler handles location endpoints
+type LocationHandler struct {
+	LocationService service.LocationServiceItf
+}
+
+// NewLocationHandler creates a new LocationHandler
+func NewLocationHandler(locationService service.LocationServiceItf) *LocationHandler {
+	return &LocationHandler{LocationService: locationService}
+}
+
+// CreateLocation godoc
+// @Summary      Create a location
+// @Description  Names are unique regardless of case. Shifts at the location are scheduled in its time zone, or the default zone when it has none. New locations are active unless active is false.
+// @Tags         locations
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        location  body      model.Location  true  "Location"
+// @Success      200  {object}  map[string]int64
+// @Failure      400  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/locations [post]
+func (h *LocationHandler) CreateLocation(c *gin.Context) {
+	location := model.Location{Active: true}
+	if err := c.ShouldBindJSON(&location); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx := c.Request.Context()
+	id, err := h.LocationService.CreateLocation(ctx, &location)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"id": id})
+}
+
+// GetLocations godoc
+// @Summary      List locations
+// @Tags         locations
+// @Produce      json
+// @Security     BearerAuth
+// @Param        active  query     bool  false  "Only active (true) or inactive (false) locations"
+// @Success      200  {array}   model.Location
+// @Failure      400  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/locations [get]
+func (h *LocationHandler) GetLocations(c *gin.Context) {
+	var active *bool
+	if value := c.Query("active"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid active"})
+			return
+		}
+		active = &parsed
+	}
+	ctx := c.Request.Context()
+	result, err := h.LocationService.GetLocations(ctx, active)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetLocationByID godoc
+// @Summary      Get a location
+// @Tags         locations
+// @Produce      json
+// @Security     BearerAuth
+// @Param        locationID  path      int  true  "Location ID"
+// @Success      200  {object}  model.Location
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /admin/locations/{locationID} [get]
+func (h *LocationHandler) GetLocationByID(c *gin.Context) {
+	locationID, err := strconv.ParseInt(c.Param("locationID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid location id"})
+		return
+	}
+	ctx := c.Request.Context()
+	result, err := h.LocationService.GetLocationByID(ctx, locationID)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// UpdateLocation godoc
+// @Summary      Update a location
+// @Description  Fields left out keep their value. Upcoming shifts keep their date and clock times, so a new time zone moves their start and end instants. Deactivating a location keeps its shifts but allows no new ones.
+// @Tags         locations
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        locationID  path      int             true  "Location ID"
+// @Param        location    body      model.Location  true  "Location"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/locations/{locationID} [put]
+func (h *LocationHandler) UpdateLocation(c *gin.Context) {
+	locationID, err := strconv.ParseInt(c.Param("locationID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid location id"})
+		return
+	}
+	ctx := c.Request.Context()
+	location, err := h.LocationService.GetLocationByID(ctx, locationID)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	if err := c.ShouldBindJSON(location); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	location.ID = locationID
+
+	if err := h.LocationService.UpdateLocation(ctx, location); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Location updated"})
+}
+
+// DeleteLocation godoc
+// @Summary      Delete a location
+// @Description  Locations used by shifts or templates cannot be deleted; deactivate them instead.
+// @Tags         locations
+// @Produce      json
+// @Security     BearerAuth
+// @Param        locationID  path      int  true  "Location ID"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/locations/{locationID} [delete]
+func (h *LocationHandler) DeleteLocation(c *gin.Context) {
+	locationID, err := strconv.ParseInt(c.Param("locationID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid location id"})
+		return
+	}
+	ctx := c.Request.Context()
+	if err := h.LocationService.DeleteLocation(ctx, locationID); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Location deleted"})
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"dailyworkerroster/model"
+	"dailyworkerroster/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RosterHandler handles automatic rostering
+type RosterHandler struct {
+	RosterService service.RosterServiceItf
+}
+
+// NewRosterHandler creates a new RosterHandler
+func NewRosterHandler(rosterService service.RosterServiceItf) *RosterHandler {
+	return &RosterHandler{RosterService: rosterService}
+}
+
+// ProposeRoster godoc
+// @Summary      Preview a roster for a date range
+// @Description  Picks the pending requests to approve so that the open shifts in the range get as many places filled as a greedy fill with single worker moves finds, within the labour rules and the workers' availability. The result is good, not guaranteed optimal. Nothing is saved; commit the approve list to apply it.
+// @Tags         roster
+// @Produce      json
+// @Security     BearerAuth
+// @Param        from         query     string  true   "First day (YYYY-MM-DD)"
+// @Param        to           query     string  true   "Last day (YYYY-MM-DD), at most 31 days after from"
+// @Param        location_id  query     int     false  "Only shifts at this location"
+// @Success      200  {object}  model.RosterProposal
+// @Failure      400  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/roster/proposal [get]
+func (h *RosterHandler) ProposeRoster(c *gin.Context) {
+	query := model.RosterQuery{DateFrom: c.Query("from"), DateTo: c.Query("to")}
+	if location := c.Query("location_id"); location != "" {
+		locationID, err := strconv.ParseInt(location, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid location_id"})
+			return
+		}
+		query.LocationID = &locationID
+	}
+	ctx := c.Request.Context()
+	result, err := h.RosterService.ProposeRoster(ctx, query)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// CommitRosterRequest is the body of a roster commit
+type CommitRosterRequest struct {
+	Approve []int64 `json:"approve" binding:"required"` // worker shift IDs, the approve list of a proposal
+}
+
+// CommitRoster godoc
+// @Summary      Commit a roster proposal
+// @Description  Approves the requests in one transaction; when any of them can no longer be approved, none is. Requests left over on shifts that fill up are waitlisted.
+// @Tags         roster
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        roster  body      CommitRosterRequest  true  "Requests to approve"
+// @Success      200  {array}   model.RosterChange
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      422  {object}  map[string]interface{}
+// @Router       /admin/roster/commit [post]
+func (h *RosterHandler) CommitRoster(c *gin.Context) {
+	var req CommitRosterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx := c.Request.Context()
+	result, err := h.RosterService.CommitRoster(ctx, req.Approve)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusConflict), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
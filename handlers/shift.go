@@ -40,6 +40,7 @@ func (h *ShiftHandler) GetAssignedShifts(c *gin.Context) {
 
 // GetAvailableShifts godoc
 // @Summary      Get available shifts for a worker
+// @Description  Shifts that have started are left out. Shifts clashing with the worker's availability are left out unless the worker has requested them. Shifts matching more of the worker's preferred locations and roles come first.
 // @Tags         shifts
 // @Produce      json
 // @Security     BearerAuth
@@ -60,6 +61,7 @@ func (h *ShiftHandler) GetAvailableShifts(c *gin.Context) {
 
 // RequestShift godoc
 // @Summary      Request a shift for a worker
+// @Description  Only open shifts that have not started yet can be requested.
 // @Tags         shifts
 // @Produce      json
 // @Security     BearerAuth
@@ -67,6 +69,9 @@ func (h *ShiftHandler) GetAvailableShifts(c *gin.Context) {
 // @Param        workerID  path      int  true  "Worker ID"
 // @Success      200  {object}  map[string]string
 // @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      422  {object}  map[string]interface{}
 // @Router       /shift/{shiftID}/request/{workerID} [post]
 func (h *ShiftHandler) RequestShift(c *gin.Context) {
 	shiftID, _ := strconv.ParseInt(c.Param("shiftID"), 10, 64)
@@ -74,12 +79,127 @@ func (h *ShiftHandler) RequestShift(c *gin.Context) {
 	ctx := c.Request.Context()
 	err := h.ShiftService.RequestShift(ctx, shiftID, workerID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err, http.StatusBadRequest), errorBody(err))
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Shift request submitted"})
 }
 
+// WithdrawShiftRequest godoc
+// @Summary      Withdraw a pending or waitlisted shift request
+// @Tags         shifts
+// @Produce      json
+// @Security     BearerAuth
+// @Param        shiftID   path      int  true  "Shift ID"
+// @Param        workerID  path      int  true  "Worker ID"
+// @Success      200  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /shift/{shiftID}/withdraw/{workerID} [post]
+func (h *ShiftHandler) WithdrawShiftRequest(c *gin.Context) {
+	shiftID, _ := strconv.ParseInt(c.Param("shiftID"), 10, 64)
+	workerID, _ := strconv.ParseInt(c.Param("workerID"), 10, 64)
+	ctx := c.Request.Context()
+	err := h.ShiftService.WithdrawShiftRequest(ctx, shiftID, workerID)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Shift request withdrawn"})
+}
+
+// CancelShift godoc
+// @Summary      Cancel an approved shift
+// @Description  Allowed until the configured notice period before the shift starts. The place is offered to the next eligible worker on the waitlist, or the shift becomes available again.
+// @Tags         shifts
+// @Produce      json
+// @Security     BearerAuth
+// @Param        shiftID   path      int  true  "Shift ID"
+// @Param        workerID  path      int  true  "Worker ID"
+// @Success      200  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Router       /shift/{shiftID}/cancel/{workerID} [post]
+func (h *ShiftHandler) CancelShift(c *gin.Context) {
+	shiftID, _ := strconv.ParseInt(c.Param("shiftID"), 10, 64)
+	workerID, _ := strconv.ParseInt(c.Param("workerID"), 10, 64)
+	ctx := c.Request.Context()
+	err := h.ShiftService.CancelShift(ctx, shiftID, workerID)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Shift cancelled"})
+}
+
+// AcceptOffer godoc
+// @Summary      Accept a place offered from the waitlist
+// @Description  Allowed until the offer expires or the shift starts, as long as the worker still passes the labour rules.
+// @Tags         shifts
+// @Produce      json
+// @Security     BearerAuth
+// @Param        shiftID   path      int  true  "Shift ID"
+// @Param        workerID  path      int  true  "Worker ID"
+// @Success      200  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      422  {object}  map[string]interface{}
+// @Router       /shift/{shiftID}/accept-offer/{workerID} [post]
+func (h *ShiftHandler) AcceptOffer(c *gin.Context) {
+	shiftID, _ := strconv.ParseInt(c.Param("shiftID"), 10, 64)
+	workerID, _ := strconv.ParseInt(c.Param("workerID"), 10, 64)
+	ctx := c.Request.Context()
+	err := h.ShiftService.AcceptOffer(ctx, shiftID, workerID)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Offer accepted"})
+}
+
+// DeclineOffer godoc
+// @Summary      Decline a place offered from the waitlist
+// @Description  The place is offered to the next eligible worker on the waitlist.
+// @Tags         shifts
+// @Produce      json
+// @Security     BearerAuth
+// @Param        shiftID   path      int  true  "Shift ID"
+// @Param        workerID  path      int  true  "Worker ID"
+// @Success      200  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /shift/{shiftID}/decline-offer/{workerID} [post]
+func (h *ShiftHandler) DeclineOffer(c *gin.Context) {
+	shiftID, _ := strconv.ParseInt(c.Param("shiftID"), 10, 64)
+	workerID, _ := strconv.ParseInt(c.Param("workerID"), 10, 64)
+	ctx := c.Request.Context()
+	err := h.ShiftService.DeclineOffer(ctx, shiftID, workerID)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Offer declined"})
+}
+
+// GetWorkerHours godoc
+// @Summary      Get a worker's booked hours
+// @Description  Hours booked and pending in the current and next week, against the default weekly cap.
+// @Tags         shifts
+// @Produce      json
+// @Security     BearerAuth
+// @Param        workerID  path      int  true  "Worker ID"
+// @Success      200  {object}  model.WorkerHours
+// @Failure      500  {object}  map[string]string
+// @Router       /worker/hours/{workerID} [get]
+func (h *ShiftHandler) GetWorkerHours(c *gin.Context) {
+	workerID, _ := strconv.ParseInt(c.Param("workerID"), 10, 64)
+	ctx := c.Request.Context()
+	result, err := h.ShiftService.GetWorkerHours(ctx, workerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
 // GetAllRequestedShifts godoc
 // @Summary      Get all requested shifts for a worker
 // @Tags         shifts
@@ -102,6 +222,7 @@ func (h *ShiftHandler) GetAllRequestedShifts(c *gin.Context) {
 
 // CreateShift godoc
 // @Summary      Create a new shift
+// @Description  The shift refers to an active location by location_id, or by its name. It is open for requests unless isAvailable is false.
 // @Tags         shifts
 // @Accept       json
 // @Produce      json
@@ -112,40 +233,192 @@ func (h *ShiftHandler) GetAllRequestedShifts(c *gin.Context) {
 // @Failure      500  {object}  map[string]string
 // @Router       /admin/shift [post]
 func (h *ShiftHandler) CreateShift(c *gin.Context) {
-	var shift model.Shift
-	if err := c.ShouldBindJSON(&shift); err != nil {
+	var req shiftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	shift := req.Shift
+	shift.IsAvailable = req.IsAvailable == nil || *req.IsAvailable
 	ctx := c.Request.Context()
 	id, err := h.ShiftService.CreateShift(ctx, &shift)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"id": id})
 }
 
+// shiftRequest is the body of a shift create or update. It tells an
+// isAvailable left out from an explicit false.
+type shiftRequest struct {
+	model.Shift
+	IsAvailable *bool `json:"isAvailable"`
+}
+
+// UpdateShift godoc
+// @Summary      Update a shift
+// @Description  Once a worker holds or held a place only the headcount and availability may change, and the headcount not below the places taken. isAvailable opens or closes the shift for requests while it has free places; left out, it is kept.
+// @Tags         shifts
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        shiftID  path      int          true  "Shift ID"
+// @Param        shift    body      model.Shift  true  "Shift"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/shift/{shiftID} [put]
+func (h *ShiftHandler) UpdateShift(c *gin.Context) {
+	shiftID, err := strconv.ParseInt(c.Param("shiftID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid shift id"})
+		return
+	}
+	var req shiftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	shift := req.Shift
+	shift.ID = shiftID
+	ctx := c.Request.Context()
+	if err := h.ShiftService.UpdateShift(ctx, &shift, req.IsAvailable); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Shift updated"})
+}
+
+// DeleteShift godoc
+// @Summary      Delete a shift
+// @Description  Rejected, withdrawn and expired requests are deleted with the shift. A shift with approved, offered, done, no-show or cancelled workers cannot be deleted, and pending or waitlisted requests must be rejected first.
+// @Tags         shifts
+// @Produce      json
+// @Security     BearerAuth
+// @Param        shiftID  path      int  true  "Shift ID"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/shift/{shiftID} [delete]
+func (h *ShiftHandler) DeleteShift(c *gin.Context) {
+	shiftID, err := strconv.ParseInt(c.Param("shiftID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid shift id"})
+		return
+	}
+	ctx := c.Request.Context()
+	if err := h.ShiftService.DeleteShift(ctx, shiftID); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Shift deleted"})
+}
+
+// GetAllShiftRequests godoc
+// @Summary      List shift requests
+// @Description  Pending requests list their conflicts with the worker's availability.
+// @Tags         shifts
+// @Produce      json
+// @Security     BearerAuth
+// @Param        status              query     string  false  "Request status (PENDING, APPROVED, REJECTED, ...)"
+// @Param        role                query     string  false  "Shift role assignment"
+// @Param        location_id         query     int     false  "Shift location ID"
+// @Param        worker              query     int     false  "Worker ID"
+// @Param        needs_reassignment  query     bool    false  "Only shifts flagged because of leave approved later (true), or only the others (false)"
+// @Param        limit               query     int     false  "Page size (default 50)"
+// @Param        offset              query     int     false  "Page offset"
+// @Success      200  {array}   model.WorkerShiftDetail
+// @Failure      400  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/requests [get]
+func (h *ShiftHandler) GetAllShiftRequests(c *gin.Context) {
+	var queryParam model.WorkerShiftDetailQuery
+	if status := c.Query("status"); status != "" {
+		queryParam.Status = &status
+	}
+	if role := c.Query("role"); role != "" {
+		queryParam.Role = &role
+	}
+	if location := c.Query("location_id"); location != "" {
+		locationID, err := strconv.ParseInt(location, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid location_id"})
+			return
+		}
+		queryParam.LocationID = &locationID
+	}
+	if worker := c.Query("worker"); worker != "" {
+		workerID, err := strconv.ParseInt(worker, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid worker"})
+			return
+		}
+		queryParam.UserAccountID = &workerID
+	}
+	if value := c.Query("needs_reassignment"); value != "" {
+		needsReassignment, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid needs_reassignment"})
+			return
+		}
+		queryParam.NeedsReassignment = &needsReassignment
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid offset"})
+		return
+	}
+	queryParam.Limit = &limit
+	queryParam.Offset = &offset
+
+	ctx := c.Request.Context()
+	result, err := h.ShiftService.GetAllShiftRequests(ctx, queryParam)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
 // ApproveShiftRequest godoc
 // @Summary      Approve a shift request for a worker
+// @Description  Only requests for shifts that have not started yet can be approved. Approving a shift outside the worker's availability is allowed; availability_conflicts lists the clashes.
 // @Tags         shifts
 // @Produce      json
 // @Security     BearerAuth
 // @Param        shiftID   path      int  true  "Shift ID"
 // @Param        workerID  path      int  true  "Worker ID"
-// @Success      200  {object}  map[string]string
+// @Success      200  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      422  {object}  map[string]interface{}
 // @Failure      500  {object}  map[string]string
 // @Router       /admin/shift/{shiftID}/approve/{workerID} [put]
 func (h *ShiftHandler) ApproveShiftRequest(c *gin.Context) {
 	shiftID, _ := strconv.ParseInt(c.Param("shiftID"), 10, 64)
 	workerID, _ := strconv.ParseInt(c.Param("workerID"), 10, 64)
 	ctx := c.Request.Context()
-	err := h.ShiftService.ApproveShiftRequest(ctx, shiftID, workerID)
+	conflicts, err := h.ShiftService.ApproveShiftRequest(ctx, shiftID, workerID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err, http.StatusInternalServerError), errorBody(err))
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Shift approved"})
+	body := gin.H{"message": "Shift approved"}
+	if len(conflicts) > 0 {
+		body["availability_conflicts"] = conflicts
+	}
+	c.JSON(http.StatusOK, body)
 }
 
 // RejectShiftRequest godoc
@@ -156,6 +429,7 @@ func (h *ShiftHandler) ApproveShiftRequest(c *gin.Context) {
 // @Param        shiftID   path      int  true  "Shift ID"
 // @Param        workerID  path      int  true  "Worker ID"
 // @Success      200  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
 // @Failure      500  {object}  map[string]string
 // @Router       /admin/shift/{shiftID}/reject/{workerID} [put]
 func (h *ShiftHandler) RejectShiftRequest(c *gin.Context) {
@@ -164,12 +438,34 @@ func (h *ShiftHandler) RejectShiftRequest(c *gin.Context) {
 	ctx := c.Request.Context()
 	err := h.ShiftService.RejectShiftRequest(ctx, shiftID, workerID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Shift rejected"})
 }
 
+// MarkNoShow godoc
+// @Summary      Mark an assignment as a no-show
+// @Description  For an approved or done shift that has started. It no longer counts as hours worked and counts against the worker when applicants are ranked.
+// @Tags         shifts
+// @Produce      json
+// @Security     BearerAuth
+// @Param        workerShiftID  path      int  true  "Worker shift ID"
+// @Success      200  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/worker-shift/{workerShiftID}/no-show [put]
+func (h *ShiftHandler) MarkNoShow(c *gin.Context) {
+	workerShiftID, _ := strconv.ParseInt(c.Param("workerShiftID"), 10, 64)
+	ctx := c.Request.Context()
+	if err := h.ShiftService.MarkNoShow(ctx, workerShiftID); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Marked as no-show"})
+}
+
 // GetShiftsByDay godoc
 // @Summary      Get all shifts by date
 // @Tags         shifts
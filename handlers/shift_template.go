@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"dailyworkerroster/model"
+	"dailyworkerroster/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ShiftTemplateHandler handles recurring shift template endpoints
+type ShiftTemplateHandler struct {
+	ShiftTemplateService service.ShiftTemplateServiceItf
+}
+
+// NewShiftTemplateHandler creates a new ShiftTemplateHandler
+func NewShiftTemplateHandler(shiftTemplateService service.ShiftTemplateServiceItf) *ShiftTemplateHandler {
+	return &ShiftTemplateHandler{ShiftTemplateService: shiftTemplateService}
+}
+
+// CreateTemplate godoc
+// @Summary      Create a recurring shift template
+// @Description  The template refers to an active location by location_id, or by its name.
+// @Tags         shift-templates
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        template  body      model.ShiftTemplate  true  "Shift template"
+// @Success      200  {object}  map[string]int64
+// @Failure      400  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/shift-templates [post]
+func (h *ShiftTemplateHandler) CreateTemplate(c *gin.Context) {
+	tpl := model.ShiftTemplate{IsActive: true}
+	if err := c.ShouldBindJSON(&tpl); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx := c.Request.Context()
+	id, err := h.ShiftTemplateService.CreateTemplate(ctx, &tpl)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"id": id})
+}
+
+// GetTemplates godoc
+// @Summary      List shift templates
+// @Tags         shift-templates
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   model.ShiftTemplate
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/shift-templates [get]
+func (h *ShiftTemplateHandler) GetTemplates(c *gin.Context) {
+	ctx := c.Request.Context()
+	result, err := h.ShiftTemplateService.GetTemplates(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetTemplateByID godoc
+// @Summary      Get a shift template
+// @Tags         shift-templates
+// @Produce      json
+// @Security     BearerAuth
+// @Param        templateID  path      int  true  "Template ID"
+// @Success      200  {object}  model.ShiftTemplate
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /admin/shift-templates/{templateID} [get]
+func (h *ShiftTemplateHandler) GetTemplateByID(c *gin.Context) {
+	templateID, err := strconv.ParseInt(c.Param("templateID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid template id"})
+		return
+	}
+	ctx := c.Request.Context()
+	result, err := h.ShiftTemplateService.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// UpdateTemplate godoc
+// @Summary      Update a shift template
+// @Description  Fields missing from the body keep their current value. future controls what happens to generated shifts that have not started: keep (default), update (carry the changes over, except to fields and shifts an admin edited or closed by hand) or replace (delete and regenerate). Shifts that workers hold or held a place on are never moved or deleted, shifts with pending or waitlisted requests are never deleted; both are reported as conflicts.
+// @Tags         shift-templates
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        templateID  path      int                  true   "Template ID"
+// @Param        future      query     string               false  "keep, update or replace"
+// @Param        template    body      model.ShiftTemplate  true   "Shift template"
+// @Success      200  {object}  model.ShiftGenerationResult
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/shift-templates/{templateID} [put]
+func (h *ShiftTemplateHandler) UpdateTemplate(c *gin.Context) {
+	templateID, err := strconv.ParseInt(c.Param("templateID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid template id"})
+		return
+	}
+	ctx := c.Request.Context()
+	tpl, err := h.ShiftTemplateService.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	if err := c.ShouldBindJSON(tpl); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	tpl.ID = templateID
+
+	result, err := h.ShiftTemplateService.UpdateTemplate(ctx, tpl, c.Query("future"))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GenerateShifts godoc
+// @Summary      Generate shifts from a template
+// @Description  Materialises the template from today up to and including until (default 28 days ahead). Shifts that would already have started are left out. Re-running it never creates duplicates nor touches shifts generated before.
+// @Tags         shift-templates
+// @Produce      json
+// @Security     BearerAuth
+// @Param        templateID  path      int     true   "Template ID"
+// @Param        until       query     string  false  "Last date to generate (YYYY-MM-DD)"
+// @Success      200  {object}  model.ShiftGenerationResult
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/shift-templates/{templateID}/generate [post]
+func (h *ShiftTemplateHandler) GenerateShifts(c *gin.Context) {
+	templateID, err := strconv.ParseInt(c.Param("templateID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid template id"})
+		return
+	}
+	ctx := c.Request.Context()
+	result, err := h.ShiftTemplateService.GenerateShifts(ctx, templateID, c.Query("until"))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"dailyworkerroster/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ShiftTransferHandler handles shift swap and give-away endpoints
+type ShiftTransferHandler struct {
+	ShiftTransferService service.ShiftTransferServiceItf
+}
+
+// NewShiftTransferHandler creates a new ShiftTransferHandler
+func NewShiftTransferHandler(shiftTransferService service.ShiftTransferServiceItf) *ShiftTransferHandler {
+	return &ShiftTransferHandler{ShiftTransferService: shiftTransferService}
+}
+
+// OfferShiftRequest is the body of an offer; leave to_user_id empty to offer
+// the shift to any worker.
+type OfferShiftRequest struct {
+	ToUserID *int64 `json:"to_user_id"`
+}
+
+// OfferShift godoc
+// @Summary      Offer an approved shift to a colleague or to anyone
+// @Tags         transfers
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        workerShiftID  path      int                 true   "Worker shift ID"
+// @Param        workerID       path      int                 true   "Worker ID"
+// @Param        offer          body      OfferShiftRequest   false  "Offer"
+// @Success      200  {object}  map[string]int64
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      422  {object}  map[string]interface{}
+// @Router       /worker-shift/{workerShiftID}/offer/{workerID} [post]
+func (h *ShiftTransferHandler) OfferShift(c *gin.Context) {
+	workerShiftID, _ := strconv.ParseInt(c.Param("workerShiftID"), 10, 64)
+	workerID, _ := strconv.ParseInt(c.Param("workerID"), 10, 64)
+
+	var req OfferShiftRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	id, err := h.ShiftTransferService.OfferShift(ctx, workerShiftID, workerID, req.ToUserID)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"id": id})
+}
+
+// GetWorkerTransfers godoc
+// @Summary      List a worker's own offers and the offers they can accept
+// @Tags         transfers
+// @Produce      json
+// @Security     BearerAuth
+// @Param        workerID  path      int  true  "Worker ID"
+// @Success      200  {object}  model.WorkerTransfers
+// @Failure      500  {object}  map[string]string
+// @Router       /worker/transfers/{workerID} [get]
+func (h *ShiftTransferHandler) GetWorkerTransfers(c *gin.Context) {
+	workerID, _ := strconv.ParseInt(c.Param("workerID"), 10, 64)
+	ctx := c.Request.Context()
+	result, err := h.ShiftTransferService.GetWorkerTransfers(ctx, workerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// AcceptTransfer godoc
+// @Summary      Accept an offered shift
+// @Tags         transfers
+// @Produce      json
+// @Security     BearerAuth
+// @Param        transferID  path      int  true  "Transfer ID"
+// @Param        workerID    path      int  true  "Worker ID"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      422  {object}  map[string]interface{}
+// @Router       /transfer/{transferID}/accept/{workerID} [post]
+func (h *ShiftTransferHandler) AcceptTransfer(c *gin.Context) {
+	transferID, _ := strconv.ParseInt(c.Param("transferID"), 10, 64)
+	workerID, _ := strconv.ParseInt(c.Param("workerID"), 10, 64)
+	ctx := c.Request.Context()
+	err := h.ShiftTransferService.AcceptTransfer(ctx, transferID, workerID)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Transfer accepted, waiting for approval"})
+}
+
+// CancelTransfer godoc
+// @Summary      Withdraw a shift offer
+// @Tags         transfers
+// @Produce      json
+// @Security     BearerAuth
+// @Param        transferID  path      int  true  "Transfer ID"
+// @Param        workerID    path      int  true  "Worker ID"
+// @Success      200  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Router       /transfer/{transferID}/cancel/{workerID} [post]
+func (h *ShiftTransferHandler) CancelTransfer(c *gin.Context) {
+	transferID, _ := strconv.ParseInt(c.Param("transferID"), 10, 64)
+	workerID, _ := strconv.ParseInt(c.Param("workerID"), 10, 64)
+	ctx := c.Request.Context()
+	err := h.ShiftTransferService.CancelTransfer(ctx, transferID, workerID)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Transfer cancelled"})
+}
+
+// GetTransfers godoc
+// @Summary      List shift transfers
+// @Tags         transfers
+// @Produce      json
+// @Security     BearerAuth
+// @Param        status  query     string  false  "OFFERED, ACCEPTED, APPROVED, REJECTED or CANCELLED"
+// @Success      200  {array}   model.ShiftTransferDetail
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/transfers [get]
+func (h *ShiftTransferHandler) GetTransfers(c *gin.Context) {
+	var status *string
+	if s := c.Query("status"); s != "" {
+		status = &s
+	}
+	ctx := c.Request.Context()
+	result, err := h.ShiftTransferService.GetTransfers(ctx, status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// ApproveTransfer godoc
+// @Summary      Approve an accepted transfer and reassign the shift
+// @Tags         transfers
+// @Produce      json
+// @Security     BearerAuth
+// @Param        transferID  path      int  true  "Transfer ID"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      422  {object}  map[string]interface{}
+// @Router       /admin/transfer/{transferID}/approve [put]
+func (h *ShiftTransferHandler) ApproveTransfer(c *gin.Context) {
+	transferID, _ := strconv.ParseInt(c.Param("transferID"), 10, 64)
+	ctx := c.Request.Context()
+	err := h.ShiftTransferService.ApproveTransfer(ctx, transferID)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), errorBody(err))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Transfer approved"})
+}
+
+// RejectTransfer godoc
+// @Summary      Reject a shift transfer
+// @Tags         transfers
+// @Produce      json
+// @Security     BearerAuth
+// @Param        transferID  path      int  true  "Transfer ID"
+// @Success      200  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Router       /admin/transfer/{transferID}/reject [put]
+func (h *ShiftTransferHandler) RejectTransfer(c *gin.Context) {
+	transferID, _ := strconv.ParseInt(c.Param("transferID"), 10, 64)
+	ctx := c.Request.Context()
+	err := h.ShiftTransferService.RejectTransfer(ctx, transferID)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Transfer rejected"})
+}
+
+// GetWorkerShiftHistory godoc
+// @Summary      Get the reassignment history of a worker shift
+// @Tags         transfers
+// @Produce      json
+// @Security     BearerAuth
+// @Param        workerShiftID  path      int  true  "Worker shift ID"
+// @Success      200  {array}   model.WorkerShiftHistory
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/worker-shift/{workerShiftID}/history [get]
+func (h *ShiftTransferHandler) GetWorkerShiftHistory(c *gin.Context) {
+	workerShiftID, _ := strconv.ParseInt(c.Param("workerShiftID"), 10, 64)
+	ctx := c.Request.Context()
+	result, err := h.ShiftTransferService.GetWorkerShiftHistory(ctx, workerShiftID)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
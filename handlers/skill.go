@@ -0,0 +1,269 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"dailyworkerroster/model"
+	"dailyworkerroster/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SkillHandler handles the skill catalogue and worker qualifications
+type SkillHandler struct {
+	SkillService service.SkillServiceItf
+}
+
+// NewSkillHandler creates a new SkillHandler
+func NewSkillHandler(skillService service.SkillServiceItf) *SkillHandler {
+	return &SkillHandler{SkillService: skillService}
+}
+
+// CreateSkill godoc
+// @Summary      Create a skill
+// @Description  The code is what shifts name as their role_assignment. New skills are active unless active is false.
+// @Tags         skills
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        skill  body      model.Skill  true  "Skill"
+// @Success      200  {object}  map[string]int64
+// @Failure      400  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/skills [post]
+func (h *SkillHandler) CreateSkill(c *gin.Context) {
+	skill := model.Skill{Active: true}
+	if err := c.ShouldBindJSON(&skill); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx := c.Request.Context()
+	id, err := h.SkillService.CreateSkill(ctx, &skill)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"id": id})
+}
+
+// GetSkills godoc
+// @Summary      List skills
+// @Tags         skills
+// @Produce      json
+// @Security     BearerAuth
+// @Param        active  query     bool  false  "Only active (true) or inactive (false) skills"
+// @Success      200  {array}   model.Skill
+// @Failure      400  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/skills [get]
+func (h *SkillHandler) GetSkills(c *gin.Context) {
+	var active *bool
+	if value := c.Query("active"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid active"})
+			return
+		}
+		active = &parsed
+	}
+	ctx := c.Request.Context()
+	result, err := h.SkillService.GetSkills(ctx, active)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetSkillByID godoc
+// @Summary      Get a skill
+// @Tags         skills
+// @Produce      json
+// @Security     BearerAuth
+// @Param        skillID  path      int  true  "Skill ID"
+// @Success      200  {object}  model.Skill
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /admin/skills/{skillID} [get]
+func (h *SkillHandler) GetSkillByID(c *gin.Context) {
+	skillID, err := strconv.ParseInt(c.Param("skillID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid skill id"})
+		return
+	}
+	ctx := c.Request.Context()
+	result, err := h.SkillService.GetSkillByID(ctx, skillID)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// UpdateSkill godoc
+// @Summary      Update a skill
+// @Description  Fields left out keep their value; the code cannot change. Deactivating a skill keeps its shifts and qualified workers but allows no new shifts or templates with it.
+// @Tags         skills
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        skillID  path      int          true  "Skill ID"
+// @Param        skill    body      model.Skill  true  "Skill"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/skills/{skillID} [put]
+func (h *SkillHandler) UpdateSkill(c *gin.Context) {
+	skillID, err := strconv.ParseInt(c.Param("skillID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid skill id"})
+		return
+	}
+	ctx := c.Request.Context()
+	skill, err := h.SkillService.GetSkillByID(ctx, skillID)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	if err := c.ShouldBindJSON(skill); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	skill.ID = skillID
+
+	if err := h.SkillService.UpdateSkill(ctx, skill); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Skill updated"})
+}
+
+// DeleteSkill godoc
+// @Summary      Delete a skill
+// @Description  Skills required by shifts or templates cannot be deleted; deactivate them instead. Workers lose their qualification for it.
+// @Tags         skills
+// @Produce      json
+// @Security     BearerAuth
+// @Param        skillID  path      int  true  "Skill ID"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/skills/{skillID} [delete]
+func (h *SkillHandler) DeleteSkill(c *gin.Context) {
+	skillID, err := strconv.ParseInt(c.Param("skillID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid skill id"})
+		return
+	}
+	ctx := c.Request.Context()
+	if err := h.SkillService.DeleteSkill(ctx, skillID); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Skill deleted"})
+}
+
+// GetWorkerSkills godoc
+// @Summary      Get a worker's skills
+// @Tags         skills
+// @Produce      json
+// @Security     BearerAuth
+// @Param        workerID  path      int  true  "Worker ID"
+// @Success      200  {array}   model.WorkerSkill
+// @Failure      500  {object}  map[string]string
+// @Router       /worker/skills/{workerID} [get]
+func (h *SkillHandler) GetWorkerSkills(c *gin.Context) {
+	workerID, _ := strconv.ParseInt(c.Param("workerID"), 10, 64)
+	ctx := c.Request.Context()
+	result, err := h.SkillService.GetWorkerSkills(ctx, workerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// WorkerSkillRequest is the body of a qualification; leave expires_on empty
+// for one that never expires.
+type WorkerSkillRequest struct {
+	ExpiresOn *string `json:"expires_on"` // YYYY-MM-DD, last valid day
+}
+
+// SetWorkerSkill godoc
+// @Summary      Qualify a worker for a skill
+// @Description  Grants the skill, or replaces the expiry of a skill the worker already has. Workers may only request and be given shifts whose role they hold on the shift's date.
+// @Tags         skills
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        userID   path      int                 true   "Worker ID"
+// @Param        skillID  path      int                 true   "Skill ID"
+// @Param        skill    body      WorkerSkillRequest  false  "Qualification"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/user/{userID}/skills/{skillID} [put]
+func (h *SkillHandler) SetWorkerSkill(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("userID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+	skillID, err := strconv.ParseInt(c.Param("skillID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid skill id"})
+		return
+	}
+	var req WorkerSkillRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	ctx := c.Request.Context()
+	workerSkill := &model.WorkerSkill{UserAccountID: userID, SkillID: skillID, ExpiresOn: req.ExpiresOn}
+	if err := h.SkillService.SetWorkerSkill(ctx, workerSkill); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Skill granted"})
+}
+
+// RemoveWorkerSkill godoc
+// @Summary      Remove a skill from a worker
+// @Description  Shifts the worker already holds are kept.
+// @Tags         skills
+// @Produce      json
+// @Security     BearerAuth
+// @Param        userID   path      int  true  "Worker ID"
+// @Param        skillID  path      int  true  "Skill ID"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/user/{userID}/skills/{skillID} [delete]
+func (h *SkillHandler) RemoveWorkerSkill(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("userID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+	skillID, err := strconv.ParseInt(c.Param("skillID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid skill id"})
+		return
+	}
+	ctx := c.Request.Context()
+	if err := h.SkillService.RemoveWorkerSkill(ctx, userID, skillID); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Skill removed"})
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"dailyworkerroster/model"
+	"dailyworkerroster/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TimeOffHandler handles leave requests
+type TimeOffHandler struct {
+	TimeOffService service.TimeOffServiceItf
+}
+
+// NewTimeOffHandler creates a new TimeOffHandler
+func NewTimeOffHandler(timeOffService service.TimeOffServiceItf) *TimeOffHandler {
+	return &TimeOffHandler{TimeOffService: timeOffService}
+}
+
+// TimeOffRequest is the body of a leave request
+type TimeOffRequest struct {
+	Type      string `json:"type" binding:"required"`       // VACATION, SICK, PERSONAL or UNPAID
+	StartDate string `json:"start_date" binding:"required"` // YYYY-MM-DD
+	EndDate   string `json:"end_date" binding:"required"`   // YYYY-MM-DD, last day of the leave
+	Reason    string `json:"reason"`
+}
+
+// RequestTimeOff godoc
+// @Summary      Request time off
+// @Description  The leave waits for an admin's approval. It may not overlap the worker's pending or approved leave.
+// @Tags         time-off
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        workerID  path      int             true  "Worker ID"
+// @Param        timeOff   body      TimeOffRequest  true  "Time off"
+// @Success      200  {object}  map[string]int64
+// @Failure      400  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /worker/time-off/{workerID} [post]
+func (h *TimeOffHandler) RequestTimeOff(c *gin.Context) {
+	workerID, _ := strconv.ParseInt(c.Param("workerID"), 10, 64)
+	var req TimeOffRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx := c.Request.Context()
+	timeOff := &model.TimeOff{
+		UserAccountID: workerID,
+		Type:          req.Type,
+		StartDate:     req.StartDate,
+		EndDate:       req.EndDate,
+		Reason:        req.Reason,
+	}
+	id, err := h.TimeOffService.RequestTimeOff(ctx, timeOff)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"id": id})
+}
+
+// GetWorkerTimeOff godoc
+// @Summary      List a worker's time off
+// @Tags         time-off
+// @Produce      json
+// @Security     BearerAuth
+// @Param        workerID  path      int  true  "Worker ID"
+// @Success      200  {array}   model.TimeOff
+// @Failure      500  {object}  map[string]string
+// @Router       /worker/time-off/{workerID} [get]
+func (h *TimeOffHandler) GetWorkerTimeOff(c *gin.Context) {
+	workerID, _ := strconv.ParseInt(c.Param("workerID"), 10, 64)
+	ctx := c.Request.Context()
+	result, err := h.TimeOffService.GetWorkerTimeOff(ctx, workerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetTimeOffRequests godoc
+// @Summary      List time off
+// @Tags         time-off
+// @Produce      json
+// @Security     BearerAuth
+// @Param        status  query     string  false  "PENDING, APPROVED or REJECTED"
+// @Param        worker  query     int     false  "Worker ID"
+// @Param        from    query     string  false  "Only leave ending on or after this date (YYYY-MM-DD)"
+// @Param        to      query     string  false  "Only leave starting on or before this date (YYYY-MM-DD)"
+// @Success      200  {array}   model.TimeOff
+// @Failure      400  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/time-off [get]
+func (h *TimeOffHandler) GetTimeOffRequests(c *gin.Context) {
+	var queryParam model.TimeOffQuery
+	if status := c.Query("status"); status != "" {
+		queryParam.Status = &status
+	}
+	if worker := c.Query("worker"); worker != "" {
+		workerID, err := strconv.ParseInt(worker, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid worker"})
+			return
+		}
+		queryParam.UserAccountID = &workerID
+	}
+	if from := c.Query("from"); from != "" {
+		queryParam.DateFrom = &from
+	}
+	if to := c.Query("to"); to != "" {
+		queryParam.DateTo = &to
+	}
+
+	ctx := c.Request.Context()
+	result, err := h.TimeOffService.GetTimeOffRequests(ctx, queryParam)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// ApproveTimeOff godoc
+// @Summary      Approve time off
+// @Description  The worker can no longer request or be given shifts during the leave. Shifts already approved for them then are kept, flagged with needs_reassignment and listed in flagged_shifts; find them later with GET /admin/requests?needs_reassignment=true.
+// @Tags         time-off
+// @Produce      json
+// @Security     BearerAuth
+// @Param        timeOffID  path      int  true  "Time off ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/time-off/{timeOffID}/approve [put]
+func (h *TimeOffHandler) ApproveTimeOff(c *gin.Context) {
+	timeOffID, _ := strconv.ParseInt(c.Param("timeOffID"), 10, 64)
+	ctx := c.Request.Context()
+	flagged, err := h.TimeOffService.ApproveTimeOff(ctx, timeOffID)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Time off approved", "flagged_shifts": flagged})
+}
+
+// RejectTimeOff godoc
+// @Summary      Reject time off
+// @Tags         time-off
+// @Produce      json
+// @Security     BearerAuth
+// @Param        timeOffID  path      int  true  "Time off ID"
+// @Success      200  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /admin/time-off/{timeOffID}/reject [put]
+func (h *TimeOffHandler) RejectTimeOff(c *gin.Context) {
+	timeOffID, _ := strconv.ParseInt(c.Param("timeOffID"), 10, 64)
+	ctx := c.Request.Context()
+	if err := h.TimeOffService.RejectTimeOff(ctx, timeOffID); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Time off rejected"})
+}
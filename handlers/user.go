@@ -37,7 +37,7 @@ func (h *UserHandler) SignUp(c *gin.Context) {
 	}
 	id, err := h.UserService.SignUp(&user)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"id": id})
@@ -72,10 +72,12 @@ func (h *UserHandler) Login(c *gin.Context) {
 
 // GetAllWorkers godoc
 // @Summary      Get all workers
+// @Description  Admins only.
 // @Tags         users
 // @Produce      json
 // @Security     BearerAuth
 // @Success      200   {array}   model.User
+// @Failure      403   {object}  map[string]string
 // @Failure      500   {object}  map[string]string
 // @Router       /workers [get]
 func (h *UserHandler) GetAllWorkers(c *gin.Context) {
@@ -92,13 +94,14 @@ func (h *UserHandler) GetAllWorkers(c *gin.Context) {
 // @Tags         users
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id   path      int  true  "Worker ID"
+// @Param        workerID  path      int  true  "Worker ID"
 // @Success      200  {object}  model.User
 // @Failure      400  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
 // @Failure      404  {object}  map[string]string
-// @Router       /worker/{id} [get]
+// @Router       /worker/{workerID} [get]
 func (h *UserHandler) GetWorkerByID(c *gin.Context) {
-	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	id, err := strconv.ParseInt(c.Param("workerID"), 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid worker id"})
 		return
@@ -110,3 +113,73 @@ func (h *UserHandler) GetWorkerByID(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, user)
 }
+
+// RefreshToken godoc
+// @Summary      Exchange a refresh token for a new token pair
+// @Description  The refresh token is single use; the response carries its replacement.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        body  body      object{refresh_token=string}  true  "Refresh token"
+// @Success      200   {object}  model.TokenPair
+// @Failure      400   {object}  map[string]string
+// @Failure      401   {object}  map[string]string
+// @Router       /token/refresh [post]
+func (h *UserHandler) RefreshToken(c *gin.Context) {
+	var req struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx := c.Request.Context()
+	pair, err := h.UserService.RefreshToken(ctx, req.RefreshToken)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, pair)
+}
+
+// Logout godoc
+// @Summary      Revoke the current session
+// @Tags         users
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /logout [post]
+func (h *UserHandler) Logout(c *gin.Context) {
+	ctx := c.Request.Context()
+	if err := h.UserService.Logout(ctx); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out"})
+}
+
+// RevokeUserSessions godoc
+// @Summary      Revoke all sessions of a user
+// @Tags         users
+// @Produce      json
+// @Security     BearerAuth
+// @Param        userID  path      int  true  "User ID"
+// @Success      200  {object}  map[string]int64
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /admin/user/{userID}/revoke-sessions [post]
+func (h *UserHandler) RevokeUserSessions(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("userID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+	ctx := c.Request.Context()
+	revoked, err := h.UserService.RevokeUserSessions(ctx, userID)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"revoked": revoked})
+}
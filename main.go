@@ -1,7 +1,97 @@
 package main
 
-import "dailyworkerroster/server"
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"dailyworkerroster/clock"
+	"dailyworkerroster/config"
+	"dailyworkerroster/migration"
+	"dailyworkerroster/repository"
+	"dailyworkerroster/server"
+)
 
 func main() {
-	server.NewServer()
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "path to a JSON config file")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	if args := flag.Args(); len(args) > 0 && args[0] == "migrate" {
+		if err := runMigrate(cfg, args[1:]); err != nil {
+			log.Fatalf("migrate: %v", err)
+		}
+		return
+	}
+
+	server.NewServer(cfg)
+}
+
+// runMigrate handles `migrate up`, `migrate down [steps]` and `migrate status`
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: migrate up|down [steps]|status")
+	}
+
+	db, dialect, err := repository.Open(cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// Some migrations convert times of shifts at locations without a zone
+	zone, err := clock.LoadLocation(cfg.TimeZone, time.UTC)
+	if err != nil {
+		return err
+	}
+	migrator, err := migration.NewMigrator(db, dialect, zone)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("applied %d migration(s)\n", len(applied))
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid steps %q", args[1])
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("reverted %d migration(s)\n", len(reverted))
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-40s %s\n", status.Version, status.Name, appliedAt)
+		}
+	default:
+		return fmt.Errorf("unknown migrate command %q", args[0])
+	}
+	return nil
 }
@@ -1,7 +1,8 @@
 package middleware
 
 import (
-	"dailyworkerroster/model"
+	"dailyworkerroster/auth"
+	"dailyworkerroster/repository"
 	"net/http"
 	"strings"
 	"time"
@@ -10,9 +11,9 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var jwtSecret = []byte("FROMCONFIG")
-
-func AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware accepts a bearer access token signed with jwtSecret whose
+// login session is still live, and puts its principal into the request context.
+func AuthMiddleware(jwtSecret []byte, sessions repository.UserSessionRepoItf) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -28,7 +29,7 @@ func AuthMiddleware() gin.HandlerFunc {
 
 		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 			return jwtSecret, nil
-		})
+		}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Name}), jwt.WithExpirationRequired())
 		if err != nil || !token.Valid {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 			return
@@ -40,24 +41,33 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		if userID, ok := claims["user_id"].(float64); ok {
-			c.Set("user_account_id", int64(userID))
-		}
-		if name, ok := claims["name"].(string); ok {
-			c.Set("name", name)
+		userID, ok := claims["user_id"].(float64)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
+			return
 		}
-		if role, ok := claims["role"].(string); ok {
-			c.Set("role", role)
+		principal := &auth.Principal{UserID: int64(userID)}
+		principal.Name, _ = claims["name"].(string)
+		principal.Role, _ = claims["role"].(string)
+		principal.TokenID, _ = claims["jti"].(string)
+		principal.SessionID, _ = claims["sid"].(string)
+
+		session, err := sessions.GetUserSessionByID(principal.SessionID)
+		if err != nil || session.UserAccountID != principal.UserID ||
+			session.RevokedAt != nil || !time.Now().Before(session.ExpiresAt) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Session expired or revoked"})
+			return
 		}
 
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
 		c.Next()
 	}
 }
 
 func AdminMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		role, exists := c.Get("role")
-		if !exists || role != model.ROLE_ADMIN {
+		principal, ok := auth.FromContext(c.Request.Context())
+		if !ok || !principal.IsAdmin() {
 			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
 			return
 		}
@@ -65,12 +75,15 @@ func AdminMiddleware() gin.HandlerFunc {
 	}
 }
 
-func GenerateJWT(userID int64, name, role string) (string, error) {
+// GenerateJWT issues an access token for the given login session, valid for ttl.
+func GenerateJWT(jwtSecret []byte, userID int64, name, role, sessionID string, ttl time.Duration) (string, error) {
 	claims := jwt.MapClaims{
 		"user_id": userID,
 		"name":    name,
 		"role":    role,
-		"exp":     time.Now().Add(time.Hour * 999).Unix(),
+		"sid":     sessionID,
+		"jti":     auth.NewToken(16),
+		"exp":     time.Now().Add(ttl).Unix(),
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString(jwtSecret)
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"dailyworkerroster/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WorkerOwnership checks that the worker named by the given URL parameter is
+// the authenticated user. Admins may act on behalf of any worker.
+// It must run after AuthMiddleware.
+func WorkerOwnership(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		workerID, err := strconv.ParseInt(c.Param(param), 10, 64)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid worker id"})
+			return
+		}
+
+		principal, ok := auth.FromContext(c.Request.Context())
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
+			return
+		}
+		if principal.IsAdmin() {
+			c.Next()
+			return
+		}
+		if principal.UserID != workerID {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// Self fills the given URL parameter with the authenticated user's ID, so the
+// /me routes can reuse the handlers of their /worker counterparts.
+// It must run after AuthMiddleware.
+func Self(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.FromContext(c.Request.Context())
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
+			return
+		}
+		c.Params = append(c.Params, gin.Param{Key: param, Value: strconv.FormatInt(principal.UserID, 10)})
+		c.Next()
+	}
+}
@@ -0,0 +1,274 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"dailyworkerroster/repository"
+)
+
+//go:embed mysql/*.sql sqlite/*.sql
+var files embed.FS
+
+const trackingTable = "schema_migrations"
+
+// Migration is one numbered schema change, read from
+// <dialect>/<version>_<name>.up.sql and the matching .down.sql.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at"` // nil when pending
+}
+
+type Migrator struct {
+	DB         *sql.DB
+	Dialect    repository.Dialect
+	Zone       *time.Location // zone of locations without one
+	Migrations []Migration
+}
+
+func NewMigrator(db *sql.DB, dialect repository.Dialect, zone *time.Location) (*Migrator, error) {
+	migrations, err := load(dialect)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{
+		DB:         db,
+		Dialect:    dialect,
+		Zone:       zone,
+		Migrations: migrations,
+	}, nil
+}
+
+// Up applies every pending migration in version order, each with its step
+// in upSteps, and returns the ones that were applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	funcName := "/migration/Up"
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, mg := range m.Migrations {
+		if _, ok := applied[mg.Version]; ok {
+			continue
+		}
+		err := m.run(ctx, mg.Up, func(tx *sql.Tx) error {
+			if step, ok := upSteps[mg.Version]; ok {
+				if err := step(tx, m.Zone); err != nil {
+					return err
+				}
+			}
+			_, err := tx.Exec(`INSERT INTO `+trackingTable+` (version, name, applied_at) VALUES (?, ?, ?)`,
+				mg.Version, mg.Name, time.Now().UTC())
+			return err
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %04d_%s up: %w", mg.Version, mg.Name, err)
+		}
+		log.Printf("%s: applied %04d_%s", funcName, mg.Version, mg.Name)
+		done = append(done, mg)
+	}
+	return done, nil
+}
+
+// Down reverts the latest steps applied migrations, newest first.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	funcName := "/migration/Down"
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(m.Migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		mg := m.Migrations[i]
+		if _, ok := applied[mg.Version]; !ok {
+			continue
+		}
+		err := m.run(ctx, mg.Down, func(tx *sql.Tx) error {
+			_, err := tx.Exec(`DELETE FROM `+trackingTable+` WHERE version = ?`, mg.Version)
+			return err
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %04d_%s down: %w", mg.Version, mg.Name, err)
+		}
+		log.Printf("%s: reverted %04d_%s", funcName, mg.Version, mg.Name)
+		done = append(done, mg)
+	}
+	return done, nil
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.Migrations))
+	for _, mg := range m.Migrations {
+		status := MigrationStatus{Version: mg.Version, Name: mg.Name}
+		if appliedAt, ok := applied[mg.Version]; ok {
+			appliedAt := appliedAt
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// run executes a migration script and its bookkeeping in one transaction.
+// MySQL commits DDL implicitly, so there the transaction only covers DML.
+func (m *Migrator) run(ctx context.Context, script string, record func(tx *sql.Tx) error) error {
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range splitStatements(script) {
+		if _, err := tx.Exec(stmt); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	if err := record(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[int64]time.Time, error) {
+	_, err := m.DB.ExecContext(ctx, `
+        CREATE TABLE IF NOT EXISTS `+trackingTable+` (
+            version BIGINT NOT NULL PRIMARY KEY,
+            name VARCHAR(255) NOT NULL,
+            applied_at DATETIME NOT NULL
+        )
+    `)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := m.DB.QueryContext(ctx, `SELECT version, applied_at FROM `+trackingTable)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]time.Time)
+	for rows.Next() {
+		var version int64
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+func load(dialect repository.Dialect) ([]Migration, error) {
+	dir := "mysql"
+	if dialect == repository.DialectSQLite {
+		dir = "sqlite"
+	}
+
+	entries, err := fs.ReadDir(files, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		fileName := entry.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(fileName, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(fileName, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+
+		base := strings.TrimSuffix(fileName, "."+direction+".sql")
+		versionPart, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s: expected <version>_<name>", fileName)
+		}
+		version, err := strconv.ParseInt(versionPart, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: invalid version: %w", fileName, err)
+		}
+
+		content, err := fs.ReadFile(files, path.Join(dir, fileName))
+		if err != nil {
+			return nil, err
+		}
+
+		mg, ok := byVersion[version]
+		if !ok {
+			mg = &Migration{Version: version, Name: name}
+			byVersion[version] = mg
+		}
+		if direction == "up" {
+			mg.Up = string(content)
+		} else {
+			mg.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mg := range byVersion {
+		if mg.Up == "" || mg.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s: missing up or down script", mg.Version, mg.Name)
+		}
+		migrations = append(migrations, *mg)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// splitStatements splits a script on semicolons that end a line. Lines that
+// only hold "--" comments are dropped, so comment-only chunks are skipped.
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
@@ -0,0 +1,52 @@
+package migration
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{
+			name:   "one per line",
+			script: "CREATE TABLE a (id INT);\nDROP TABLE b;\n",
+			want:   []string{"CREATE TABLE a (id INT)", "DROP TABLE b"},
+		},
+		{
+			name:   "over several lines",
+			script: "CREATE TABLE a (\n    id INT,\n    name TEXT\n);\n",
+			want:   []string{"CREATE TABLE a (\n    id INT,\n    name TEXT\n)"},
+		},
+		{
+			name:   "comments and blank lines dropped",
+			script: "-- why\n\nUPDATE a SET id = 1;\n  -- indented comment\n",
+			want:   []string{"UPDATE a SET id = 1"},
+		},
+		{
+			name:   "comment only",
+			script: "-- nothing to do for this dialect\n",
+			want:   nil,
+		},
+		{
+			name:   "last statement without semicolon",
+			script: "DROP TABLE a;\nDROP TABLE b",
+			want:   []string{"DROP TABLE a", "DROP TABLE b"},
+		},
+		{
+			name:   "semicolon inside a line does not split",
+			script: "INSERT INTO a (note) VALUES ('x; y');\n",
+			want:   []string{"INSERT INTO a (note) VALUES ('x; y')"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitStatements(tt.script); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitStatements = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package migration_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"dailyworkerroster/config"
+	"dailyworkerroster/migration"
+	"dailyworkerroster/repository"
+)
+
+func TestMain(m *testing.M) {
+	// The migrator logs every step it applies
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+func newMigrator(t *testing.T) (*sql.DB, *migration.Migrator) {
+	t.Helper()
+
+	db, dialect, err := repository.Open(config.DatabaseConfig{DSN: "sqlite://:memory:"})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	migrator, err := migration.NewMigrator(db, dialect, time.UTC)
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	return db, migrator
+}
+
+func countApplied(t *testing.T, migrator *migration.Migrator) int {
+	t.Helper()
+
+	statuses, err := migrator.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	applied := 0
+	for _, status := range statuses {
+		if status.AppliedAt != nil {
+			applied++
+		}
+	}
+	return applied
+}
+
+func TestMigrateUpDownStatus(t *testing.T) {
+	_, migrator := newMigrator(t)
+	ctx := context.Background()
+	all := len(migrator.Migrations)
+
+	if got := countApplied(t, migrator); got != 0 {
+		t.Fatalf("%d migrations applied to a new database, want 0", got)
+	}
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if len(applied) != all || countApplied(t, migrator) != all {
+		t.Errorf("Up applied %d of %d migrations", len(applied), all)
+	}
+	if applied, err := migrator.Up(ctx); err != nil || len(applied) != 0 {
+		t.Errorf("second Up applied %d migrations, err = %v; want none", len(applied), err)
+	}
+
+	// Every down script undoes its up script, so the schema can be built again
+	reverted, err := migrator.Down(ctx, 2)
+	if err != nil {
+		t.Fatalf("Down 2: %v", err)
+	}
+	if len(reverted) != 2 || reverted[0].Version != migrator.Migrations[all-1].Version {
+		t.Errorf("Down 2 reverted %v, want the newest two", reverted)
+	}
+	if _, err := migrator.Down(ctx, all); err != nil {
+		t.Fatalf("Down all: %v", err)
+	}
+	if got := countApplied(t, migrator); got != 0 {
+		t.Errorf("%d migrations applied after Down all, want 0", got)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Up after Down: %v", err)
+	}
+}
+
+func TestFailedMigrationIsRolledBack(t *testing.T) {
+	db, migrator := newMigrator(t)
+	migrator.Migrations = []migration.Migration{{
+		Version: 1,
+		Name:    "broken",
+		Up:      "CREATE TABLE half (id INT);\nINSERT INTO missing VALUES (1);",
+		Down:    "DROP TABLE half;",
+	}}
+
+	if _, err := migrator.Up(context.Background()); err == nil {
+		t.Fatal("Up of a broken migration succeeded")
+	}
+	if got := countApplied(t, migrator); got != 0 {
+		t.Errorf("broken migration recorded as applied")
+	}
+	var name string
+	err := db.QueryRow(`SELECT name FROM sqlite_master WHERE type = 'table' AND name = 'half'`).Scan(&name)
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("table of the broken migration left behind: err = %v", err)
+	}
+}
+
+func TestDialectsHaveSameMigrations(t *testing.T) {
+	mysql, err := migration.NewMigrator(nil, repository.DialectMySQL, time.UTC)
+	if err != nil {
+		t.Fatalf("load mysql migrations: %v", err)
+	}
+	sqlite, err := migration.NewMigrator(nil, repository.DialectSQLite, time.UTC)
+	if err != nil {
+		t.Fatalf("load sqlite migrations: %v", err)
+	}
+	if len(mysql.Migrations) != len(sqlite.Migrations) {
+		t.Fatalf("%d mysql and %d sqlite migrations", len(mysql.Migrations), len(sqlite.Migrations))
+	}
+	for i, mg := range mysql.Migrations {
+		other := sqlite.Migrations[i]
+		if mg.Version != other.Version || mg.Name != other.Name {
+			t.Errorf("migration %d is %04d_%s for mysql and %04d_%s for sqlite", i, mg.Version, mg.Name, other.Version, other.Name)
+		}
+	}
+}
+
+func TestUpgradeAnchorsShiftInstantsInDefaultZone(t *testing.T) {
+	db, dialect, err := repository.Open(config.DatabaseConfig{DSN: "sqlite://:memory:"})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer db.Close()
+	zone, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("load zone: %v", err)
+	}
+	migrator, err := migration.NewMigrator(db, dialect, zone)
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	all := migrator.Migrations
+
+	// A night shift over the start of daylight saving time, stored as
+	// naive server local time before locations had zones
+	migrator.Migrations = all[:10]
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("migrate up to 0010: %v", err)
+	}
+	_, err = db.Exec(`INSERT INTO shift (date, start_time, end_time, role_assignment, location, start_at, end_at)
+		VALUES ('2026-03-07', '22:00:00', '06:00:00', 'CLEANER', 'Store', '2026-03-07 22:00:00', '2026-03-08 06:00:00')`)
+	if err != nil {
+		t.Fatalf("insert shift: %v", err)
+	}
+
+	migrator.Migrations = all
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	shift, err := repository.NewRepositories(db, dialect, zone).Shift.GetShiftByID(1)
+	if err != nil {
+		t.Fatalf("GetShiftByID: %v", err)
+	}
+	wantStart := time.Date(2026, 3, 8, 3, 0, 0, 0, time.UTC)
+	wantEnd := time.Date(2026, 3, 8, 10, 0, 0, 0, time.UTC)
+	if !shift.StartAt.Equal(wantStart) || !shift.EndAt.Equal(wantEnd) {
+		t.Errorf("instants = %v-%v, want %v-%v", shift.StartAt.UTC(), shift.EndAt.UTC(), wantStart, wantEnd)
+	}
+	if shift.StartTime != "22:00:00" || shift.EndTime != "06:00:00" || shift.TimeZone != zone.String() {
+		t.Errorf("wall clock = %s-%s in %s, want 22:00:00-06:00:00 in %s", shift.StartTime, shift.EndTime, shift.TimeZone, zone)
+	}
+
+	if _, err := migrator.Down(context.Background(), len(all)-10); err != nil {
+		t.Fatalf("migrate down to 0010: %v", err)
+	}
+	// The driver reads the naive values as UTC
+	var startAt, endAt time.Time
+	if err := db.QueryRow(`SELECT start_at, end_at FROM shift WHERE id = 1`).Scan(&startAt, &endAt); err != nil {
+		t.Fatalf("read instants: %v", err)
+	}
+	naiveStart := time.Date(2026, 3, 7, 22, 0, 0, 0, time.UTC)
+	naiveEnd := time.Date(2026, 3, 8, 6, 0, 0, 0, time.UTC)
+	if !startAt.Equal(naiveStart) || !endAt.Equal(naiveEnd) {
+		t.Errorf("after down instants = %v-%v, want the naive %v-%v", startAt, endAt, naiveStart, naiveEnd)
+	}
+}
@@ -1,4 +1,4 @@
-CREATE TABLE user_account (
+CREATE TABLE IF NOT EXISTS user_account (
     id BIGINT AUTO_INCREMENT PRIMARY KEY,
     name VARCHAR(100) NOT NULL,
     username VARCHAR(50) NOT NULL UNIQUE,
@@ -9,7 +9,7 @@ CREATE TABLE user_account (
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
 );
 
-CREATE TABLE shift (
+CREATE TABLE IF NOT EXISTS shift (
     id BIGINT AUTO_INCREMENT PRIMARY KEY,
     date DATE NOT NULL,
     start_time TIME NOT NULL,
@@ -21,7 +21,7 @@ CREATE TABLE shift (
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
 );
 
-CREATE TABLE worker_shift (
+CREATE TABLE IF NOT EXISTS worker_shift (
     id BIGINT AUTO_INCREMENT PRIMARY KEY,
     shift_id BIGINT NOT NULL,
     user_account_id BIGINT NOT NULL,
@@ -32,4 +32,4 @@ CREATE TABLE worker_shift (
     FOREIGN KEY (shift_id) REFERENCES shift(id),
     FOREIGN KEY (user_account_id) REFERENCES user_account(id),
     FOREIGN KEY (approved_by) REFERENCES user_account(id)
-);
\ No newline at end of file
+);
@@ -0,0 +1,5 @@
+UPDATE worker_shift SET status = 'APPROVED' WHERE status = 'DONE';
+UPDATE worker_shift SET status = 'REJECTED' WHERE status = 'EXPIRED';
+
+ALTER TABLE worker_shift
+    MODIFY status ENUM('PENDING', 'APPROVED', 'REJECTED') NOT NULL;
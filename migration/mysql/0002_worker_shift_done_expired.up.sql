@@ -0,0 +1,2 @@
+ALTER TABLE worker_shift
+    MODIFY status ENUM('PENDING', 'APPROVED', 'REJECTED', 'DONE', 'EXPIRED') NOT NULL;
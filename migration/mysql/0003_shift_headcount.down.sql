@@ -0,0 +1 @@
+ALTER TABLE shift DROP COLUMN headcount;
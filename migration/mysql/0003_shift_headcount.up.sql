@@ -0,0 +1 @@
+ALTER TABLE shift ADD COLUMN headcount INT NOT NULL DEFAULT 1;
@@ -0,0 +1,7 @@
+ALTER TABLE shift DROP FOREIGN KEY fk_shift_template;
+
+ALTER TABLE shift
+    DROP INDEX uq_shift_template_date,
+    DROP COLUMN template_id;
+
+DROP TABLE shift_template;
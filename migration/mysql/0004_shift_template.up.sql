@@ -0,0 +1,21 @@
+CREATE TABLE shift_template (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    role_assignment ENUM('CLEANER', 'CASHIER') NOT NULL,
+    location VARCHAR(100) NOT NULL,
+    headcount INT NOT NULL DEFAULT 1,
+    weekdays VARCHAR(27) NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NULL,
+    excluded_dates TEXT,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+ALTER TABLE shift
+    ADD COLUMN template_id BIGINT NULL,
+    ADD CONSTRAINT fk_shift_template FOREIGN KEY (template_id) REFERENCES shift_template(id),
+    ADD UNIQUE KEY uq_shift_template_date (template_id, date);
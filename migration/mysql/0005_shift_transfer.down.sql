@@ -0,0 +1,2 @@
+DROP TABLE worker_shift_history;
+DROP TABLE shift_transfer;
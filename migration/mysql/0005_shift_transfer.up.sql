@@ -0,0 +1,31 @@
+CREATE TABLE shift_transfer (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    worker_shift_id BIGINT NOT NULL,
+    from_user_id BIGINT NOT NULL,
+    to_user_id BIGINT NULL,
+    taker_id BIGINT NULL,
+    status ENUM('OFFERED', 'ACCEPTED', 'APPROVED', 'REJECTED', 'CANCELLED') NOT NULL,
+    decided_by BIGINT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (worker_shift_id) REFERENCES worker_shift(id) ON DELETE CASCADE,
+    FOREIGN KEY (from_user_id) REFERENCES user_account(id),
+    FOREIGN KEY (to_user_id) REFERENCES user_account(id),
+    FOREIGN KEY (taker_id) REFERENCES user_account(id),
+    FOREIGN KEY (decided_by) REFERENCES user_account(id)
+);
+
+CREATE TABLE worker_shift_history (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    worker_shift_id BIGINT NOT NULL,
+    from_user_id BIGINT NOT NULL,
+    to_user_id BIGINT NOT NULL,
+    transfer_id BIGINT NULL,
+    changed_by BIGINT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (worker_shift_id) REFERENCES worker_shift(id) ON DELETE CASCADE,
+    FOREIGN KEY (from_user_id) REFERENCES user_account(id),
+    FOREIGN KEY (to_user_id) REFERENCES user_account(id),
+    FOREIGN KEY (transfer_id) REFERENCES shift_transfer(id) ON DELETE SET NULL,
+    FOREIGN KEY (changed_by) REFERENCES user_account(id)
+);
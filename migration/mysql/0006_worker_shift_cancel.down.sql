@@ -0,0 +1,4 @@
+UPDATE worker_shift SET status = 'REJECTED' WHERE status IN ('WITHDRAWN', 'CANCELLED');
+
+ALTER TABLE worker_shift
+    MODIFY status ENUM('PENDING', 'APPROVED', 'REJECTED', 'DONE', 'EXPIRED') NOT NULL;
@@ -0,0 +1,11 @@
+CREATE TABLE user_session (
+    id VARCHAR(64) NOT NULL PRIMARY KEY,
+    user_account_id BIGINT NOT NULL,
+    refresh_token_hash CHAR(64) NOT NULL,
+    expires_at DATETIME NOT NULL,
+    revoked_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_account_id) REFERENCES user_account(id) ON DELETE CASCADE,
+    INDEX idx_user_session_user (user_account_id)
+);
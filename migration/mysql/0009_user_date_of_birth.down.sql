@@ -0,0 +1 @@
+ALTER TABLE user_account DROP COLUMN date_of_birth;
@@ -0,0 +1,4 @@
+ALTER TABLE shift
+    DROP INDEX idx_shift_start_at,
+    DROP COLUMN end_at,
+    DROP COLUMN start_at;
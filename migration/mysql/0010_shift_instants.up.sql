@@ -0,0 +1,16 @@
+ALTER TABLE shift
+    ADD COLUMN start_at DATETIME NULL AFTER end_time,
+    ADD COLUMN end_at DATETIME NULL AFTER start_at;
+
+-- An end time not after the start time ends the shift on the next day
+UPDATE shift SET
+    start_at = TIMESTAMP(date, start_time),
+    end_at = CASE
+        WHEN end_time > start_time THEN TIMESTAMP(date, end_time)
+        ELSE TIMESTAMP(DATE_ADD(date, INTERVAL 1 DAY), end_time)
+    END;
+
+ALTER TABLE shift
+    MODIFY start_at DATETIME NOT NULL,
+    MODIFY end_at DATETIME NOT NULL,
+    ADD INDEX idx_shift_start_at (start_at);
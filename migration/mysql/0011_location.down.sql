@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS location;
+
+-- Back to naive server local time, as migration 0010 stored them
+UPDATE shift SET
+    start_at = TIMESTAMP(date, start_time),
+    end_at = CASE
+        WHEN end_time > start_time THEN TIMESTAMP(date, end_time)
+        ELSE TIMESTAMP(DATE_ADD(date, INTERVAL 1 DAY), end_time)
+    END;
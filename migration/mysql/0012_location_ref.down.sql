@@ -0,0 +1,19 @@
+ALTER TABLE shift_template ADD COLUMN location VARCHAR(100) NOT NULL DEFAULT '' AFTER role_assignment;
+UPDATE shift_template SET location = COALESCE((SELECT l.name FROM location l WHERE l.id = shift_template.location_id), '');
+ALTER TABLE shift_template
+    DROP FOREIGN KEY fk_shift_template_location,
+    DROP COLUMN location_id,
+    ALTER COLUMN location DROP DEFAULT;
+
+ALTER TABLE shift ADD COLUMN location VARCHAR(100) NOT NULL DEFAULT '' AFTER role_assignment;
+UPDATE shift SET location = COALESCE((SELECT l.name FROM location l WHERE l.id = shift.location_id), '');
+ALTER TABLE shift
+    DROP FOREIGN KEY fk_shift_location,
+    DROP COLUMN location_id,
+    ALTER COLUMN location DROP DEFAULT;
+
+ALTER TABLE location
+    DROP COLUMN longitude,
+    DROP COLUMN latitude,
+    DROP COLUMN active,
+    DROP COLUMN address;
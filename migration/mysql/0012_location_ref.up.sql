@@ -0,0 +1,27 @@
+ALTER TABLE location
+    ADD COLUMN address VARCHAR(255) NOT NULL DEFAULT '' AFTER name,
+    ADD COLUMN active BOOLEAN NOT NULL DEFAULT TRUE AFTER time_zone,
+    ADD COLUMN latitude DOUBLE NULL AFTER active,
+    ADD COLUMN longitude DOUBLE NULL AFTER latitude;
+
+-- Every place named by a shift or template becomes a location. Names that
+-- differ only in case or surrounding spaces are the same place; new rows
+-- get the default time zone.
+INSERT INTO location (name, time_zone)
+SELECT MIN(TRIM(u.location)), ''
+FROM (SELECT location FROM shift UNION ALL SELECT location FROM shift_template) u
+WHERE TRIM(u.location) <> ''
+  AND NOT EXISTS (SELECT 1 FROM location l WHERE LOWER(l.name) = LOWER(TRIM(u.location)))
+GROUP BY LOWER(TRIM(u.location));
+
+ALTER TABLE shift ADD COLUMN location_id BIGINT NULL AFTER role_assignment;
+UPDATE shift SET location_id = (SELECT MIN(l.id) FROM location l WHERE LOWER(l.name) = LOWER(TRIM(shift.location)));
+ALTER TABLE shift
+    ADD CONSTRAINT fk_shift_location FOREIGN KEY (location_id) REFERENCES location(id),
+    DROP COLUMN location;
+
+ALTER TABLE shift_template ADD COLUMN location_id BIGINT NULL AFTER role_assignment;
+UPDATE shift_template SET location_id = (SELECT MIN(l.id) FROM location l WHERE LOWER(l.name) = LOWER(TRIM(shift_template.location)));
+ALTER TABLE shift_template
+    ADD CONSTRAINT fk_shift_template_location FOREIGN KEY (location_id) REFERENCES location(id),
+    DROP COLUMN location;
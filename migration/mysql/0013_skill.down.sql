@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS worker_skill;
+
+-- Fails while shifts use roles other than the original two
+ALTER TABLE shift_template DROP FOREIGN KEY fk_shift_template_skill;
+ALTER TABLE shift_template
+    DROP INDEX fk_shift_template_skill,
+    MODIFY role_assignment ENUM('CLEANER', 'CASHIER') NOT NULL;
+
+ALTER TABLE shift DROP FOREIGN KEY fk_shift_skill;
+ALTER TABLE shift
+    DROP INDEX fk_shift_skill,
+    MODIFY role_assignment ENUM('CLEANER', 'CASHIER') NOT NULL;
+
+DROP TABLE IF EXISTS skill;
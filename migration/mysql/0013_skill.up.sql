@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS skill (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(50) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+INSERT INTO skill (code, name) VALUES ('CLEANER', 'Cleaner'), ('CASHIER', 'Cashier');
+
+-- Shift roles are skill codes now
+ALTER TABLE shift
+    MODIFY role_assignment VARCHAR(50) NOT NULL,
+    ADD CONSTRAINT fk_shift_skill FOREIGN KEY (role_assignment) REFERENCES skill(code);
+
+ALTER TABLE shift_template
+    MODIFY role_assignment VARCHAR(50) NOT NULL,
+    ADD CONSTRAINT fk_shift_template_skill FOREIGN KEY (role_assignment) REFERENCES skill(code);
+
+CREATE TABLE IF NOT EXISTS worker_skill (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_account_id BIGINT NOT NULL,
+    skill_id BIGINT NOT NULL,
+    expires_on DATE NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_worker_skill (user_account_id, skill_id),
+    FOREIGN KEY (user_account_id) REFERENCES user_account(id),
+    FOREIGN KEY (skill_id) REFERENCES skill(id)
+);
+
+-- Every worker could take every role so far and keeps doing so
+INSERT INTO worker_skill (user_account_id, skill_id)
+SELECT u.id, s.id FROM user_account u CROSS JOIN skill s WHERE u.role = 'WORKER';
@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS worker_availability (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_account_id BIGINT NOT NULL,
+    weekday CHAR(3) NOT NULL,
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_account_id) REFERENCES user_account(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS worker_unavailable_date (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_account_id BIGINT NOT NULL,
+    date DATE NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_worker_unavailable_date (user_account_id, date),
+    FOREIGN KEY (user_account_id) REFERENCES user_account(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS worker_preferred_location (
+    user_account_id BIGINT NOT NULL,
+    location_id BIGINT NOT NULL,
+    PRIMARY KEY (user_account_id, location_id),
+    FOREIGN KEY (user_account_id) REFERENCES user_account(id) ON DELETE CASCADE,
+    FOREIGN KEY (location_id) REFERENCES location(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS worker_preferred_role (
+    user_account_id BIGINT NOT NULL,
+    skill_id BIGINT NOT NULL,
+    PRIMARY KEY (user_account_id, skill_id),
+    FOREIGN KEY (user_account_id) REFERENCES user_account(id) ON DELETE CASCADE,
+    FOREIGN KEY (skill_id) REFERENCES skill(id) ON DELETE CASCADE
+);
@@ -0,0 +1,4 @@
+UPDATE worker_shift SET status = 'CANCELLED' WHERE status = 'NO_SHOW';
+
+ALTER TABLE worker_shift
+    MODIFY status ENUM('PENDING', 'APPROVED', 'REJECTED', 'DONE', 'EXPIRED', 'WITHDRAWN', 'CANCELLED') NOT NULL;
@@ -0,0 +1,7 @@
+UPDATE worker_shift SET status = 'REJECTED' WHERE status IN ('WAITLISTED', 'OFFERED');
+
+ALTER TABLE worker_shift DROP COLUMN offer_expires_at;
+ALTER TABLE worker_shift DROP COLUMN waitlist_position;
+
+ALTER TABLE worker_shift
+    MODIFY status ENUM('PENDING', 'APPROVED', 'REJECTED', 'DONE', 'EXPIRED', 'WITHDRAWN', 'CANCELLED', 'NO_SHOW') NOT NULL;
@@ -0,0 +1,6 @@
+ALTER TABLE worker_shift
+    MODIFY status ENUM('PENDING', 'APPROVED', 'REJECTED', 'DONE', 'EXPIRED', 'WITHDRAWN', 'CANCELLED', 'NO_SHOW', 'WAITLISTED', 'OFFERED') NOT NULL;
+
+-- Place on the waitlist of a full shift, and the deadline of a place offered from it
+ALTER TABLE worker_shift ADD COLUMN waitlist_position INT NULL;
+ALTER TABLE worker_shift ADD COLUMN offer_expires_at DATETIME NULL;
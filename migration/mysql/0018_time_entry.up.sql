@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS time_entry (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    worker_shift_id BIGINT NOT NULL,
+    clock_in_at DATETIME NOT NULL,
+    clock_out_at DATETIME NULL,
+    auto_clock_out BOOLEAN NOT NULL DEFAULT FALSE,
+    corrected_by BIGINT NULL,
+    correction_reason VARCHAR(255) NOT NULL DEFAULT '',
+    corrected_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_time_entry_worker_shift (worker_shift_id),
+    FOREIGN KEY (worker_shift_id) REFERENCES worker_shift(id) ON DELETE CASCADE,
+    FOREIGN KEY (corrected_by) REFERENCES user_account(id)
+);
+
+CREATE TABLE IF NOT EXISTS time_entry_break (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    time_entry_id BIGINT NOT NULL,
+    start_at DATETIME NOT NULL,
+    end_at DATETIME NULL,
+    INDEX idx_time_entry_break_entry (time_entry_id),
+    FOREIGN KEY (time_entry_id) REFERENCES time_entry(id) ON DELETE CASCADE
+);
@@ -0,0 +1 @@
+ALTER TABLE user_session DROP COLUMN previous_token_hash;
@@ -0,0 +1,3 @@
+-- Hash of the refresh token the last rotation replaced, to tell a leaked
+-- token presented again from one that was never issued
+ALTER TABLE user_session ADD COLUMN previous_token_hash CHAR(64) NULL;
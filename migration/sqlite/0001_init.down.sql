@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS worker_shift;
+DROP TABLE IF EXISTS shift;
+DROP TABLE IF EXISTS user_account;
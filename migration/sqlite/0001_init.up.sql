@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS user_account (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(100) NOT NULL,
+    username VARCHAR(50) NOT NULL UNIQUE,
+    email VARCHAR(100) NOT NULL UNIQUE,
+    password VARCHAR(255) NOT NULL,
+    role TEXT NOT NULL CHECK (role IN ('ADMIN', 'WORKER')),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS shift (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    date DATE NOT NULL,
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    role_assignment TEXT NOT NULL CHECK (role_assignment IN ('CLEANER', 'CASHIER')),
+    location VARCHAR(100) NOT NULL,
+    isAvailable BOOLEAN DEFAULT TRUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS worker_shift (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    shift_id BIGINT NOT NULL,
+    user_account_id BIGINT NOT NULL,
+    approved_by BIGINT,
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED')),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (shift_id) REFERENCES shift(id),
+    FOREIGN KEY (user_account_id) REFERENCES user_account(id),
+    FOREIGN KEY (approved_by) REFERENCES user_account(id)
+);
@@ -0,0 +1,22 @@
+UPDATE worker_shift SET status = 'APPROVED' WHERE status = 'DONE';
+UPDATE worker_shift SET status = 'REJECTED' WHERE status = 'EXPIRED';
+
+CREATE TABLE worker_shift_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    shift_id BIGINT NOT NULL,
+    user_account_id BIGINT NOT NULL,
+    approved_by BIGINT,
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED')),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (shift_id) REFERENCES shift(id),
+    FOREIGN KEY (user_account_id) REFERENCES user_account(id),
+    FOREIGN KEY (approved_by) REFERENCES user_account(id)
+);
+
+INSERT INTO worker_shift_old (id, shift_id, user_account_id, approved_by, status, created_at, updated_at)
+SELECT id, shift_id, user_account_id, approved_by, status, created_at, updated_at FROM worker_shift;
+
+DROP TABLE worker_shift;
+
+ALTER TABLE worker_shift_old RENAME TO worker_shift;
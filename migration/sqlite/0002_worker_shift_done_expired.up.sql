@@ -0,0 +1,21 @@
+-- SQLite cannot alter a CHECK constraint, so the table is rebuilt. The new
+-- status column is left unconstrained; the application owns the state machine.
+CREATE TABLE worker_shift_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    shift_id BIGINT NOT NULL,
+    user_account_id BIGINT NOT NULL,
+    approved_by BIGINT,
+    status TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (shift_id) REFERENCES shift(id),
+    FOREIGN KEY (user_account_id) REFERENCES user_account(id),
+    FOREIGN KEY (approved_by) REFERENCES user_account(id)
+);
+
+INSERT INTO worker_shift_new (id, shift_id, user_account_id, approved_by, status, created_at, updated_at)
+SELECT id, shift_id, user_account_id, approved_by, status, created_at, updated_at FROM worker_shift;
+
+DROP TABLE worker_shift;
+
+ALTER TABLE worker_shift_new RENAME TO worker_shift;
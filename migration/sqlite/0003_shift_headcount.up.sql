@@ -0,0 +1 @@
+ALTER TABLE shift ADD COLUMN headcount INTEGER NOT NULL DEFAULT 1;
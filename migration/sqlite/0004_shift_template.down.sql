@@ -0,0 +1,5 @@
+DROP INDEX uq_shift_template_date;
+
+ALTER TABLE shift DROP COLUMN template_id;
+
+DROP TABLE shift_template;
@@ -0,0 +1,22 @@
+CREATE TABLE shift_template (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(100) NOT NULL,
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    role_assignment TEXT NOT NULL,
+    location VARCHAR(100) NOT NULL,
+    headcount INTEGER NOT NULL DEFAULT 1,
+    weekdays VARCHAR(27) NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NULL,
+    excluded_dates TEXT,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- No REFERENCES clause: SQLite cannot drop a column that takes part in a
+-- foreign key, which the down migration needs to do.
+ALTER TABLE shift ADD COLUMN template_id BIGINT NULL;
+
+CREATE UNIQUE INDEX uq_shift_template_date ON shift (template_id, date);
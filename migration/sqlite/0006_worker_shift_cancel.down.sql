@@ -0,0 +1 @@
+UPDATE worker_shift SET status = 'REJECTED' WHERE status IN ('WITHDRAWN', 'CANCELLED');
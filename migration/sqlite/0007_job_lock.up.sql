@@ -0,0 +1,5 @@
+CREATE TABLE job_lock (
+    name VARCHAR(100) NOT NULL PRIMARY KEY,
+    owner VARCHAR(255) NOT NULL,
+    locked_until DATETIME NOT NULL
+);
@@ -0,0 +1,12 @@
+CREATE TABLE user_session (
+    id VARCHAR(64) NOT NULL PRIMARY KEY,
+    user_account_id BIGINT NOT NULL,
+    refresh_token_hash CHAR(64) NOT NULL,
+    expires_at DATETIME NOT NULL,
+    revoked_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_account_id) REFERENCES user_account(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_user_session_user ON user_session (user_account_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_shift_start_at;
+ALTER TABLE shift DROP COLUMN end_at;
+ALTER TABLE shift DROP COLUMN start_at;
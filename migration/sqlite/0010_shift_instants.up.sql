@@ -0,0 +1,9 @@
+ALTER TABLE shift ADD COLUMN start_at DATETIME;
+ALTER TABLE shift ADD COLUMN end_at DATETIME;
+
+-- An end time not after the start time ends the shift on the next day
+UPDATE shift SET
+    start_at = datetime(date || ' ' || start_time),
+    end_at = datetime(date || ' ' || end_time, CASE WHEN end_time > start_time THEN '+0 days' ELSE '+1 day' END);
+
+CREATE INDEX IF NOT EXISTS idx_shift_start_at ON shift (start_at);
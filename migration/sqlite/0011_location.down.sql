@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS location;
+
+-- Back to naive server local time, as migration 0010 stored them
+UPDATE shift SET
+    start_at = datetime(date || ' ' || start_time),
+    end_at = datetime(date || ' ' || end_time, CASE WHEN end_time > start_time THEN '+0 days' ELSE '+1 day' END);
@@ -0,0 +1,10 @@
+-- Shift instants were stored as server local time until now and are UTC
+-- from here on. The migrator reads them again from each shift's date and
+-- clock times in the default time zone once this script has run.
+CREATE TABLE IF NOT EXISTS location (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    time_zone VARCHAR(64) NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,13 @@
+ALTER TABLE shift_template ADD COLUMN location VARCHAR(100) NOT NULL DEFAULT '';
+UPDATE shift_template SET location = COALESCE((SELECT l.name FROM location l WHERE l.id = shift_template.location_id), '');
+ALTER TABLE shift_template DROP COLUMN location_id;
+
+ALTER TABLE shift ADD COLUMN location VARCHAR(100) NOT NULL DEFAULT '';
+UPDATE shift SET location = COALESCE((SELECT l.name FROM location l WHERE l.id = shift.location_id), '');
+DROP INDEX IF EXISTS idx_shift_location_id;
+ALTER TABLE shift DROP COLUMN location_id;
+
+ALTER TABLE location DROP COLUMN longitude;
+ALTER TABLE location DROP COLUMN latitude;
+ALTER TABLE location DROP COLUMN active;
+ALTER TABLE location DROP COLUMN address;
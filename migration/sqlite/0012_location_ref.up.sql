@@ -0,0 +1,25 @@
+ALTER TABLE location ADD COLUMN address VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE location ADD COLUMN active BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE location ADD COLUMN latitude DOUBLE NULL;
+ALTER TABLE location ADD COLUMN longitude DOUBLE NULL;
+
+-- Every place named by a shift or template becomes a location. Names that
+-- differ only in case or surrounding spaces are the same place; new rows
+-- get the default time zone.
+INSERT INTO location (name, time_zone)
+SELECT MIN(TRIM(u.location)), ''
+FROM (SELECT location FROM shift UNION ALL SELECT location FROM shift_template) u
+WHERE TRIM(u.location) <> ''
+  AND NOT EXISTS (SELECT 1 FROM location l WHERE LOWER(l.name) = LOWER(TRIM(u.location)))
+GROUP BY LOWER(TRIM(u.location));
+
+-- No REFERENCES clause: SQLite cannot drop a column that takes part in a
+-- foreign key, which the down migration needs to do.
+ALTER TABLE shift ADD COLUMN location_id BIGINT NULL;
+UPDATE shift SET location_id = (SELECT MIN(l.id) FROM location l WHERE LOWER(l.name) = LOWER(TRIM(shift.location)));
+CREATE INDEX IF NOT EXISTS idx_shift_location_id ON shift (location_id);
+ALTER TABLE shift DROP COLUMN location;
+
+ALTER TABLE shift_template ADD COLUMN location_id BIGINT NULL;
+UPDATE shift_template SET location_id = (SELECT MIN(l.id) FROM location l WHERE LOWER(l.name) = LOWER(TRIM(shift_template.location)));
+ALTER TABLE shift_template DROP COLUMN location;
@@ -0,0 +1,3 @@
+-- shift.role_assignment keeps its unconstrained type
+DROP TABLE IF EXISTS worker_skill;
+DROP TABLE IF EXISTS skill;
@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS skill (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    code VARCHAR(50) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO skill (code, name) VALUES ('CLEANER', 'Cleaner'), ('CASHIER', 'Cashier');
+
+-- Shift roles are skill codes now. SQLite cannot drop the CHECK constraint
+-- listing the old roles, so the column is replaced; it takes part in no
+-- index or foreign key, which lets it be dropped.
+ALTER TABLE shift ADD COLUMN role_code VARCHAR(50) NOT NULL DEFAULT '';
+UPDATE shift SET role_code = role_assignment;
+ALTER TABLE shift DROP COLUMN role_assignment;
+ALTER TABLE shift RENAME COLUMN role_code TO role_assignment;
+
+CREATE TABLE IF NOT EXISTS worker_skill (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_account_id BIGINT NOT NULL,
+    skill_id BIGINT NOT NULL,
+    expires_on DATE NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_account_id, skill_id),
+    FOREIGN KEY (user_account_id) REFERENCES user_account(id),
+    FOREIGN KEY (skill_id) REFERENCES skill(id)
+);
+
+-- Every worker could take every role so far and keeps doing so
+INSERT INTO worker_skill (user_account_id, skill_id)
+SELECT u.id, s.id FROM user_account u CROSS JOIN skill s WHERE u.role = 'WORKER';
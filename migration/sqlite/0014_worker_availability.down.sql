@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS worker_preferred_role;
+DROP TABLE IF EXISTS worker_preferred_location;
+DROP TABLE IF EXISTS worker_unavailable_date;
+DROP TABLE IF EXISTS worker_availability;
@@ -0,0 +1,3 @@
+ALTER TABLE worker_shift DROP COLUMN needs_reassignment;
+
+DROP TABLE IF EXISTS time_off;
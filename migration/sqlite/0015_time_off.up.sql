@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS time_off (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_account_id BIGINT NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    reviewed_by BIGINT NULL,
+    reviewed_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_account_id) REFERENCES user_account(id),
+    FOREIGN KEY (reviewed_by) REFERENCES user_account(id)
+);
+
+CREATE INDEX idx_time_off_user_dates ON time_off (user_account_id, start_date);
+
+-- Approved shifts clashing with leave approved later, for an admin to reassign
+ALTER TABLE worker_shift ADD COLUMN needs_reassignment BOOLEAN NOT NULL DEFAULT FALSE;
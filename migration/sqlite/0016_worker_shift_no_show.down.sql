@@ -0,0 +1 @@
+UPDATE worker_shift SET status = 'CANCELLED' WHERE status = 'NO_SHOW';
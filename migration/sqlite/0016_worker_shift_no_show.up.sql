@@ -0,0 +1,2 @@
+-- worker_shift.status has no CHECK constraint in SQLite since 0002, so
+-- NO_SHOW needs no schema change here.
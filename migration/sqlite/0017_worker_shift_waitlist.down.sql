@@ -0,0 +1,4 @@
+UPDATE worker_shift SET status = 'REJECTED' WHERE status IN ('WAITLISTED', 'OFFERED');
+
+ALTER TABLE worker_shift DROP COLUMN offer_expires_at;
+ALTER TABLE worker_shift DROP COLUMN waitlist_position;
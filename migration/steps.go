@@ -0,0 +1,81 @@
+package migration
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"dailyworkerroster/model"
+	"dailyworkerroster/rules"
+)
+
+const (
+	dateLayout     = "2006-01-02"
+	dateTimeLayout = "2006-01-02 15:04:05"
+)
+
+// upSteps finish migrations with data changes SQL cannot express, such as
+// time zone conversions. A step runs after the up script of its version, in
+// the same transaction; zone is the zone of locations without one.
+var upSteps = map[int64]func(tx *sql.Tx, zone *time.Location) error{
+	11: anchorShiftInstants,
+}
+
+// anchorShiftInstants stores the shift instants in UTC. Until now they held
+// naive server local time, so they are read again from each shift's date and
+// clock times in zone, where every shift is until it gets a location.
+func anchorShiftInstants(tx *sql.Tx, zone *time.Location) error {
+	rows, err := tx.Query(`SELECT id, date, start_time, end_time FROM shift`)
+	if err != nil {
+		return err
+	}
+	var shifts []*model.Shift
+	for rows.Next() {
+		var shift model.Shift
+		var date interface{}
+		if err := rows.Scan(&shift.ID, &date, &shift.StartTime, &shift.EndTime); err != nil {
+			rows.Close()
+			return err
+		}
+		if shift.Date, err = formatDate(date); err != nil {
+			rows.Close()
+			return fmt.Errorf("shift %d: %w", shift.ID, err)
+		}
+		shifts = append(shifts, &shift)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, shift := range shifts {
+		start, end, err := rules.BoundsIn(shift, zone)
+		if err != nil {
+			return fmt.Errorf("shift %d: %w", shift.ID, err)
+		}
+		_, err = tx.Exec(`UPDATE shift SET start_at = ?, end_at = ? WHERE id = ?`,
+			start.UTC().Format(dateTimeLayout), end.UTC().Format(dateTimeLayout), shift.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatDate turns a DATE column into "YYYY-MM-DD"; drivers hand it back as
+// a time.Time or as text.
+func formatDate(src interface{}) (string, error) {
+	switch v := src.(type) {
+	case time.Time:
+		return v.Format(dateLayout), nil
+	case []byte:
+		return formatDate(string(v))
+	case string:
+		if len(v) < len(dateLayout) {
+			return "", fmt.Errorf("invalid date %q", v)
+		}
+		return v[:len(dateLayout)], nil
+	default:
+		return "", fmt.Errorf("unsupported date value %T", src)
+	}
+}
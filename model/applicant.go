@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// ApplicantRanking lists the pending requests for a shift, best first.
+type ApplicantRanking struct {
+	ShiftID     int64             `json:"shift_id"`
+	Headcount   int               `json:"headcount"`
+	Filled      int               `json:"filled"`
+	PeriodFrom  string            `json:"period_from"`  // YYYY-MM-DD, first day counted for period hours
+	HistoryFrom string            `json:"history_from"` // YYYY-MM-DD, first day counted for cancellations and no-shows
+	PeriodTo    string            `json:"period_to"`    // YYYY-MM-DD, last day of the shift's week
+	Applicants  []*ApplicantScore `json:"applicants"`
+}
+
+// ApplicantScore is how one applicant of a shift ranks against the others.
+// Applicants the labour rules keep off the shift rank after the eligible
+// ones, with the reasons in Ineligible.
+type ApplicantScore struct {
+	Rank          int       `json:"rank"`
+	WorkerShiftID int64     `json:"worker_shift_id"`
+	UserAccountID int64     `json:"user_account_id"`
+	WorkerName    string    `json:"worker_name"`
+	RequestedAt   time.Time `json:"requested_at"`
+	Score         float64   `json:"score"`
+	WeekHours     float64   `json:"week_hours"`   // approved or done in the shift's week
+	PeriodHours   float64   `json:"period_hours"` // approved or done in the period
+	SeniorityDays int       `json:"seniority_days"`
+	Cancellations int       `json:"cancellations"` // since history_from
+	NoShows       int       `json:"no_shows"`      // since history_from
+	Eligible      bool      `json:"eligible"`
+	Ineligible    []string  `json:"ineligible,omitempty"`
+	// AvailabilityConflicts lists how the shift clashes with the worker's
+	// availability calendar; it does not affect the rank
+	AvailabilityConflicts []string `json:"availability_conflicts,omitempty"`
+}
+
+// AutoApproveResult counts the approvals made by one auto-approve run.
+type AutoApproveResult struct {
+	Shifts   int `json:"shifts"`   // shifts that got at least one approval
+	Approved int `json:"approved"` // requests approved
+	Failed   int `json:"failed"`   // shifts left unchanged after an error, retried next run
+}
@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// AvailabilityWindow is a weekly period a worker can work. Times are wall
+// clock times at the shift's location; an end time at or before the start
+// time runs into the next day.
+type AvailabilityWindow struct {
+	ID            int64  `json:"id"`
+	UserAccountID int64  `json:"user_account_id"`
+	Weekday       string `json:"weekday"` // MON, TUE, WED, THU, FRI, SAT or SUN
+	StartTime     string `json:"start_time"`
+	EndTime       string `json:"end_time"`
+}
+
+// UnavailableDate is a day a worker cannot work at all.
+type UnavailableDate struct {
+	ID            int64     `json:"id"`
+	UserAccountID int64     `json:"user_account_id"`
+	Date          string    `json:"date"` // YYYY-MM-DD
+	Reason        string    `json:"reason"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// WorkerAvailability is a worker's availability calendar and preferences.
+// A worker without windows is available any day they have not marked
+// unavailable.
+type WorkerAvailability struct {
+	UserAccountID        int64                 `json:"user_account_id"`
+	Windows              []*AvailabilityWindow `json:"windows"`
+	UnavailableDates     []*UnavailableDate    `json:"unavailable_dates"` // today and later
+	PreferredLocationIDs []int64               `json:"preferred_location_ids"`
+	PreferredRoles       []string              `json:"preferred_roles"` // skill codes
+}
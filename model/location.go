@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// Location is a site shifts take place at. Shift times are wall clock times
+// in its time zone.
+type Location struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"` // unique regardless of case
+	Address   string    `json:"address"`
+	TimeZone  string    `json:"time_zone"` // IANA zone, e.g. Europe/Amsterdam; empty for the default zone
+	Active    bool      `json:"active"`    // inactive locations get no new shifts or templates
+	Latitude  *float64  `json:"latitude"`  // nullable, set together with longitude
+	Longitude *float64  `json:"longitude"` // nullable
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,51 @@
+package model
+
+// RosterQuery selects the open shifts a roster proposal fills.
+type RosterQuery struct {
+	DateFrom   string // YYYY-MM-DD
+	DateTo     string // YYYY-MM-DD, included
+	LocationID *int64
+}
+
+// RosterChange is a pending request in a roster proposal: approved, waitlisted
+// because its shift fills up, or left pending with the reasons why.
+type RosterChange struct {
+	WorkerShiftID  int64    `json:"worker_shift_id"`
+	ShiftID        int64    `json:"shift_id"`
+	UserAccountID  int64    `json:"user_account_id"`
+	WorkerName     string   `json:"worker_name"`
+	Date           string   `json:"date"`
+	StartTime      string   `json:"start_time"`
+	EndTime        string   `json:"end_time"`
+	RoleAssignment string   `json:"role_assignment"`
+	Location       string   `json:"location"`
+	From           string   `json:"from"`              // PENDING
+	To             string   `json:"to"`                // APPROVED, WAITLISTED, or PENDING when left as is
+	Reasons        []string `json:"reasons,omitempty"` // why a request is not approved
+}
+
+// RosterShift is the coverage of an open shift before and after a proposal.
+type RosterShift struct {
+	ShiftID        int64  `json:"shift_id"`
+	Date           string `json:"date"`
+	StartTime      string `json:"start_time"`
+	EndTime        string `json:"end_time"`
+	RoleAssignment string `json:"role_assignment"`
+	Location       string `json:"location"`
+	Headcount      int    `json:"headcount"`
+	FilledBefore   int    `json:"filled_before"`
+	FilledAfter    int    `json:"filled_after"`
+}
+
+// RosterProposal is a set of approvals filling open places of the selected
+// shifts. Nothing is saved until its Approve list is committed.
+type RosterProposal struct {
+	DateFrom   string          `json:"date_from"`
+	DateTo     string          `json:"date_to"`
+	Open       int             `json:"open"`       // places to fill before the proposal
+	Filled     int             `json:"filled"`     // places the proposal fills
+	Approve    []int64         `json:"approve"`    // worker shift IDs to commit
+	Changes    []*RosterChange `json:"changes"`    // requests approved or waitlisted by the proposal
+	Unassigned []*RosterChange `json:"unassigned"` // requests that stay pending
+	Shifts     []*RosterShift  `json:"shifts"`
+}
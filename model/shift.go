@@ -7,29 +7,49 @@ type Shift struct {
 	Date           string    `json:"date"`
 	StartTime      string    `json:"start_time"`
 	EndTime        string    `json:"end_time"`
+	StartAt        time.Time `json:"start_at"`  // derived from date and start_time unless given
+	EndAt          time.Time `json:"end_at"`    // after start_at, on the next day for overnight shifts
+	TimeZone       string    `json:"time_zone"` // zone of the location, date and times are wall clock in it
 	RoleAssignment string    `json:"role_assignment"`
-	Location       string    `json:"location"`
+	LocationID     int64     `json:"location_id"`
+	Location       string    `json:"location"` // name of the location, used to find it when location_id is not given
 	IsAvailable    bool      `json:"isAvailable"`
+	Headcount      int       `json:"headcount"`   // number of workers required, defaults to 1
+	TemplateID     *int64    `json:"template_id"` // set when generated from a shift template
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
 }
 
 type ShiftStatus struct {
-	ID             int64  `json:"id"`
-	Date           string `json:"date"`
-	StartTime      string `json:"start_time"`
-	EndTime        string `json:"end_time"`
-	RoleAssignment string `json:"role_assignment"`
-	Location       string `json:"location"`
-	IsAvailable    bool   `json:"isAvailable"`
-	StatusWorker   string `json:"status_worker"`
+	ID              int64     `json:"id"`
+	Date            string    `json:"date"`
+	StartTime       string    `json:"start_time"`
+	EndTime         string    `json:"end_time"`
+	StartAt         time.Time `json:"start_at"`
+	EndAt           time.Time `json:"end_at"`
+	TimeZone        string    `json:"time_zone"`
+	RoleAssignment  string    `json:"role_assignment"`
+	LocationID      int64     `json:"location_id"`
+	Location        string    `json:"location"`
+	IsAvailable     bool      `json:"isAvailable"`
+	Headcount       int       `json:"headcount"`
+	Filled          int       `json:"filled"`                     // approved workers
+	StatusWorker    string    `json:"status_worker,omitempty"`    // the requesting worker's own status
+	PreferenceScore int       `json:"preference_score,omitempty"` // preferences of the requesting worker it matches
+	// The requesting worker's place on the waitlist, and the deadline of a
+	// place offered to them
+	WaitlistPosition *int       `json:"waitlist_position,omitempty"`
+	OfferExpiresAt   *time.Time `json:"offer_expires_at,omitempty"`
 }
 
 type ShiftListQuery struct {
 	Limit          int
 	Offset         int
 	RoleAssignment string
-	Location       string
+	LocationID     int64
 	Date           string
 	IsAvailable    *bool
+	TemplateID     *int64
+	DateFrom       string
+	DateTo         string
 }
@@ -0,0 +1,23 @@
+package model
+
+// ShiftLifecycleResult counts the transitions made by one lifecycle run.
+type ShiftLifecycleResult struct {
+	Done          int `json:"done"`           // APPROVED -> DONE, still clocked in when clocking out closed
+	NoShows       int `json:"no_shows"`       // APPROVED -> NO_SHOW, never clocked in
+	Expired       int `json:"expired"`        // PENDING or WAITLISTED -> EXPIRED, shift has started
+	OffersExpired int `json:"offers_expired"` // OFFERED -> EXPIRED, deadline passed or shift started
+	Offered       int `json:"offered"`        // WAITLISTED -> OFFERED, in place of expired offers
+	Closed        int `json:"closed"`         // shifts no longer available because they started
+	Failed        int `json:"failed"`         // shifts left unchanged after an error, retried next run
+}
+
+// Add counts the transitions of another run, or of one shift, in r.
+func (r *ShiftLifecycleResult) Add(other ShiftLifecycleResult) {
+	r.Done += other.Done
+	r.NoShows += other.NoShows
+	r.Expired += other.Expired
+	r.OffersExpired += other.OffersExpired
+	r.Offered += other.Offered
+	r.Closed += other.Closed
+	r.Failed += other.Failed
+}
@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+const (
+	// What happens to future generated shifts when a template is edited
+	TEMPLATE_FUTURE_KEEP    = "keep"    // leave them as they are
+	TEMPLATE_FUTURE_UPDATE  = "update"  // sync them with the template
+	TEMPLATE_FUTURE_REPLACE = "replace" // delete them and generate fresh ones
+)
+
+// ShiftTemplate describes a recurring shift. Weekdays use three-letter
+// upper-case names (MON..SUN); dates are YYYY-MM-DD.
+type ShiftTemplate struct {
+	ID             int64     `json:"id"`
+	Name           string    `json:"name"`
+	StartTime      string    `json:"start_time"`
+	EndTime        string    `json:"end_time"`
+	RoleAssignment string    `json:"role_assignment"`
+	LocationID     int64     `json:"location_id"`
+	Location       string    `json:"location"` // name of the location, used to find it when location_id is not given
+	Headcount      int       `json:"headcount"`
+	Weekdays       []string  `json:"weekdays"`
+	StartDate      string    `json:"start_date"`
+	EndDate        *string   `json:"end_date"` // nullable, open ended
+	ExcludedDates  []string  `json:"excluded_dates"`
+	IsActive       bool      `json:"is_active"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type ShiftGenerationConflict struct {
+	ShiftID int64  `json:"shift_id"`
+	Date    string `json:"date"`
+	Reason  string `json:"reason"`
+}
+
+// ShiftGenerationResult reports what materialising a template changed
+type ShiftGenerationResult struct {
+	TemplateID int64                     `json:"template_id"`
+	From       string                    `json:"from"`
+	Until      string                    `json:"until"`
+	Created    []int64                   `json:"created"`
+	Updated    []int64                   `json:"updated"`
+	Deleted    []int64                   `json:"deleted"`
+	Conflicts  []ShiftGenerationConflict `json:"conflicts"`
+}
@@ -0,0 +1,61 @@
+package model
+
+import "time"
+
+const (
+	TRANSFER_OFFERED   = "OFFERED"   // posted by the assigned worker
+	TRANSFER_ACCEPTED  = "ACCEPTED"  // a colleague agreed to take it, waiting for an admin
+	TRANSFER_APPROVED  = "APPROVED"  // the worker shift was reassigned
+	TRANSFER_REJECTED  = "REJECTED"  // turned down by an admin
+	TRANSFER_CANCELLED = "CANCELLED" // withdrawn by the offering worker
+)
+
+// ShiftTransfer hands an approved worker shift to another worker. ToUserID
+// names the colleague it is offered to; nil posts it for anyone.
+type ShiftTransfer struct {
+	ID            int64     `json:"id"`
+	WorkerShiftID int64     `json:"worker_shift_id"`
+	FromUserID    int64     `json:"from_user_id"`
+	ToUserID      *int64    `json:"to_user_id"` // nullable
+	TakerID       *int64    `json:"taker_id"`   // nullable
+	Status        string    `json:"status"`     // OFFERED, ACCEPTED, APPROVED, REJECTED, CANCELLED
+	DecidedBy     *int64    `json:"decided_by"` // nullable
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type ShiftTransferDetail struct {
+	ShiftTransfer
+	ShiftID        int64  `json:"shift_id"`
+	Date           string `json:"date"`
+	StartTime      string `json:"start_time"`
+	EndTime        string `json:"end_time"`
+	RoleAssignment string `json:"role_assignment"`
+	LocationID     int64  `json:"location_id"`
+	Location       string `json:"location"`
+}
+
+type ShiftTransferQuery struct {
+	Status     *string
+	FromUserID *int64
+	// OpenFor lists offers a worker may accept: open ones and the ones
+	// naming them, excluding their own
+	OpenFor *int64
+}
+
+type WorkerShiftHistory struct {
+	ID            int64     `json:"id"`
+	WorkerShiftID int64     `json:"worker_shift_id"`
+	FromUserID    int64     `json:"from_user_id"`
+	ToUserID      int64     `json:"to_user_id"`
+	TransferID    *int64    `json:"transfer_id"` // nullable
+	ChangedBy     *int64    `json:"changed_by"`  // nullable
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// WorkerTransfers is a worker's view of transfers: the shifts they offered
+// and the offers they can accept.
+type WorkerTransfers struct {
+	Offered   []ShiftTransferDetail `json:"offered"`
+	Available []ShiftTransferDetail `json:"available"`
+}
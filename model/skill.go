@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// Skill is an entry of the role catalogue. A shift requires the skill whose
+// code is its role_assignment.
+type Skill struct {
+	ID          int64     `json:"id"`
+	Code        string    `json:"code"` // upper case, e.g. CASHIER; cannot change
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Active      bool      `json:"active"` // inactive skills get no new shifts, templates or workers
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// WorkerSkill records that a worker is qualified for a skill.
+type WorkerSkill struct {
+	ID            int64     `json:"id"`
+	UserAccountID int64     `json:"user_account_id"`
+	SkillID       int64     `json:"skill_id"`
+	SkillCode     string    `json:"skill_code"`
+	SkillName     string    `json:"skill_name"`
+	ExpiresOn     *string   `json:"expires_on"` // YYYY-MM-DD, last day a certification is valid; nullable, never expires
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
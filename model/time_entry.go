@@ -0,0 +1,74 @@
+package model
+
+import "time"
+
+// TimeEntry is when a worker actually worked an approved shift: clocked in,
+// on breaks and clocked out. Late, early leave, break and worked minutes
+// are derived from the times and the shift.
+type TimeEntry struct {
+	ID               int64             `json:"id"`
+	WorkerShiftID    int64             `json:"worker_shift_id"`
+	ClockInAt        time.Time         `json:"clock_in_at"`
+	ClockOutAt       *time.Time        `json:"clock_out_at"`   // nullable, still working
+	AutoClockOut     bool              `json:"auto_clock_out"` // clocked out at the scheduled end by the scheduler
+	CorrectedBy      *int64            `json:"corrected_by"`   // nullable, admin who last corrected the times
+	CorrectionReason string            `json:"correction_reason"`
+	CorrectedAt      *time.Time        `json:"corrected_at"` // nullable
+	Breaks           []*TimeEntryBreak `json:"breaks"`
+	CreatedAt        time.Time         `json:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+
+	Late              bool `json:"late"`                // clocked in after the late tolerance
+	LateMinutes       int  `json:"late_minutes"`        // minutes after the scheduled start
+	LeftEarly         bool `json:"left_early"`          // clocked out before the early leave tolerance
+	EarlyLeaveMinutes int  `json:"early_leave_minutes"` // minutes before the scheduled end
+	BreakMinutes      int  `json:"break_minutes"`
+	WorkedMinutes     int  `json:"worked_minutes"` // clocked time less breaks, up to now while working
+}
+
+// TimeEntryBreak is a break within a time entry; EndAt is nil while it lasts.
+type TimeEntryBreak struct {
+	ID          int64      `json:"id"`
+	TimeEntryID int64      `json:"time_entry_id"`
+	StartAt     time.Time  `json:"start_at"`
+	EndAt       *time.Time `json:"end_at"` // nullable
+}
+
+// TimeEntryCorrection replaces the times of a time entry. Breaks left out
+// are kept; an empty list removes them.
+type TimeEntryCorrection struct {
+	ClockInAt  time.Time         `json:"clock_in_at"`
+	ClockOutAt *time.Time        `json:"clock_out_at"`
+	Breaks     []*TimeEntryBreak `json:"breaks"`
+	Reason     string            `json:"reason"`
+}
+
+// AttendanceQuery selects the settled shifts, DONE or NO_SHOW, dated in a
+// range, optionally of one worker.
+type AttendanceQuery struct {
+	DateFrom      string // YYYY-MM-DD
+	DateTo        string // YYYY-MM-DD, included
+	UserAccountID *int64
+}
+
+// AttendanceStats sums up how workers turned up for their shifts.
+type AttendanceStats struct {
+	DateFrom string              `json:"date_from"`
+	DateTo   string              `json:"date_to"`
+	Workers  []*WorkerAttendance `json:"workers"`
+}
+
+// WorkerAttendance is the attendance of one worker in the range.
+type WorkerAttendance struct {
+	UserAccountID     int64   `json:"user_account_id"`
+	WorkerName        string  `json:"worker_name"`
+	Shifts            int     `json:"shifts"`     // done or no-show
+	ClockedIn         int     `json:"clocked_in"` // shifts with a time entry
+	Late              int     `json:"late"`
+	LateMinutes       int     `json:"late_minutes"` // over the late shifts
+	EarlyLeaves       int     `json:"early_leaves"`
+	EarlyLeaveMinutes int     `json:"early_leave_minutes"` // over the shifts left early
+	NoShows           int     `json:"no_shows"`
+	ScheduledHours    float64 `json:"scheduled_hours"`
+	WorkedHours       float64 `json:"worked_hours"` // clocked time less breaks
+}
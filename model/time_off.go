@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+const (
+	TIME_OFF_PENDING  = "PENDING"
+	TIME_OFF_APPROVED = "APPROVED"
+	TIME_OFF_REJECTED = "REJECTED"
+)
+
+const (
+	TIME_OFF_VACATION = "VACATION"
+	TIME_OFF_SICK     = "SICK"
+	TIME_OFF_PERSONAL = "PERSONAL"
+	TIME_OFF_UNPAID   = "UNPAID"
+)
+
+// TimeOff is a worker's leave over a range of days. Approved leave keeps
+// the worker from getting shifts on those days.
+type TimeOff struct {
+	ID            int64      `json:"id"`
+	UserAccountID int64      `json:"user_account_id"`
+	Type          string     `json:"type"`       // VACATION, SICK, PERSONAL or UNPAID
+	StartDate     string     `json:"start_date"` // YYYY-MM-DD
+	EndDate       string     `json:"end_date"`   // YYYY-MM-DD, last day of the leave
+	Reason        string     `json:"reason"`
+	Status        string     `json:"status"`      // PENDING, APPROVED, REJECTED
+	ReviewedBy    *int64     `json:"reviewed_by"` // nullable
+	ReviewedAt    *time.Time `json:"reviewed_at"` // nullable
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TimeOffQuery filters time off; DateFrom and DateTo select leave
+// overlapping that range.
+type TimeOffQuery struct {
+	UserAccountID *int64
+	Status        *string
+	DateFrom      *string
+	DateTo        *string
+}
@@ -9,13 +9,16 @@ const (
 )
 
 type User struct {
-	ID        int64     `json:"id"`
-	Name      string    `json:"name"`
-	Username  string    `json:"username"`
-	Email     string    `json:"email"`
-	Password  string    `json:"password"`
-	Role      string    `json:"role"` // ADMIN, WORKER
-	JWTToken  string    `json:"jwt_token"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID           int64     `json:"id"`
+	Name         string    `json:"name"`
+	Username     string    `json:"username"`
+	Email        string    `json:"email"`
+	Password     string    `json:"password"`
+	Role         string    `json:"role"`          // ADMIN, WORKER
+	DateOfBirth  *string   `json:"date_of_birth"` // YYYY-MM-DD, nullable; used by age-based labour rules
+	JWTToken     string    `json:"jwt_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"` // set on login only
+	ExpiresIn    int64     `json:"expires_in,omitempty"`    // access token lifetime in seconds, set on login only
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
 }
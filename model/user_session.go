@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// UserSession backs the refresh token of one login. Access tokens carry its
+// ID, so revoking the session also invalidates them.
+type UserSession struct {
+	ID                string     `json:"id"`
+	UserAccountID     int64      `json:"user_account_id"`
+	RefreshTokenHash  string     `json:"-"`
+	PreviousTokenHash *string    `json:"-"` // nullable, the refresh token the last rotation replaced
+	ExpiresAt         time.Time  `json:"expires_at"`
+	RevokedAt         *time.Time `json:"revoked_at"` // nullable
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+type TokenPair struct {
+	JWTToken     string `json:"jwt_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"` // access token lifetime in seconds
+}
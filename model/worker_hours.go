@@ -0,0 +1,19 @@
+package model
+
+// WorkerHours summarises the hours a worker has booked per week.
+type WorkerHours struct {
+	WorkerID int64       `json:"worker_id"`
+	Weeks    []WeekHours `json:"weeks"` // current week first, then next week
+}
+
+// WeekHours is the hours booked in one Monday to Sunday week. Hours are
+// derived from the shifts' start and end times.
+type WeekHours struct {
+	WeekStart      string   `json:"week_start"` // Monday, YYYY-MM-DD
+	WeekEnd        string   `json:"week_end"`   // Sunday, YYYY-MM-DD
+	Shifts         int      `json:"shifts"`     // approved or done
+	BookedHours    float64  `json:"booked_hours"`
+	PendingHours   float64  `json:"pending_hours"`
+	MaxHours       *float64 `json:"max_hours"`       // tightest weekly cap of the week's shifts, null when disabled
+	RemainingHours *float64 `json:"remaining_hours"` // null when there is no cap
+}
@@ -8,8 +8,15 @@ const (
 	WORKER_SHIFT_REJECTED = "REJECTED"
 	WORKER_SHIFT_DONE     = "DONE"
 	WORKER_SHIFT_EXPIRED  = "EXPIRED"
-
-	MAXIMUM_WORKER_SHIFT_WEEK = 5
+	// Set by the worker: WITHDRAWN for a pending request, CANCELLED for an approved shift
+	WORKER_SHIFT_WITHDRAWN = "WITHDRAWN"
+	WORKER_SHIFT_CANCELLED = "CANCELLED"
+	// Set by an admin on an approved shift the worker did not turn up for
+	WORKER_SHIFT_NO_SHOW = "NO_SHOW"
+	// Pending requests left over when a shift fills wait for a place in
+	// order; a vacated place is OFFERED to the next one until its deadline
+	WORKER_SHIFT_WAITLISTED = "WAITLISTED"
+	WORKER_SHIFT_OFFERED    = "OFFERED"
 )
 
 type WorkerShift struct {
@@ -20,6 +27,11 @@ type WorkerShift struct {
 	Status        string    `json:"status"`      // PENDING, APPROVED, REJECTED
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
+
+	// Set while WAITLISTED or OFFERED: the place on the waitlist, lowest
+	// first, and the time by which an offer must be accepted
+	WaitlistPosition *int       `json:"waitlist_position,omitempty"`
+	OfferExpiresAt   *time.Time `json:"offer_expires_at,omitempty"`
 }
 
 type ListShiftDetail struct {
@@ -29,24 +41,58 @@ type ListShiftDetail struct {
 }
 
 type WorkerShiftDetail struct {
-	ID             int64  `json:"id"`
-	ShiftID        int64  `json:"shift_id"`
-	ApprovedBy     *int64 `json:"approved_by"` // nullable
-	Status         string `json:"status"`      // PENDING, APPROVED, REJECTED
-	Date           string `json:"date"`
-	StartTime      string `json:"start_time"`
-	EndTime        string `json:"end_time"`
-	RoleAssignment string `json:"role_assignment"`
-	Location       string `json:"location"`
-	IsAvailable    bool   `json:"isAvailable"`
-	UserAccountID  int64  `json:"user_account_id"`
+	ID             int64     `json:"id"`
+	ShiftID        int64     `json:"shift_id"`
+	ApprovedBy     *int64    `json:"approved_by"` // nullable
+	Status         string    `json:"status"`      // PENDING, APPROVED, REJECTED
+	Date           string    `json:"date"`
+	StartTime      string    `json:"start_time"`
+	EndTime        string    `json:"end_time"`
+	StartAt        time.Time `json:"start_at"`
+	EndAt          time.Time `json:"end_at"`
+	TimeZone       string    `json:"time_zone"`
+	RoleAssignment string    `json:"role_assignment"`
+	LocationID     int64     `json:"location_id"`
+	Location       string    `json:"location"`
+	IsAvailable    bool      `json:"isAvailable"`
+	Headcount      int       `json:"headcount"`
+	UserAccountID  int64     `json:"user_account_id"`
+	// NeedsReassignment is set on approved shifts clashing with leave
+	// approved afterwards
+	NeedsReassignment bool       `json:"needs_reassignment"`
+	WaitlistPosition  *int       `json:"waitlist_position,omitempty"`
+	OfferExpiresAt    *time.Time `json:"offer_expires_at,omitempty"`
+	// AvailabilityConflicts lists how a pending request clashes with the
+	// worker's availability calendar
+	AvailabilityConflicts []string `json:"availability_conflicts,omitempty"`
+}
+
+// AsShift returns the shift the detail belongs to.
+func (d *WorkerShiftDetail) AsShift() *Shift {
+	return &Shift{
+		ID:             d.ShiftID,
+		Date:           d.Date,
+		StartTime:      d.StartTime,
+		EndTime:        d.EndTime,
+		StartAt:        d.StartAt,
+		EndAt:          d.EndAt,
+		TimeZone:       d.TimeZone,
+		RoleAssignment: d.RoleAssignment,
+		LocationID:     d.LocationID,
+		Location:       d.Location,
+		IsAvailable:    d.IsAvailable,
+		Headcount:      d.Headcount,
+	}
 }
 
 type WorkerShiftDetailQuery struct {
-	UserAccountID *int64
-	Status        *string
-	Role          *string
-	Location      *string
-	Limit         *int
-	Offset        *int
+	UserAccountID     *int64
+	Status            *string
+	Role              *string
+	LocationID        *int64
+	NeedsReassignment *bool
+	DateFrom          *string
+	DateTo            *string
+	Limit             *int
+	Offset            *int
 }
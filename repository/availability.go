@@ -0,0 +1,221 @@
+package repository
+
+import (
+	model "dailyworkerroster/model"
+)
+
+type AvailabilityRepoItf interface {
+	ListAvailabilityWindows(userAccountID int64) ([]*model.AvailabilityWindow, error)
+	// ReplaceAvailabilityWindows swaps the worker's weekly windows for the given ones
+	ReplaceAvailabilityWindows(userAccountID int64, windows []*model.AvailabilityWindow) error
+
+	ListUnavailableDates(userAccountID int64, from string) ([]*model.UnavailableDate, error)
+	GetUnavailableDate(userAccountID int64, date string) (*model.UnavailableDate, error)
+	CreateUnavailableDate(unavailable *model.UnavailableDate) (int64, error)
+	UpdateUnavailableDate(unavailable *model.UnavailableDate) error
+	DeleteUnavailableDate(userAccountID int64, date string) error
+
+	ListPreferredLocations(userAccountID int64) ([]int64, error)
+	ReplacePreferredLocations(userAccountID int64, locationIDs []int64) error
+	ListPreferredRoles(userAccountID int64) ([]string, error)
+	ReplacePreferredRoles(userAccountID int64, skillIDs []int64) error
+}
+
+type AvailabilityRepository struct {
+	DB      DBTX
+	Dialect Dialect
+}
+
+func NewAvailabilityRepository(db DBTX) AvailabilityRepoItf {
+	return &AvailabilityRepository{DB: db, Dialect: DialectMySQL}
+}
+
+func NewSQLiteAvailabilityRepository(db DBTX) AvailabilityRepoItf {
+	return &AvailabilityRepository{DB: db, Dialect: DialectSQLite}
+}
+
+func (r *AvailabilityRepository) ListAvailabilityWindows(userAccountID int64) ([]*model.AvailabilityWindow, error) {
+	query := `
+        SELECT id, user_account_id, weekday, start_time, end_time
+        FROM worker_availability
+        WHERE user_account_id = ?
+        ORDER BY id
+    `
+	rows, err := r.DB.Query(query, userAccountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := make([]*model.AvailabilityWindow, 0)
+	for rows.Next() {
+		var window model.AvailabilityWindow
+		if err := rows.Scan(&window.ID, &window.UserAccountID, &window.Weekday, &window.StartTime, &window.EndTime); err != nil {
+			return nil, err
+		}
+		list = append(list, &window)
+	}
+	return list, nil
+}
+
+func (r *AvailabilityRepository) ReplaceAvailabilityWindows(userAccountID int64, windows []*model.AvailabilityWindow) error {
+	if _, err := r.DB.Exec(`DELETE FROM worker_availability WHERE user_account_id = ?`, userAccountID); err != nil {
+		return err
+	}
+	query := `
+        INSERT INTO worker_availability (user_account_id, weekday, start_time, end_time, created_at)
+        VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)
+    `
+	for _, window := range windows {
+		result, err := r.DB.Exec(query, userAccountID, window.Weekday, window.StartTime, window.EndTime)
+		if err != nil {
+			return err
+		}
+		if window.ID, err = result.LastInsertId(); err != nil {
+			return err
+		}
+		window.UserAccountID = userAccountID
+	}
+	return nil
+}
+
+const unavailableDateQuery = `
+        SELECT id, user_account_id, date, reason, created_at, updated_at
+        FROM worker_unavailable_date
+    `
+
+func scanUnavailableDate(row rowScanner) (*model.UnavailableDate, error) {
+	var unavailable model.UnavailableDate
+	err := row.Scan(
+		&unavailable.ID, &unavailable.UserAccountID, (*dateColumn)(&unavailable.Date), &unavailable.Reason,
+		&unavailable.CreatedAt, &unavailable.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &unavailable, nil
+}
+
+func (r *AvailabilityRepository) ListUnavailableDates(userAccountID int64, from string) ([]*model.UnavailableDate, error) {
+	query := unavailableDateQuery + ` WHERE user_account_id = ? AND date >= ? ORDER BY date`
+	rows, err := r.DB.Query(query, userAccountID, from)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := make([]*model.UnavailableDate, 0)
+	for rows.Next() {
+		unavailable, err := scanUnavailableDate(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, unavailable)
+	}
+	return list, nil
+}
+
+func (r *AvailabilityRepository) GetUnavailableDate(userAccountID int64, date string) (*model.UnavailableDate, error) {
+	query := unavailableDateQuery + ` WHERE user_account_id = ? AND date = ?`
+	return scanUnavailableDate(r.DB.QueryRow(query, userAccountID, date))
+}
+
+func (r *AvailabilityRepository) CreateUnavailableDate(unavailable *model.UnavailableDate) (int64, error) {
+	query := `
+        INSERT INTO worker_unavailable_date (user_account_id, date, reason, created_at, updated_at)
+        VALUES (?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+    `
+	result, err := r.DB.Exec(query, unavailable.UserAccountID, unavailable.Date, unavailable.Reason)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+func (r *AvailabilityRepository) UpdateUnavailableDate(unavailable *model.UnavailableDate) error {
+	query := `
+        UPDATE worker_unavailable_date SET reason = ?, updated_at = CURRENT_TIMESTAMP
+        WHERE id = ?
+    `
+	_, err := r.DB.Exec(query, unavailable.Reason, unavailable.ID)
+	return err
+}
+
+func (r *AvailabilityRepository) DeleteUnavailableDate(userAccountID int64, date string) error {
+	_, err := r.DB.Exec(`DELETE FROM worker_unavailable_date WHERE user_account_id = ? AND date = ?`, userAccountID, date)
+	return err
+}
+
+func (r *AvailabilityRepository) ListPreferredLocations(userAccountID int64) ([]int64, error) {
+	query := `SELECT location_id FROM worker_preferred_location WHERE user_account_id = ? ORDER BY location_id`
+	return r.listIDs(query, userAccountID)
+}
+
+func (r *AvailabilityRepository) ReplacePreferredLocations(userAccountID int64, locationIDs []int64) error {
+	if _, err := r.DB.Exec(`DELETE FROM worker_preferred_location WHERE user_account_id = ?`, userAccountID); err != nil {
+		return err
+	}
+	for _, id := range locationIDs {
+		query := `INSERT INTO worker_preferred_location (user_account_id, location_id) VALUES (?, ?)`
+		if _, err := r.DB.Exec(query, userAccountID, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *AvailabilityRepository) ListPreferredRoles(userAccountID int64) ([]string, error) {
+	query := `
+        SELECT s.code
+        FROM worker_preferred_role p
+        JOIN skill s ON p.skill_id = s.id
+        WHERE p.user_account_id = ?
+        ORDER BY s.code
+    `
+	rows, err := r.DB.Query(query, userAccountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	codes := make([]string, 0)
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+func (r *AvailabilityRepository) ReplacePreferredRoles(userAccountID int64, skillIDs []int64) error {
+	if _, err := r.DB.Exec(`DELETE FROM worker_preferred_role WHERE user_account_id = ?`, userAccountID); err != nil {
+		return err
+	}
+	for _, id := range skillIDs {
+		query := `INSERT INTO worker_preferred_role (user_account_id, skill_id) VALUES (?, ?)`
+		if _, err := r.DB.Exec(query, userAccountID, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *AvailabilityRepository) listIDs(query string, args ...interface{}) ([]int64, error) {
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
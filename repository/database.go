@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"database/sql"
+	"strings"
+
+	"dailyworkerroster/config"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Dialect identifies the SQL flavour a repository talks to.
+type Dialect string
+
+const (
+	DialectMySQL  Dialect = "mysql"
+	DialectSQLite Dialect = "sqlite3"
+
+	sqliteScheme = "sqlite://"
+)
+
+// ForUpdate returns the row locking clause for the dialect. SQLite has no
+// row locks; writers are serialized by opening every transaction with
+// BEGIN IMMEDIATE instead (see Open).
+func (d Dialect) ForUpdate() string {
+	if d == DialectSQLite {
+		return ""
+	}
+	return "FOR UPDATE"
+}
+
+// InsertIgnore returns the INSERT variant that skips rows clashing with an
+// existing key instead of failing.
+func (d Dialect) InsertIgnore() string {
+	if d == DialectSQLite {
+		return "INSERT OR IGNORE"
+	}
+	return "INSERT IGNORE"
+}
+
+// Open connects to the database described by cfg.DSN. A "sqlite://" prefix
+// selects the embedded SQLite backend, e.g. "sqlite://roster.db" or
+// "sqlite://:memory:"; anything else is treated as a MySQL DSN.
+func Open(cfg config.DatabaseConfig) (*sql.DB, Dialect, error) {
+	if !strings.HasPrefix(cfg.DSN, sqliteScheme) {
+		db, err := sql.Open(string(DialectMySQL), cfg.DSN)
+		if err != nil {
+			return nil, DialectMySQL, err
+		}
+		db.SetMaxOpenConns(cfg.MaxOpenConns)
+		db.SetMaxIdleConns(cfg.MaxIdleConns)
+		db.SetConnMaxLifetime(cfg.ConnMaxLifetime.Std())
+		return db, DialectMySQL, nil
+	}
+
+	path := strings.TrimPrefix(cfg.DSN, sqliteScheme)
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	path += separator + "_foreign_keys=on&_busy_timeout=5000&_txlock=immediate"
+
+	db, err := sql.Open(string(DialectSQLite), path)
+	if err != nil {
+		return nil, DialectSQLite, err
+	}
+	// A single connection keeps ":memory:" databases alive and avoids
+	// SQLITE_BUSY between writers of the same process.
+	db.SetMaxOpenConns(1)
+	return db, DialectSQLite, nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"time"
+)
+
+type JobLockRepoItf interface {
+	AcquireJobLock(name, owner string, now, until time.Time) (bool, error)
+}
+
+// JobLockRepository keeps one lease row per background job so that only one
+// replica runs a job at a time. Times are stored in UTC.
+type JobLockRepository struct {
+	DB      DBTX
+	Dialect Dialect
+}
+
+func NewJobLockRepository(db DBTX) JobLockRepoItf {
+	return &JobLockRepository{DB: db, Dialect: DialectMySQL}
+}
+
+func NewSQLiteJobLockRepository(db DBTX) JobLockRepoItf {
+	return &JobLockRepository{DB: db, Dialect: DialectSQLite}
+}
+
+// AcquireJobLock takes or renews the lease on name until the given time. It
+// succeeds when the lease is free, expired, or already held by owner.
+func (r *JobLockRepository) AcquireJobLock(name, owner string, now, until time.Time) (bool, error) {
+	now = now.UTC().Truncate(time.Second)
+	until = until.UTC().Truncate(time.Second)
+
+	_, err := r.DB.Exec(r.Dialect.InsertIgnore()+` INTO job_lock (name, owner, locked_until) VALUES (?, '', ?)`, name, now)
+	if err != nil {
+		return false, err
+	}
+
+	query := `
+        UPDATE job_lock
+        SET owner = ?, locked_until = ?
+        WHERE name = ? AND (locked_until <= ? OR owner = ?)
+    `
+	result, err := r.DB.Exec(query, owner, until, name, now, owner)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	model "dailyworkerroster/model"
+)
+
+type LocationRepoItf interface {
+	CreateLocation(location *model.Location) (int64, error)
+	GetLocationByID(id int64) (*model.Location, error)
+	GetLocationByIDForUpdate(id int64) (*model.Location, error)
+	GetLocationByName(name string) (*model.Location, error)
+	ListLocations(active *bool) ([]*model.Location, error)
+	UpdateLocation(location *model.Location) error
+	DeleteLocation(id int64) error
+	IsLocationInUse(id int64) (bool, error)
+}
+
+const locationColumns = "id, name, address, time_zone, active, latitude, longitude, created_at, updated_at"
+
+// locationRef selects the ID, name and time zone of the location the rows
+// of table refer to. Rows from before locations were required have none.
+func locationRef(table string) string {
+	return "COALESCE(" + table + ".location_id, 0), " +
+		"COALESCE((SELECT l.name FROM location l WHERE l.id = " + table + ".location_id), ''), " +
+		"(SELECT l.time_zone FROM location l WHERE l.id = " + table + ".location_id)"
+}
+
+func scanLocation(row rowScanner) (*model.Location, error) {
+	var location model.Location
+	err := row.Scan(
+		&location.ID, &location.Name, &location.Address, &location.TimeZone, &location.Active,
+		&location.Latitude, &location.Longitude, &location.CreatedAt, &location.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+type LocationRepository struct {
+	DB      DBTX
+	Dialect Dialect
+}
+
+func NewLocationRepository(db DBTX) LocationRepoItf {
+	return &LocationRepository{DB: db, Dialect: DialectMySQL}
+}
+
+func NewSQLiteLocationRepository(db DBTX) LocationRepoItf {
+	return &LocationRepository{DB: db, Dialect: DialectSQLite}
+}
+
+func (r *LocationRepository) CreateLocation(location *model.Location) (int64, error) {
+	query := `
+        INSERT INTO location (name, address, time_zone, active, latitude, longitude, created_at, updated_at)
+        VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+    `
+	result, err := r.DB.Exec(query, location.Name, location.Address, location.TimeZone, location.Active,
+		location.Latitude, location.Longitude)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+func (r *LocationRepository) GetLocationByID(id int64) (*model.Location, error) {
+	query := `SELECT ` + locationColumns + ` FROM location WHERE id = ?`
+	return scanLocation(r.DB.QueryRow(query, id))
+}
+
+func (r *LocationRepository) GetLocationByIDForUpdate(id int64) (*model.Location, error) {
+	query := `SELECT ` + locationColumns + ` FROM location WHERE id = ? ` + r.Dialect.ForUpdate()
+	return scanLocation(r.DB.QueryRow(query, id))
+}
+
+// GetLocationByName finds a location by name, ignoring case
+func (r *LocationRepository) GetLocationByName(name string) (*model.Location, error) {
+	query := `SELECT ` + locationColumns + ` FROM location WHERE LOWER(name) = LOWER(?) ORDER BY id LIMIT 1`
+	return scanLocation(r.DB.QueryRow(query, name))
+}
+
+func (r *LocationRepository) ListLocations(active *bool) ([]*model.Location, error) {
+	query := `SELECT ` + locationColumns + ` FROM location WHERE 1=1`
+	args := []interface{}{}
+	if active != nil {
+		query += " AND active = ?"
+		args = append(args, *active)
+	}
+	query += " ORDER BY name"
+
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := make([]*model.Location, 0)
+	for rows.Next() {
+		location, err := scanLocation(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, location)
+	}
+	return list, nil
+}
+
+func (r *LocationRepository) UpdateLocation(location *model.Location) error {
+	query := `
+        UPDATE location SET name = ?, address = ?, time_zone = ?, active = ?, latitude = ?, longitude = ?,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE id = ?
+    `
+	_, err := r.DB.Exec(query, location.Name, location.Address, location.TimeZone, location.Active,
+		location.Latitude, location.Longitude, location.ID)
+	return err
+}
+
+func (r *LocationRepository) DeleteLocation(id int64) error {
+	_, err := r.DB.Exec(`DELETE FROM location WHERE id = ?`, id)
+	return err
+}
+
+// IsLocationInUse reports whether any shift or shift template refers to the location
+func (r *LocationRepository) IsLocationInUse(id int64) (bool, error) {
+	query := `
+        SELECT EXISTS (SELECT 1 FROM shift WHERE location_id = ?)
+            OR EXISTS (SELECT 1 FROM shift_template WHERE location_id = ?)
+    `
+	var inUse bool
+	if err := r.DB.QueryRow(query, id, id).Scan(&inUse); err != nil {
+		return false, err
+	}
+	return inUse, nil
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"dailyworkerroster/clock"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// dateColumn scans a DATE column into its "YYYY-MM-DD" form. With
+// parseTime=true (MySQL) or a DATE declared type (SQLite) the drivers hand
+// back a time.Time, which would otherwise be stringified as RFC 3339.
+type dateColumn string
+
+func (d *dateColumn) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case time.Time:
+		*d = dateColumn(v.Format(dateLayout))
+	case []byte:
+		*d = dateColumn(truncateDate(string(v)))
+	case string:
+		*d = dateColumn(truncateDate(v))
+	case nil:
+		*d = ""
+	default:
+		return fmt.Errorf("unsupported date value %T", src)
+	}
+	return nil
+}
+
+// nullDateColumn is the nullable variant of dateColumn
+type nullDateColumn sql.NullString
+
+func (d *nullDateColumn) Scan(src interface{}) error {
+	if src == nil {
+		*d = nullDateColumn{}
+		return nil
+	}
+	var date dateColumn
+	if err := date.Scan(src); err != nil {
+		return err
+	}
+	*d = nullDateColumn{String: string(date), Valid: true}
+	return nil
+}
+
+func truncateDate(v string) string {
+	if len(v) > len(dateLayout) {
+		return v[:len(dateLayout)]
+	}
+	return v
+}
+
+const dateTimeLayout = "2006-01-02 15:04:05"
+
+// utcColumn scans a DATETIME column holding a UTC time. NULL scans to the
+// zero time.
+type utcColumn time.Time
+
+func (u *utcColumn) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case time.Time:
+		*u = utcColumn(time.Date(v.Year(), v.Month(), v.Day(),
+			v.Hour(), v.Minute(), v.Second(), v.Nanosecond(), time.UTC))
+	case []byte:
+		return u.parse(string(v))
+	case string:
+		return u.parse(v)
+	case nil:
+		*u = utcColumn{}
+	default:
+		return fmt.Errorf("unsupported datetime value %T", src)
+	}
+	return nil
+}
+
+func (u *utcColumn) parse(v string) error {
+	if len(v) > len(dateTimeLayout) {
+		v = v[:len(dateTimeLayout)]
+	}
+	value, err := time.Parse(dateTimeLayout, v)
+	if err != nil {
+		return err
+	}
+	*u = utcColumn(value)
+	return nil
+}
+
+// utcDateTime formats t for a DATETIME column holding UTC times. The value
+// is passed as text so both drivers store it the same way.
+func utcDateTime(t time.Time) string {
+	return t.UTC().Format(dateTimeLayout)
+}
+
+// inZone renders the scanned instants in the named zone, fallback when the
+// name is empty.
+func inZone(zone string, fallback *time.Location, times ...*time.Time) (*time.Location, error) {
+	loc, err := clock.LoadLocation(zone, fallback)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range times {
+		if !t.IsZero() {
+			*t = t.In(loc)
+		}
+	}
+	return loc, nil
+}
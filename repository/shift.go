@@ -4,34 +4,78 @@ import (
 	model "dailyworkerroster/model"
 	"database/sql"
 	"strings"
+	"time"
 )
 
 type ShiftRepoItf interface {
 	CreateShift(shift *model.Shift) (int64, error)
 	GetShiftByID(id int64) (*model.Shift, error)
+	GetShiftByIDForUpdate(id int64) (*model.Shift, error)
 	GetShiftsByIDs(ids []int64) ([]*model.Shift, error)
 	UpdateShiftByID(shift *model.Shift) error
 	DeleteShiftByID(id int64) error
 	GetListShifts(queryParam model.ShiftListQuery) ([]*model.Shift, error)
 }
 
+// shiftColumns reads from "shift" and adds the name and time zone of its location
+var shiftColumns = "id, date, start_time, end_time, start_at, end_at, role_assignment, " + locationRef("shift") +
+	", isAvailable, headcount, template_id, created_at, updated_at"
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanShift reads a shift; defaultZone is the zone of locations without one.
+func scanShift(row rowScanner, defaultZone *time.Location) (*model.Shift, error) {
+	var shift model.Shift
+	var zone sql.NullString
+	err := row.Scan(
+		&shift.ID, (*dateColumn)(&shift.Date), &shift.StartTime, &shift.EndTime,
+		(*utcColumn)(&shift.StartAt), (*utcColumn)(&shift.EndAt),
+		&shift.RoleAssignment, &shift.LocationID, &shift.Location, &zone, &shift.IsAvailable, &shift.Headcount,
+		&shift.TemplateID, &shift.CreatedAt, &shift.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := inZone(zone.String, defaultZone, &shift.StartAt, &shift.EndAt)
+	if err != nil {
+		return nil, err
+	}
+	shift.TimeZone = loc.String()
+	return &shift, nil
+}
+
 type ShiftRepository struct {
-	DB *sql.DB
+	DB      DBTX
+	Dialect Dialect
+	Zone    *time.Location // zone of locations without one
+}
+
+func NewShiftRepository(db DBTX, zone *time.Location) ShiftRepoItf {
+	return &ShiftRepository{
+		DB:      db,
+		Dialect: DialectMySQL,
+		Zone:    zone,
+	}
 }
 
-func NewShiftRepository(db *sql.DB) ShiftRepoItf {
+func NewSQLiteShiftRepository(db DBTX, zone *time.Location) ShiftRepoItf {
 	return &ShiftRepository{
-		DB: db,
+		DB:      db,
+		Dialect: DialectSQLite,
+		Zone:    zone,
 	}
 }
 
 // CreateShift inserts a new shift into the database
 func (r *ShiftRepository) CreateShift(shift *model.Shift) (int64, error) {
 	query := `
-        INSERT INTO shift (date, start_time, end_time, role_assignment, location, isAvailable, created_at, updated_at)
-        VALUES (?, ?, ?, ?, ?, ?, NOW(), NOW())
+        INSERT INTO shift (date, start_time, end_time, start_at, end_at, role_assignment, location_id, isAvailable, headcount, template_id, created_at, updated_at)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
     `
-	result, err := r.DB.Exec(query, shift.Date, shift.StartTime, shift.EndTime, shift.RoleAssignment, shift.Location, shift.IsAvailable)
+	result, err := r.DB.Exec(query, shift.Date, shift.StartTime, shift.EndTime, utcDateTime(shift.StartAt), utcDateTime(shift.EndAt), shift.RoleAssignment, shift.LocationID, shift.IsAvailable, shift.Headcount, shift.TemplateID)
 	if err != nil {
 		return 0, err
 	}
@@ -41,19 +85,20 @@ func (r *ShiftRepository) CreateShift(shift *model.Shift) (int64, error) {
 // GetShiftByID retrieves a shift by its ID
 func (r *ShiftRepository) GetShiftByID(id int64) (*model.Shift, error) {
 	query := `
-        SELECT id, date, start_time, end_time, role_assignment, location, isAvailable, created_at, updated_at
+        SELECT ` + shiftColumns + `
         FROM shift WHERE id = ?
     `
-	var shift model.Shift
-	err := r.DB.QueryRow(query, id).Scan(
-		&shift.ID, &shift.Date, &shift.StartTime, &shift.EndTime,
-		&shift.RoleAssignment, &shift.Location, &shift.IsAvailable,
-		&shift.CreatedAt, &shift.UpdatedAt,
-	)
-	if err != nil {
-		return nil, err
-	}
-	return &shift, nil
+	return scanShift(r.DB.QueryRow(query, id), r.Zone)
+}
+
+// GetShiftByIDForUpdate retrieves a shift by its ID and locks the row until
+// the surrounding transaction ends
+func (r *ShiftRepository) GetShiftByIDForUpdate(id int64) (*model.Shift, error) {
+	query := `
+        SELECT ` + shiftColumns + `
+        FROM shift WHERE id = ?
+    ` + r.Dialect.ForUpdate()
+	return scanShift(r.DB.QueryRow(query, id), r.Zone)
 }
 
 // GetShiftsByIDs retrieves multiple shifts by a list of IDs
@@ -71,7 +116,7 @@ func (r *ShiftRepository) GetShiftsByIDs(ids []int64) ([]*model.Shift, error) {
 	}
 
 	query := `
-        SELECT id, date, start_time, end_time, role_assignment, location, isAvailable, created_at, updated_at
+        SELECT ` + shiftColumns + `
         FROM shift
         WHERE id IN (` + strings.Join(placeholders, ",") + `)
     `
@@ -84,16 +129,11 @@ func (r *ShiftRepository) GetShiftsByIDs(ids []int64) ([]*model.Shift, error) {
 
 	var shifts []*model.Shift
 	for rows.Next() {
-		var shift model.Shift
-		err := rows.Scan(
-			&shift.ID, &shift.Date, &shift.StartTime, &shift.EndTime,
-			&shift.RoleAssignment, &shift.Location, &shift.IsAvailable,
-			&shift.CreatedAt, &shift.UpdatedAt,
-		)
+		shift, err := scanShift(rows, r.Zone)
 		if err != nil {
 			return nil, err
 		}
-		shifts = append(shifts, &shift)
+		shifts = append(shifts, shift)
 	}
 	return shifts, nil
 }
@@ -101,10 +141,10 @@ func (r *ShiftRepository) GetShiftsByIDs(ids []int64) ([]*model.Shift, error) {
 // UpdateShift updates an existing shift
 func (r *ShiftRepository) UpdateShiftByID(shift *model.Shift) error {
 	query := `
-        UPDATE shift SET date=?, start_time=?, end_time=?, role_assignment=?, location=?, isAvailable=?, updated_at=NOW()
+        UPDATE shift SET date=?, start_time=?, end_time=?, start_at=?, end_at=?, role_assignment=?, location_id=?, isAvailable=?, headcount=?, updated_at=CURRENT_TIMESTAMP
         WHERE id=?
     `
-	_, err := r.DB.Exec(query, shift.Date, shift.StartTime, shift.EndTime, shift.RoleAssignment, shift.Location, shift.IsAvailable, shift.ID)
+	_, err := r.DB.Exec(query, shift.Date, shift.StartTime, shift.EndTime, utcDateTime(shift.StartAt), utcDateTime(shift.EndAt), shift.RoleAssignment, shift.LocationID, shift.IsAvailable, shift.Headcount, shift.ID)
 	return err
 }
 
@@ -119,7 +159,7 @@ func (r *ShiftRepository) GetListShifts(
 	queryParam model.ShiftListQuery,
 ) ([]*model.Shift, error) {
 	query := `
-        SELECT id, date, start_time, end_time, role_assignment, location, is_available, created_at, updated_at
+        SELECT ` + shiftColumns + `
         FROM shift
         WHERE 1=1
     `
@@ -129,21 +169,36 @@ func (r *ShiftRepository) GetListShifts(
 		query += " AND role_assignment = ?"
 		args = append(args, queryParam.RoleAssignment)
 	}
-	if queryParam.Location != "" {
-		query += " AND location = ?"
-		args = append(args, queryParam.Location)
+	if queryParam.LocationID != 0 {
+		query += " AND location_id = ?"
+		args = append(args, queryParam.LocationID)
 	}
 	if queryParam.IsAvailable != nil {
-		query += " AND is_available = ?"
+		query += " AND isAvailable = ?"
 		args = append(args, *queryParam.IsAvailable)
 	}
 	if queryParam.Date != "" {
 		query += " AND date = ?"
 		args = append(args, queryParam.Date)
 	}
+	if queryParam.DateFrom != "" {
+		query += " AND date >= ?"
+		args = append(args, queryParam.DateFrom)
+	}
+	if queryParam.DateTo != "" {
+		query += " AND date <= ?"
+		args = append(args, queryParam.DateTo)
+	}
+	if queryParam.TemplateID != nil {
+		query += " AND template_id = ?"
+		args = append(args, *queryParam.TemplateID)
+	}
 
-	query += " ORDER BY date, start_time LIMIT ? OFFSET ?"
-	args = append(args, queryParam.Limit, queryParam.Offset)
+	query += " ORDER BY start_at"
+	if queryParam.Limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, queryParam.Limit, queryParam.Offset)
+	}
 
 	rows, err := r.DB.Query(query, args...)
 	if err != nil {
@@ -153,16 +208,11 @@ func (r *ShiftRepository) GetListShifts(
 
 	var shifts []*model.Shift
 	for rows.Next() {
-		var shift model.Shift
-		err := rows.Scan(
-			&shift.ID, &shift.Date, &shift.StartTime, &shift.EndTime,
-			&shift.RoleAssignment, &shift.Location, &shift.IsAvailable,
-			&shift.CreatedAt, &shift.UpdatedAt,
-		)
+		shift, err := scanShift(rows, r.Zone)
 		if err != nil {
 			return nil, err
 		}
-		shifts = append(shifts, &shift)
+		shifts = append(shifts, shift)
 	}
 	return shifts, nil
 }
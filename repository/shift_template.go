@@ -0,0 +1,125 @@
+package repository
+
+import (
+	model "dailyworkerroster/model"
+	"database/sql"
+	"strings"
+)
+
+type ShiftTemplateRepoItf interface {
+	CreateShiftTemplate(tpl *model.ShiftTemplate) (int64, error)
+	GetShiftTemplateByID(id int64) (*model.ShiftTemplate, error)
+	GetShiftTemplateByIDForUpdate(id int64) (*model.ShiftTemplate, error)
+	ListShiftTemplates() ([]*model.ShiftTemplate, error)
+	UpdateShiftTemplate(tpl *model.ShiftTemplate) error
+}
+
+type ShiftTemplateRepository struct {
+	DB      DBTX
+	Dialect Dialect
+}
+
+func NewShiftTemplateRepository(db DBTX) ShiftTemplateRepoItf {
+	return &ShiftTemplateRepository{DB: db, Dialect: DialectMySQL}
+}
+
+func NewSQLiteShiftTemplateRepository(db DBTX) ShiftTemplateRepoItf {
+	return &ShiftTemplateRepository{DB: db, Dialect: DialectSQLite}
+}
+
+const shiftTemplateColumns = `id, name, start_time, end_time, role_assignment, COALESCE(location_id, 0),
+        COALESCE((SELECT l.name FROM location l WHERE l.id = shift_template.location_id), ''), headcount, weekdays, start_date, end_date, excluded_dates, is_active, created_at, updated_at`
+
+// Weekdays and excluded dates are stored as comma separated lists
+func scanShiftTemplate(row rowScanner) (*model.ShiftTemplate, error) {
+	var tpl model.ShiftTemplate
+	var weekdays string
+	var endDate sql.NullString
+	var excludedDates sql.NullString
+	err := row.Scan(
+		&tpl.ID, &tpl.Name, &tpl.StartTime, &tpl.EndTime, &tpl.RoleAssignment, &tpl.LocationID, &tpl.Location, &tpl.Headcount,
+		&weekdays, (*dateColumn)(&tpl.StartDate), (*nullDateColumn)(&endDate), &excludedDates, &tpl.IsActive,
+		&tpl.CreatedAt, &tpl.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if endDate.Valid {
+		tpl.EndDate = &endDate.String
+	}
+	tpl.Weekdays = splitList(weekdays)
+	tpl.ExcludedDates = splitList(excludedDates.String)
+	return &tpl, nil
+}
+
+func splitList(value string) []string {
+	list := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func (r *ShiftTemplateRepository) CreateShiftTemplate(tpl *model.ShiftTemplate) (int64, error) {
+	query := `
+        INSERT INTO shift_template (name, start_time, end_time, role_assignment, location_id, headcount,
+            weekdays, start_date, end_date, excluded_dates, is_active, created_at, updated_at)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+    `
+	result, err := r.DB.Exec(query,
+		tpl.Name, tpl.StartTime, tpl.EndTime, tpl.RoleAssignment, tpl.LocationID, tpl.Headcount,
+		strings.Join(tpl.Weekdays, ","), tpl.StartDate, tpl.EndDate, strings.Join(tpl.ExcludedDates, ","), tpl.IsActive,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+func (r *ShiftTemplateRepository) GetShiftTemplateByID(id int64) (*model.ShiftTemplate, error) {
+	query := `SELECT ` + shiftTemplateColumns + ` FROM shift_template WHERE id = ?`
+	return scanShiftTemplate(r.DB.QueryRow(query, id))
+}
+
+// GetShiftTemplateByIDForUpdate locks the template so that only one
+// generation run per template happens at a time
+func (r *ShiftTemplateRepository) GetShiftTemplateByIDForUpdate(id int64) (*model.ShiftTemplate, error) {
+	query := `SELECT ` + shiftTemplateColumns + ` FROM shift_template WHERE id = ? ` + r.Dialect.ForUpdate()
+	return scanShiftTemplate(r.DB.QueryRow(query, id))
+}
+
+func (r *ShiftTemplateRepository) ListShiftTemplates() ([]*model.ShiftTemplate, error) {
+	query := `SELECT ` + shiftTemplateColumns + ` FROM shift_template ORDER BY id`
+	rows, err := r.DB.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*model.ShiftTemplate
+	for rows.Next() {
+		tpl, err := scanShiftTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, tpl)
+	}
+	return list, nil
+}
+
+func (r *ShiftTemplateRepository) UpdateShiftTemplate(tpl *model.ShiftTemplate) error {
+	query := `
+        UPDATE shift_template
+        SET name = ?, start_time = ?, end_time = ?, role_assignment = ?, location_id = ?, headcount = ?,
+            weekdays = ?, start_date = ?, end_date = ?, excluded_dates = ?, is_active = ?, updated_at = CURRENT_TIMESTAMP
+        WHERE id = ?
+    `
+	_, err := r.DB.Exec(query,
+		tpl.Name, tpl.StartTime, tpl.EndTime, tpl.RoleAssignment, tpl.LocationID, tpl.Headcount,
+		strings.Join(tpl.Weekdays, ","), tpl.StartDate, tpl.EndDate, strings.Join(tpl.ExcludedDates, ","), tpl.IsActive,
+		tpl.ID,
+	)
+	return err
+}
@@ -0,0 +1,176 @@
+package repository_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"dailyworkerroster/model"
+	"dailyworkerroster/repository"
+)
+
+// amsterdamShift is a morning shift at a location in Europe/Amsterdam, so
+// its wall clock date and times differ from the stored UTC instants.
+func amsterdamShift(locationID int64, date string, startHour int) *model.Shift {
+	loc, _ := time.LoadLocation("Europe/Amsterdam")
+	day, _ := time.ParseInLocation("2006-01-02", date, loc)
+	start := day.Add(time.Duration(startHour) * time.Hour)
+	return &model.Shift{
+		Date:           date,
+		StartTime:      start.Format("15:04:05"),
+		EndTime:        start.Add(4 * time.Hour).Format("15:04:05"),
+		StartAt:        start,
+		EndAt:          start.Add(4 * time.Hour),
+		RoleAssignment: "CLEANER",
+		LocationID:     locationID,
+		IsAvailable:    true,
+		Headcount:      2,
+	}
+}
+
+func TestShiftRepoCreateAndGet(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos *repository.Repositories) {
+		locationID := createTestLocation(t, repos, "Store", "Europe/Amsterdam")
+		want := amsterdamShift(locationID, "2026-03-29", 1)
+		id := createTestShift(t, repos, want)
+
+		got, err := repos.Shift.GetShiftByID(id)
+		if err != nil {
+			t.Fatalf("GetShiftByID: %v", err)
+		}
+		if got.Date != want.Date || got.StartTime != want.StartTime || got.EndTime != want.EndTime {
+			t.Errorf("wall clock = %s %s-%s, want %s %s-%s",
+				got.Date, got.StartTime, got.EndTime, want.Date, want.StartTime, want.EndTime)
+		}
+		if !got.StartAt.Equal(want.StartAt) || !got.EndAt.Equal(want.EndAt) {
+			t.Errorf("instants = %v-%v, want %v-%v", got.StartAt, got.EndAt, want.StartAt, want.EndAt)
+		}
+		if got.TimeZone != "Europe/Amsterdam" || got.StartAt.Location().String() != "Europe/Amsterdam" {
+			t.Errorf("zone = %q, start in %v, want Europe/Amsterdam", got.TimeZone, got.StartAt.Location())
+		}
+		if got.Location != "Store" || got.LocationID != locationID {
+			t.Errorf("location = %d %q, want %d Store", got.LocationID, got.Location, locationID)
+		}
+		if got.RoleAssignment != "CLEANER" || !got.IsAvailable || got.Headcount != 2 || got.TemplateID != nil {
+			t.Errorf("got %+v", got)
+		}
+
+		locked, err := repos.Shift.GetShiftByIDForUpdate(id)
+		if err != nil || locked.ID != id {
+			t.Errorf("GetShiftByIDForUpdate = %v, %v", locked, err)
+		}
+
+		if _, err := repos.Shift.GetShiftByID(id + 100); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("GetShiftByID of a missing shift: err = %v, want sql.ErrNoRows", err)
+		}
+	})
+}
+
+func TestShiftRepoUpdateAndDelete(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos *repository.Repositories) {
+		locationID := createTestLocation(t, repos, "Store", "Europe/Amsterdam")
+		otherID := createTestLocation(t, repos, "Depot", "")
+		shift := amsterdamShift(locationID, "2026-05-04", 9)
+		id := createTestShift(t, repos, shift)
+
+		moved := amsterdamShift(otherID, "2026-05-05", 13)
+		moved.ID = id
+		moved.RoleAssignment = "CASHIER"
+		moved.IsAvailable = false
+		moved.Headcount = 3
+		if err := repos.Shift.UpdateShiftByID(moved); err != nil {
+			t.Fatalf("UpdateShiftByID: %v", err)
+		}
+
+		got, err := repos.Shift.GetShiftByID(id)
+		if err != nil {
+			t.Fatalf("GetShiftByID: %v", err)
+		}
+		if got.Date != "2026-05-05" || !got.StartAt.Equal(moved.StartAt) || got.LocationID != otherID ||
+			got.RoleAssignment != "CASHIER" || got.IsAvailable || got.Headcount != 3 {
+			t.Errorf("after update got %+v", got)
+		}
+		if got.TimeZone != defaultZone.String() || got.StartAt.Location() != defaultZone {
+			t.Errorf("at a location without a zone: zone = %q, start in %v, want %v", got.TimeZone, got.StartAt.Location(), defaultZone)
+		}
+
+		if err := repos.Shift.DeleteShiftByID(id); err != nil {
+			t.Fatalf("DeleteShiftByID: %v", err)
+		}
+		if _, err := repos.Shift.GetShiftByID(id); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("GetShiftByID after delete: err = %v, want sql.ErrNoRows", err)
+		}
+	})
+}
+
+func TestShiftRepoList(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos *repository.Repositories) {
+		store := createTestLocation(t, repos, "Store", "Europe/Amsterdam")
+		depot := createTestLocation(t, repos, "Depot", "Europe/Amsterdam")
+
+		late := createTestShift(t, repos, amsterdamShift(store, "2026-06-02", 14))
+		early := createTestShift(t, repos, amsterdamShift(store, "2026-06-02", 6))
+		closed := amsterdamShift(depot, "2026-06-03", 9)
+		closed.IsAvailable = false
+		closedID := createTestShift(t, repos, closed)
+		cashier := amsterdamShift(depot, "2026-06-05", 9)
+		cashier.RoleAssignment = "CASHIER"
+		cashierID := createTestShift(t, repos, cashier)
+
+		open := true
+		tests := []struct {
+			name  string
+			query model.ShiftListQuery
+			want  []int64
+		}{
+			{"all by start", model.ShiftListQuery{}, []int64{early, late, closedID, cashierID}},
+			{"location", model.ShiftListQuery{LocationID: depot}, []int64{closedID, cashierID}},
+			{"role", model.ShiftListQuery{RoleAssignment: "CASHIER"}, []int64{cashierID}},
+			{"available", model.ShiftListQuery{IsAvailable: &open}, []int64{early, late, cashierID}},
+			{"date", model.ShiftListQuery{Date: "2026-06-02"}, []int64{early, late}},
+			{"date range", model.ShiftListQuery{DateFrom: "2026-06-03", DateTo: "2026-06-04"}, []int64{closedID}},
+			{"page", model.ShiftListQuery{Limit: 2, Offset: 1}, []int64{late, closedID}},
+		}
+		for _, tt := range tests {
+			shifts, err := repos.Shift.GetListShifts(tt.query)
+			if err != nil {
+				t.Fatalf("%s: GetListShifts: %v", tt.name, err)
+			}
+			if got := shiftIDs(shifts); !equalIDs(got, tt.want) {
+				t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+			}
+		}
+
+		shifts, err := repos.Shift.GetShiftsByIDs([]int64{cashierID, early, 999})
+		if err != nil {
+			t.Fatalf("GetShiftsByIDs: %v", err)
+		}
+		if len(shifts) != 2 {
+			t.Errorf("GetShiftsByIDs returned %v, want shifts %d and %d", shiftIDs(shifts), cashierID, early)
+		}
+		if shifts, err := repos.Shift.GetShiftsByIDs(nil); err != nil || len(shifts) != 0 {
+			t.Errorf("GetShiftsByIDs(nil) = %v, %v, want none", shifts, err)
+		}
+	})
+}
+
+func shiftIDs(shifts []*model.Shift) []int64 {
+	ids := make([]int64, len(shifts))
+	for i, shift := range shifts {
+		ids[i] = shift.ID
+	}
+	return ids
+}
+
+func equalIDs(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	model "dailyworkerroster/model"
+)
+
+type ShiftTransferRepoItf interface {
+	CreateShiftTransfer(transfer *model.ShiftTransfer) (int64, error)
+	GetShiftTransferByID(id int64) (*model.ShiftTransferDetail, error)
+	GetShiftTransferByIDForUpdate(id int64) (*model.ShiftTransferDetail, error)
+	ListShiftTransfers(queryParam model.ShiftTransferQuery) ([]model.ShiftTransferDetail, error)
+	ListActiveTransfersByWorkerShift(workerShiftID int64) ([]model.ShiftTransferDetail, error)
+	UpdateShiftTransfer(transfer *model.ShiftTransfer) error
+}
+
+type ShiftTransferRepository struct {
+	DB      DBTX
+	Dialect Dialect
+}
+
+func NewShiftTransferRepository(db DBTX) ShiftTransferRepoItf {
+	return &ShiftTransferRepository{DB: db, Dialect: DialectMySQL}
+}
+
+func NewSQLiteShiftTransferRepository(db DBTX) ShiftTransferRepoItf {
+	return &ShiftTransferRepository{DB: db, Dialect: DialectSQLite}
+}
+
+const shiftTransferDetailQuery = `
+        SELECT t.id, t.worker_shift_id, t.from_user_id, t.to_user_id, t.taker_id, t.status, t.decided_by,
+               t.created_at, t.updated_at,
+               s.id, s.date, s.start_time, s.end_time, s.role_assignment, COALESCE(s.location_id, 0),
+               COALESCE((SELECT l.name FROM location l WHERE l.id = s.location_id), '')
+        FROM shift_transfer t
+        JOIN worker_shift ws ON t.worker_shift_id = ws.id
+        JOIN shift s ON ws.shift_id = s.id
+    `
+
+func scanShiftTransferDetail(row rowScanner) (*model.ShiftTransferDetail, error) {
+	var t model.ShiftTransferDetail
+	err := row.Scan(
+		&t.ID, &t.WorkerShiftID, &t.FromUserID, &t.ToUserID, &t.TakerID, &t.Status, &t.DecidedBy,
+		&t.CreatedAt, &t.UpdatedAt,
+		&t.ShiftID, (*dateColumn)(&t.Date), &t.StartTime, &t.EndTime, &t.RoleAssignment, &t.LocationID, &t.Location,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *ShiftTransferRepository) CreateShiftTransfer(transfer *model.ShiftTransfer) (int64, error) {
+	query := `
+        INSERT INTO shift_transfer (worker_shift_id, from_user_id, to_user_id, status, created_at, updated_at)
+        VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+    `
+	result, err := r.DB.Exec(query, transfer.WorkerShiftID, transfer.FromUserID, transfer.ToUserID, transfer.Status)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+func (r *ShiftTransferRepository) GetShiftTransferByID(id int64) (*model.ShiftTransferDetail, error) {
+	return scanShiftTransferDetail(r.DB.QueryRow(shiftTransferDetailQuery+" WHERE t.id = ?", id))
+}
+
+func (r *ShiftTransferRepository) GetShiftTransferByIDForUpdate(id int64) (*model.ShiftTransferDetail, error) {
+	query := shiftTransferDetailQuery + " WHERE t.id = ? " + r.Dialect.ForUpdate()
+	return scanShiftTransferDetail(r.DB.QueryRow(query, id))
+}
+
+func (r *ShiftTransferRepository) ListShiftTransfers(queryParam model.ShiftTransferQuery) ([]model.ShiftTransferDetail, error) {
+	query := shiftTransferDetailQuery + " WHERE 1=1"
+	args := []interface{}{}
+
+	if queryParam.Status != nil {
+		query += " AND t.status = ?"
+		args = append(args, *queryParam.Status)
+	}
+	if queryParam.FromUserID != nil {
+		query += " AND t.from_user_id = ?"
+		args = append(args, *queryParam.FromUserID)
+	}
+	if queryParam.OpenFor != nil {
+		query += " AND t.from_user_id <> ? AND (t.to_user_id IS NULL OR t.to_user_id = ?)"
+		args = append(args, *queryParam.OpenFor, *queryParam.OpenFor)
+	}
+	query += " ORDER BY s.date, s.start_time, t.id"
+
+	return r.listShiftTransfers(query, args...)
+}
+
+// ListActiveTransfersByWorkerShift returns the OFFERED and ACCEPTED transfers of a worker shift
+func (r *ShiftTransferRepository) ListActiveTransfersByWorkerShift(workerShiftID int64) ([]model.ShiftTransferDetail, error) {
+	query := shiftTransferDetailQuery + " WHERE t.worker_shift_id = ? AND t.status IN (?, ?)"
+	return r.listShiftTransfers(query, workerShiftID, model.TRANSFER_OFFERED, model.TRANSFER_ACCEPTED)
+}
+
+func (r *ShiftTransferRepository) listShiftTransfers(query string, args ...interface{}) ([]model.ShiftTransferDetail, error) {
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []model.ShiftTransferDetail
+	for rows.Next() {
+		t, err := scanShiftTransferDetail(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *t)
+	}
+	return list, nil
+}
+
+func (r *ShiftTransferRepository) UpdateShiftTransfer(transfer *model.ShiftTransfer) error {
+	query := `
+        UPDATE shift_transfer
+        SET status = ?, taker_id = ?, decided_by = ?, updated_at = CURRENT_TIMESTAMP
+        WHERE id = ?
+    `
+	_, err := r.DB.Exec(query, transfer.Status, transfer.TakerID, transfer.DecidedBy, transfer.ID)
+	return err
+}
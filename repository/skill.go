@@ -0,0 +1,121 @@
+package repository
+
+import (
+	model "dailyworkerroster/model"
+)
+
+type SkillRepoItf interface {
+	CreateSkill(skill *model.Skill) (int64, error)
+	GetSkillByID(id int64) (*model.Skill, error)
+	GetSkillByIDForUpdate(id int64) (*model.Skill, error)
+	GetSkillByCode(code string) (*model.Skill, error)
+	ListSkills(active *bool) ([]*model.Skill, error)
+	UpdateSkill(skill *model.Skill) error
+	DeleteSkill(id int64) error
+	IsSkillInUse(code string) (bool, error)
+}
+
+const skillColumns = "id, code, name, description, active, created_at, updated_at"
+
+func scanSkill(row rowScanner) (*model.Skill, error) {
+	var skill model.Skill
+	err := row.Scan(&skill.ID, &skill.Code, &skill.Name, &skill.Description, &skill.Active, &skill.CreatedAt, &skill.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &skill, nil
+}
+
+type SkillRepository struct {
+	DB      DBTX
+	Dialect Dialect
+}
+
+func NewSkillRepository(db DBTX) SkillRepoItf {
+	return &SkillRepository{DB: db, Dialect: DialectMySQL}
+}
+
+func NewSQLiteSkillRepository(db DBTX) SkillRepoItf {
+	return &SkillRepository{DB: db, Dialect: DialectSQLite}
+}
+
+func (r *SkillRepository) CreateSkill(skill *model.Skill) (int64, error) {
+	query := `
+        INSERT INTO skill (code, name, description, active, created_at, updated_at)
+        VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+    `
+	result, err := r.DB.Exec(query, skill.Code, skill.Name, skill.Description, skill.Active)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+func (r *SkillRepository) GetSkillByID(id int64) (*model.Skill, error) {
+	query := `SELECT ` + skillColumns + ` FROM skill WHERE id = ?`
+	return scanSkill(r.DB.QueryRow(query, id))
+}
+
+func (r *SkillRepository) GetSkillByIDForUpdate(id int64) (*model.Skill, error) {
+	query := `SELECT ` + skillColumns + ` FROM skill WHERE id = ? ` + r.Dialect.ForUpdate()
+	return scanSkill(r.DB.QueryRow(query, id))
+}
+
+func (r *SkillRepository) GetSkillByCode(code string) (*model.Skill, error) {
+	query := `SELECT ` + skillColumns + ` FROM skill WHERE code = ?`
+	return scanSkill(r.DB.QueryRow(query, code))
+}
+
+func (r *SkillRepository) ListSkills(active *bool) ([]*model.Skill, error) {
+	query := `SELECT ` + skillColumns + ` FROM skill WHERE 1=1`
+	args := []interface{}{}
+	if active != nil {
+		query += " AND active = ?"
+		args = append(args, *active)
+	}
+	query += " ORDER BY code"
+
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := make([]*model.Skill, 0)
+	for rows.Next() {
+		skill, err := scanSkill(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, skill)
+	}
+	return list, nil
+}
+
+// UpdateSkill saves everything but the code, which shifts refer to
+func (r *SkillRepository) UpdateSkill(skill *model.Skill) error {
+	query := `
+        UPDATE skill SET name = ?, description = ?, active = ?, updated_at = CURRENT_TIMESTAMP
+        WHERE id = ?
+    `
+	_, err := r.DB.Exec(query, skill.Name, skill.Description, skill.Active, skill.ID)
+	return err
+}
+
+func (r *SkillRepository) DeleteSkill(id int64) error {
+	_, err := r.DB.Exec(`DELETE FROM skill WHERE id = ?`, id)
+	return err
+}
+
+// IsSkillInUse reports whether any shift or shift template requires the skill
+func (r *SkillRepository) IsSkillInUse(code string) (bool, error) {
+	query := `
+        SELECT EXISTS (SELECT 1 FROM shift WHERE role_assignment = ?)
+            OR EXISTS (SELECT 1 FROM shift_template WHERE role_assignment = ?)
+    `
+	var inUse bool
+	if err := r.DB.QueryRow(query, code, code).Scan(&inUse); err != nil {
+		return false, err
+	}
+	return inUse, nil
+}
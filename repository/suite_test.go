@@ -0,0 +1,95 @@
+package repository_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"dailyworkerroster/config"
+	"dailyworkerroster/migration"
+	"dailyworkerroster/model"
+	"dailyworkerroster/repository"
+)
+
+// mysqlDSNEnv names a MySQL database the suite may migrate up and back down,
+// e.g. "root:password@tcp(127.0.0.1:3306)/roster_test?parseTime=true". It
+// must start out empty; without it only SQLite is tested.
+const mysqlDSNEnv = "TEST_MYSQL_DSN"
+
+// defaultZone is the zone of test locations without one. It is neither UTC
+// nor the zone of the machine, so falling back to either shows.
+var defaultZone, _ = time.LoadLocation("America/New_York")
+
+// forEachBackend runs test once per backend against a freshly migrated
+// database.
+func forEachBackend(t *testing.T, test func(t *testing.T, repos *repository.Repositories)) {
+	t.Run("sqlite", func(t *testing.T) {
+		test(t, openTestDB(t, "sqlite://:memory:"))
+	})
+	t.Run("mysql", func(t *testing.T) {
+		dsn := os.Getenv(mysqlDSNEnv)
+		if dsn == "" {
+			t.Skipf("%s is not set", mysqlDSNEnv)
+		}
+		test(t, openTestDB(t, dsn))
+	})
+}
+
+func openTestDB(t *testing.T, dsn string) *repository.Repositories {
+	t.Helper()
+
+	db, dialect, err := repository.Open(config.DatabaseConfig{DSN: dsn, MaxOpenConns: 4, MaxIdleConns: 4})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	migrator, err := migration.NewMigrator(db, dialect, time.UTC)
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	ctx := context.Background()
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	t.Cleanup(func() {
+		// Leave a shared MySQL database empty for the next test
+		if _, err := migrator.Down(ctx, len(migrator.Migrations)); err != nil {
+			t.Errorf("migrate down: %v", err)
+		}
+		db.Close()
+	})
+	return repository.NewRepositories(db, dialect, defaultZone)
+}
+
+func createTestUser(t *testing.T, repos *repository.Repositories, name, role string) int64 {
+	t.Helper()
+
+	id, err := repos.User.SignUp(&model.User{
+		Name: name, Username: name, Email: name + "@example.com", Password: "hash", Role: role,
+	})
+	if err != nil {
+		t.Fatalf("SignUp %s: %v", name, err)
+	}
+	return id
+}
+
+func createTestLocation(t *testing.T, repos *repository.Repositories, name, zone string) int64 {
+	t.Helper()
+
+	id, err := repos.Location.CreateLocation(&model.Location{Name: name, TimeZone: zone, Active: true})
+	if err != nil {
+		t.Fatalf("CreateLocation %s: %v", name, err)
+	}
+	return id
+}
+
+func createTestShift(t *testing.T, repos *repository.Repositories, shift *model.Shift) int64 {
+	t.Helper()
+
+	id, err := repos.Shift.CreateShift(shift)
+	if err != nil {
+		t.Fatalf("CreateShift: %v", err)
+	}
+	shift.ID = id
+	return id
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	model "dailyworkerroster/model"
+)
+
+type TimeEntryRepoItf interface {
+	CreateTimeEntry(entry *model.TimeEntry) (int64, error)
+	GetTimeEntryByWorkerShiftID(workerShiftID int64) (*model.TimeEntry, error)
+	GetTimeEntryByWorkerShiftIDForUpdate(workerShiftID int64) (*model.TimeEntry, error)
+	ListTimeEntriesByWorkerShiftIDs(workerShiftIDs []int64) ([]*model.TimeEntry, error)
+	UpdateTimeEntry(entry *model.TimeEntry) error
+	CreateTimeEntryBreak(b *model.TimeEntryBreak) (int64, error)
+	EndTimeEntryBreak(id int64, endAt time.Time) error
+	DeleteTimeEntryBreaks(timeEntryID int64) error
+}
+
+const timeEntryColumns = `id, worker_shift_id, clock_in_at, clock_out_at, auto_clock_out,
+        corrected_by, correction_reason, corrected_at, created_at, updated_at`
+
+func scanTimeEntry(row rowScanner) (*model.TimeEntry, error) {
+	var entry model.TimeEntry
+	err := row.Scan(
+		&entry.ID, &entry.WorkerShiftID, (*utcColumn)(&entry.ClockInAt), &entry.ClockOutAt, &entry.AutoClockOut,
+		&entry.CorrectedBy, &entry.CorrectionReason, &entry.CorrectedAt, &entry.CreatedAt, &entry.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	entry.Breaks = make([]*model.TimeEntryBreak, 0)
+	return &entry, nil
+}
+
+type TimeEntryRepository struct {
+	DB      DBTX
+	Dialect Dialect
+}
+
+func NewTimeEntryRepository(db DBTX) TimeEntryRepoItf {
+	return &TimeEntryRepository{DB: db, Dialect: DialectMySQL}
+}
+
+func NewSQLiteTimeEntryRepository(db DBTX) TimeEntryRepoItf {
+	return &TimeEntryRepository{DB: db, Dialect: DialectSQLite}
+}
+
+func (r *TimeEntryRepository) CreateTimeEntry(entry *model.TimeEntry) (int64, error) {
+	query := `
+        INSERT INTO time_entry (worker_shift_id, clock_in_at, clock_out_at, auto_clock_out,
+            corrected_by, correction_reason, corrected_at, created_at, updated_at)
+        VALUES (?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+    `
+	result, err := r.DB.Exec(query, entry.WorkerShiftID, entry.ClockInAt.UTC(), utcOrNil(entry.ClockOutAt),
+		entry.AutoClockOut, entry.CorrectedBy, entry.CorrectionReason, utcOrNil(entry.CorrectedAt))
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// GetTimeEntryByWorkerShiftID returns the time entry of a worker shift with
+// its breaks.
+func (r *TimeEntryRepository) GetTimeEntryByWorkerShiftID(workerShiftID int64) (*model.TimeEntry, error) {
+	query := `SELECT ` + timeEntryColumns + ` FROM time_entry WHERE worker_shift_id = ?`
+	return r.getTimeEntry(query, workerShiftID)
+}
+
+func (r *TimeEntryRepository) GetTimeEntryByWorkerShiftIDForUpdate(workerShiftID int64) (*model.TimeEntry, error) {
+	query := `SELECT ` + timeEntryColumns + ` FROM time_entry WHERE worker_shift_id = ? ` + r.Dialect.ForUpdate()
+	return r.getTimeEntry(query, workerShiftID)
+}
+
+func (r *TimeEntryRepository) getTimeEntry(query string, workerShiftID int64) (*model.TimeEntry, error) {
+	entry, err := scanTimeEntry(r.DB.QueryRow(query, workerShiftID))
+	if err != nil {
+		return nil, err
+	}
+	if err := r.attachBreaks([]*model.TimeEntry{entry}); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// ListTimeEntriesByWorkerShiftIDs returns the time entries of the given
+// worker shifts with their breaks. Worker shifts without one are left out.
+func (r *TimeEntryRepository) ListTimeEntriesByWorkerShiftIDs(workerShiftIDs []int64) ([]*model.TimeEntry, error) {
+	list := make([]*model.TimeEntry, 0)
+	if len(workerShiftIDs) == 0 {
+		return list, nil
+	}
+
+	placeholders := make([]string, len(workerShiftIDs))
+	args := make([]interface{}, len(workerShiftIDs))
+	for i, id := range workerShiftIDs {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+	query := `SELECT ` + timeEntryColumns + ` FROM time_entry
+        WHERE worker_shift_id IN (` + strings.Join(placeholders, ",") + `)
+        ORDER BY id`
+
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entry, err := scanTimeEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := r.attachBreaks(list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// attachBreaks loads the breaks of the entries, in start order.
+func (r *TimeEntryRepository) attachBreaks(entries []*model.TimeEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	byID := make(map[int64]*model.TimeEntry, len(entries))
+	placeholders := make([]string, 0, len(entries))
+	args := make([]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		byID[entry.ID] = entry
+		placeholders = append(placeholders, "?")
+		args = append(args, entry.ID)
+	}
+	query := `SELECT id, time_entry_id, start_at, end_at FROM time_entry_break
+        WHERE time_entry_id IN (` + strings.Join(placeholders, ",") + `)
+        ORDER BY start_at, id`
+
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var b model.TimeEntryBreak
+		if err := rows.Scan(&b.ID, &b.TimeEntryID, (*utcColumn)(&b.StartAt), &b.EndAt); err != nil {
+			return err
+		}
+		entry := byID[b.TimeEntryID]
+		entry.Breaks = append(entry.Breaks, &b)
+	}
+	return rows.Err()
+}
+
+func (r *TimeEntryRepository) UpdateTimeEntry(entry *model.TimeEntry) error {
+	query := `
+        UPDATE time_entry
+        SET clock_in_at = ?, clock_out_at = ?, auto_clock_out = ?,
+            corrected_by = ?, correction_reason = ?, corrected_at = ?, updated_at = CURRENT_TIMESTAMP
+        WHERE id = ?
+    `
+	_, err := r.DB.Exec(query, entry.ClockInAt.UTC(), utcOrNil(entry.ClockOutAt), entry.AutoClockOut,
+		entry.CorrectedBy, entry.CorrectionReason, utcOrNil(entry.CorrectedAt), entry.ID)
+	return err
+}
+
+func (r *TimeEntryRepository) CreateTimeEntryBreak(b *model.TimeEntryBreak) (int64, error) {
+	query := `INSERT INTO time_entry_break (time_entry_id, start_at, end_at) VALUES (?, ?, ?)`
+	result, err := r.DB.Exec(query, b.TimeEntryID, b.StartAt.UTC(), utcOrNil(b.EndAt))
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+func (r *TimeEntryRepository) EndTimeEntryBreak(id int64, endAt time.Time) error {
+	query := `UPDATE time_entry_break SET end_at = ? WHERE id = ?`
+	_, err := r.DB.Exec(query, endAt.UTC(), id)
+	return err
+}
+
+func (r *TimeEntryRepository) DeleteTimeEntryBreaks(timeEntryID int64) error {
+	query := `DELETE FROM time_entry_break WHERE time_entry_id = ?`
+	_, err := r.DB.Exec(query, timeEntryID)
+	return err
+}
+
+// utcOrNil returns a nullable time for a DATETIME column.
+func utcOrNil(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	model "dailyworkerroster/model"
+)
+
+type TimeOffRepoItf interface {
+	CreateTimeOff(timeOff *model.TimeOff) (int64, error)
+	GetTimeOffByID(id int64) (*model.TimeOff, error)
+	GetTimeOffByIDForUpdate(id int64) (*model.TimeOff, error)
+	ListTimeOff(query model.TimeOffQuery) ([]*model.TimeOff, error)
+	UpdateTimeOffStatus(id int64, status string, reviewedBy *int64) error
+}
+
+const timeOffColumns = `id, user_account_id, type, start_date, end_date, reason, status,
+        reviewed_by, reviewed_at, created_at, updated_at`
+
+func scanTimeOff(row rowScanner) (*model.TimeOff, error) {
+	var timeOff model.TimeOff
+	err := row.Scan(
+		&timeOff.ID, &timeOff.UserAccountID, &timeOff.Type,
+		(*dateColumn)(&timeOff.StartDate), (*dateColumn)(&timeOff.EndDate), &timeOff.Reason, &timeOff.Status,
+		&timeOff.ReviewedBy, &timeOff.ReviewedAt, &timeOff.CreatedAt, &timeOff.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &timeOff, nil
+}
+
+type TimeOffRepository struct {
+	DB      DBTX
+	Dialect Dialect
+}
+
+func NewTimeOffRepository(db DBTX) TimeOffRepoItf {
+	return &TimeOffRepository{DB: db, Dialect: DialectMySQL}
+}
+
+func NewSQLiteTimeOffRepository(db DBTX) TimeOffRepoItf {
+	return &TimeOffRepository{DB: db, Dialect: DialectSQLite}
+}
+
+func (r *TimeOffRepository) CreateTimeOff(timeOff *model.TimeOff) (int64, error) {
+	query := `
+        INSERT INTO time_off (user_account_id, type, start_date, end_date, reason, status, created_at, updated_at)
+        VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+    `
+	result, err := r.DB.Exec(query, timeOff.UserAccountID, timeOff.Type, timeOff.StartDate, timeOff.EndDate,
+		timeOff.Reason, timeOff.Status)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+func (r *TimeOffRepository) GetTimeOffByID(id int64) (*model.TimeOff, error) {
+	query := `SELECT ` + timeOffColumns + ` FROM time_off WHERE id = ?`
+	return scanTimeOff(r.DB.QueryRow(query, id))
+}
+
+func (r *TimeOffRepository) GetTimeOffByIDForUpdate(id int64) (*model.TimeOff, error) {
+	query := `SELECT ` + timeOffColumns + ` FROM time_off WHERE id = ? ` + r.Dialect.ForUpdate()
+	return scanTimeOff(r.DB.QueryRow(query, id))
+}
+
+func (r *TimeOffRepository) ListTimeOff(queryParam model.TimeOffQuery) ([]*model.TimeOff, error) {
+	query := `SELECT ` + timeOffColumns + ` FROM time_off WHERE 1=1`
+	args := []interface{}{}
+
+	if queryParam.UserAccountID != nil {
+		query += " AND user_account_id = ?"
+		args = append(args, *queryParam.UserAccountID)
+	}
+	if queryParam.Status != nil {
+		query += " AND status = ?"
+		args = append(args, *queryParam.Status)
+	}
+	if queryParam.DateFrom != nil {
+		query += " AND end_date >= ?"
+		args = append(args, *queryParam.DateFrom)
+	}
+	if queryParam.DateTo != nil {
+		query += " AND start_date <= ?"
+		args = append(args, *queryParam.DateTo)
+	}
+	query += " ORDER BY start_date, id"
+
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := make([]*model.TimeOff, 0)
+	for rows.Next() {
+		timeOff, err := scanTimeOff(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, timeOff)
+	}
+	return list, nil
+}
+
+func (r *TimeOffRepository) UpdateTimeOffStatus(id int64, status string, reviewedBy *int64) error {
+	query := `
+        UPDATE time_off
+        SET status = ?, reviewed_by = ?, reviewed_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+        WHERE id = ?
+    `
+	_, err := r.DB.Exec(query, status, reviewedBy, id)
+	return err
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+)
+
+// DBTX is satisfied by both *sql.DB and *sql.Tx, so every repository can
+// run either directly against the pool or inside a transaction.
+type DBTX interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// Repositories groups the repositories bound to the same DBTX.
+type Repositories struct {
+	User          UserRepoItf
+	Shift         ShiftRepoItf
+	WorkerShift   WorkerShiftRepoItf
+	ShiftTemplate ShiftTemplateRepoItf
+	ShiftTransfer ShiftTransferRepoItf
+	JobLock       JobLockRepoItf
+	UserSession   UserSessionRepoItf
+	Location      LocationRepoItf
+	Skill         SkillRepoItf
+	WorkerSkill   WorkerSkillRepoItf
+	Availability  AvailabilityRepoItf
+	TimeOff       TimeOffRepoItf
+	TimeEntry     TimeEntryRepoItf
+}
+
+// NewRepositories builds the repository set for the given dialect. Shift
+// times at locations without a time zone are read in zone.
+func NewRepositories(db DBTX, dialect Dialect, zone *time.Location) *Repositories {
+	if dialect == DialectSQLite {
+		return &Repositories{
+			User:          NewSQLiteUserRepository(db),
+			Shift:         NewSQLiteShiftRepository(db, zone),
+			WorkerShift:   NewSQLiteWorkerShiftRepository(db, zone),
+			ShiftTemplate: NewSQLiteShiftTemplateRepository(db),
+			ShiftTransfer: NewSQLiteShiftTransferRepository(db),
+			JobLock:       NewSQLiteJobLockRepository(db),
+			UserSession:   NewSQLiteUserSessionRepository(db),
+			Location:      NewSQLiteLocationRepository(db),
+			Skill:         NewSQLiteSkillRepository(db),
+			WorkerSkill:   NewSQLiteWorkerSkillRepository(db),
+			Availability:  NewSQLiteAvailabilityRepository(db),
+			TimeOff:       NewSQLiteTimeOffRepository(db),
+			TimeEntry:     NewSQLiteTimeEntryRepository(db),
+		}
+	}
+	return &Repositories{
+		User:          NewUserRepository(db),
+		Shift:         NewShiftRepository(db, zone),
+		WorkerShift:   NewWorkerShiftRepository(db, zone),
+		ShiftTemplate: NewShiftTemplateRepository(db),
+		ShiftTransfer: NewShiftTransferRepository(db),
+		JobLock:       NewJobLockRepository(db),
+		UserSession:   NewUserSessionRepository(db),
+		Location:      NewLocationRepository(db),
+		Skill:         NewSkillRepository(db),
+		WorkerSkill:   NewWorkerSkillRepository(db),
+		Availability:  NewAvailabilityRepository(db),
+		TimeOff:       NewTimeOffRepository(db),
+		TimeEntry:     NewTimeEntryRepository(db),
+	}
+}
+
+type UnitOfWorkItf interface {
+	// WithinTx runs fn with repositories bound to a single transaction.
+	// The transaction is committed when fn returns nil and rolled back otherwise.
+	WithinTx(ctx context.Context, fn func(repos *Repositories) error) error
+}
+
+type UnitOfWork struct {
+	DB      *sql.DB
+	Dialect Dialect
+	Zone    *time.Location // zone of locations without one
+}
+
+func NewUnitOfWork(db *sql.DB, dialect Dialect, zone *time.Location) UnitOfWorkItf {
+	return &UnitOfWork{DB: db, Dialect: dialect, Zone: zone}
+}
+
+func (u *UnitOfWork) WithinTx(ctx context.Context, fn func(repos *Repositories) error) (err error) {
+	funcName := "/repository/unit_of_work/WithinTx"
+
+	tx, err := u.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err = fn(NewRepositories(tx, u.Dialect, u.Zone)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Printf("%s: Rollback error: %v", funcName, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
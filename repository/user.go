@@ -2,7 +2,6 @@ package repository
 
 import (
 	"dailyworkerroster/model"
-	"database/sql"
 )
 
 type UserRepoItf interface {
@@ -10,25 +9,53 @@ type UserRepoItf interface {
 	Login(identifier string) (*model.User, error)
 	GetUsersByRole(role string) ([]*model.User, error)
 	GetUserByID(id int64) (*model.User, error)
+	GetUserByIDForUpdate(id int64) (*model.User, error)
 }
 
 type UserRepository struct {
-	DB *sql.DB
+	DB      DBTX
+	Dialect Dialect
 }
 
-func NewUserRepository(db *sql.DB) UserRepoItf {
+const userColumns = "id, name, username, email, password, role, date_of_birth, created_at, updated_at"
+
+func scanUser(row rowScanner) (*model.User, error) {
+	var user model.User
+	var dateOfBirth nullDateColumn
+	err := row.Scan(
+		&user.ID, &user.Name, &user.Username, &user.Email, &user.Password,
+		&user.Role, &dateOfBirth, &user.CreatedAt, &user.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if dateOfBirth.Valid {
+		user.DateOfBirth = &dateOfBirth.String
+	}
+	return &user, nil
+}
+
+func NewUserRepository(db DBTX) UserRepoItf {
 	return &UserRepository{
-		DB: db,
+		DB:      db,
+		Dialect: DialectMySQL,
+	}
+}
+
+func NewSQLiteUserRepository(db DBTX) UserRepoItf {
+	return &UserRepository{
+		DB:      db,
+		Dialect: DialectSQLite,
 	}
 }
 
 // SignUp inserts a new user into the user_account table
 func (r *UserRepository) SignUp(user *model.User) (int64, error) {
 	query := `
-        INSERT INTO user_account (name, username, email, password, role, created_at, updated_at)
-        VALUES (?, ?, ?, ?, ?, NOW(), NOW())
+        INSERT INTO user_account (name, username, email, password, role, date_of_birth, created_at, updated_at)
+        VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
     `
-	result, err := r.DB.Exec(query, user.Name, user.Username, user.Email, user.Password, user.Role)
+	result, err := r.DB.Exec(query, user.Name, user.Username, user.Email, user.Password, user.Role, user.DateOfBirth)
 	if err != nil {
 		return 0, err
 	}
@@ -38,24 +65,16 @@ func (r *UserRepository) SignUp(user *model.User) (int64, error) {
 // Login checks if a user exists with the given username/email and password
 func (r *UserRepository) Login(identifier string) (*model.User, error) {
 	query := `
-        SELECT id, name, username, email, password, role, created_at, updated_at
+        SELECT ` + userColumns + `
         FROM user_account
         WHERE (username = ? OR email = ?)
     `
-	var user model.User
-	err := r.DB.QueryRow(query, identifier, identifier).Scan(
-		&user.ID, &user.Name, &user.Username, &user.Email, &user.Password,
-		&user.Role, &user.CreatedAt, &user.UpdatedAt,
-	)
-	if err != nil {
-		return nil, err
-	}
-	return &user, nil
+	return scanUser(r.DB.QueryRow(query, identifier, identifier))
 }
 
 func (r *UserRepository) GetUsersByRole(role string) ([]*model.User, error) {
 	query := `
-        SELECT id, name, username, email, password, role, created_at, updated_at
+        SELECT ` + userColumns + `
         FROM user_account
         WHERE role = ?
     `
@@ -67,33 +86,33 @@ func (r *UserRepository) GetUsersByRole(role string) ([]*model.User, error) {
 
 	var users []*model.User
 	for rows.Next() {
-		var user model.User
-		err := rows.Scan(
-			&user.ID, &user.Name, &user.Username, &user.Email, &user.Password,
-			&user.Role, &user.CreatedAt, &user.UpdatedAt,
-		)
+		user, err := scanUser(rows)
 		if err != nil {
 			return nil, err
 		}
-		users = append(users, &user)
+		users = append(users, user)
 	}
 	return users, nil
 }
 
 func (r *UserRepository) GetUserByID(id int64) (*model.User, error) {
 	query := `
-        SELECT id, name, username, email, password, role, created_at, updated_at
+        SELECT ` + userColumns + `
         FROM user_account
         WHERE id = ?
         LIMIT 1
     `
-	var user model.User
-	err := r.DB.QueryRow(query, id).Scan(
-		&user.ID, &user.Name, &user.Username, &user.Email, &user.Password,
-		&user.Role, &user.CreatedAt, &user.UpdatedAt,
-	)
-	if err != nil {
-		return nil, err
-	}
-	return &user, nil
+	return scanUser(r.DB.QueryRow(query, id))
+}
+
+// GetUserByIDForUpdate retrieves a user by ID and locks the row until the
+// surrounding transaction ends. It is used to serialize per-worker checks.
+func (r *UserRepository) GetUserByIDForUpdate(id int64) (*model.User, error) {
+	query := `
+        SELECT ` + userColumns + `
+        FROM user_account
+        WHERE id = ?
+        LIMIT 1
+    ` + r.Dialect.ForUpdate()
+	return scanUser(r.DB.QueryRow(query, id))
 }
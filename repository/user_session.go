@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"time"
+
+	model "dailyworkerroster/model"
+)
+
+type UserSessionRepoItf interface {
+	CreateUserSession(session *model.UserSession) error
+	GetUserSessionByID(id string) (*model.UserSession, error)
+	GetUserSessionByIDForUpdate(id string) (*model.UserSession, error)
+	RotateUserSession(id, refreshTokenHash string, expiresAt time.Time) error
+	RevokeUserSession(id string, at time.Time) error
+	RevokeUserSessionsByUser(userID int64, at time.Time) (int64, error)
+}
+
+type UserSessionRepository struct {
+	DB      DBTX
+	Dialect Dialect
+}
+
+func NewUserSessionRepository(db DBTX) UserSessionRepoItf {
+	return &UserSessionRepository{DB: db, Dialect: DialectMySQL}
+}
+
+func NewSQLiteUserSessionRepository(db DBTX) UserSessionRepoItf {
+	return &UserSessionRepository{DB: db, Dialect: DialectSQLite}
+}
+
+const userSessionColumns = "id, user_account_id, refresh_token_hash, previous_token_hash, expires_at, revoked_at, created_at, updated_at"
+
+func scanUserSession(row rowScanner) (*model.UserSession, error) {
+	var session model.UserSession
+	err := row.Scan(
+		&session.ID, &session.UserAccountID, &session.RefreshTokenHash, &session.PreviousTokenHash,
+		&session.ExpiresAt, &session.RevokedAt, &session.CreatedAt, &session.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *UserSessionRepository) CreateUserSession(session *model.UserSession) error {
+	query := `
+        INSERT INTO user_session (id, user_account_id, refresh_token_hash, expires_at, created_at, updated_at)
+        VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+    `
+	_, err := r.DB.Exec(query, session.ID, session.UserAccountID, session.RefreshTokenHash, session.ExpiresAt.UTC())
+	return err
+}
+
+func (r *UserSessionRepository) GetUserSessionByID(id string) (*model.UserSession, error) {
+	query := `SELECT ` + userSessionColumns + ` FROM user_session WHERE id = ?`
+	return scanUserSession(r.DB.QueryRow(query, id))
+}
+
+func (r *UserSessionRepository) GetUserSessionByIDForUpdate(id string) (*model.UserSession, error) {
+	query := `SELECT ` + userSessionColumns + ` FROM user_session WHERE id = ? ` + r.Dialect.ForUpdate()
+	return scanUserSession(r.DB.QueryRow(query, id))
+}
+
+// RotateUserSession replaces the refresh token of a session, keeping the
+// hash of the old one, and extends it.
+func (r *UserSessionRepository) RotateUserSession(id, refreshTokenHash string, expiresAt time.Time) error {
+	query := `
+        UPDATE user_session
+        SET previous_token_hash = refresh_token_hash, refresh_token_hash = ?, expires_at = ?, updated_at = CURRENT_TIMESTAMP
+        WHERE id = ?
+    `
+	_, err := r.DB.Exec(query, refreshTokenHash, expiresAt.UTC(), id)
+	return err
+}
+
+func (r *UserSessionRepository) RevokeUserSession(id string, at time.Time) error {
+	query := `
+        UPDATE user_session
+        SET revoked_at = ?, updated_at = CURRENT_TIMESTAMP
+        WHERE id = ? AND revoked_at IS NULL
+    `
+	_, err := r.DB.Exec(query, at.UTC(), id)
+	return err
+}
+
+// RevokeUserSessionsByUser revokes every live session of a user and returns
+// how many were revoked.
+func (r *UserSessionRepository) RevokeUserSessionsByUser(userID int64, at time.Time) (int64, error) {
+	query := `
+        UPDATE user_session
+        SET revoked_at = ?, updated_at = CURRENT_TIMESTAMP
+        WHERE user_account_id = ? AND revoked_at IS NULL
+    `
+	result, err := r.DB.Exec(query, at.UTC(), userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,116 @@
+package repository_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"dailyworkerroster/model"
+	"dailyworkerroster/repository"
+)
+
+func TestUserRepoSignUpAndLogin(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos *repository.Repositories) {
+		birth := "1990-02-28"
+		id, err := repos.User.SignUp(&model.User{
+			Name: "Ann", Username: "ann", Email: "ann@example.com", Password: "hash",
+			Role: model.ROLE_WORKER, DateOfBirth: &birth,
+		})
+		if err != nil {
+			t.Fatalf("SignUp: %v", err)
+		}
+
+		for _, identifier := range []string{"ann", "ann@example.com"} {
+			user, err := repos.User.Login(identifier)
+			if err != nil {
+				t.Fatalf("Login(%q): %v", identifier, err)
+			}
+			if user.ID != id || user.Password != "hash" || user.Role != model.ROLE_WORKER {
+				t.Errorf("Login(%q) = %+v", identifier, user)
+			}
+			if user.DateOfBirth == nil || *user.DateOfBirth != birth {
+				t.Errorf("Login(%q) date of birth = %v, want %s", identifier, user.DateOfBirth, birth)
+			}
+		}
+		if _, err := repos.User.Login("bob"); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Login of an unknown user: err = %v, want sql.ErrNoRows", err)
+		}
+
+		_, err = repos.User.SignUp(&model.User{
+			Name: "Ann B", Username: "ann", Email: "other@example.com", Password: "hash", Role: model.ROLE_WORKER,
+		})
+		if err == nil {
+			t.Error("SignUp with a taken username succeeded")
+		}
+	})
+}
+
+func TestUserRepoGet(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos *repository.Repositories) {
+		admin := createTestUser(t, repos, "admin", model.ROLE_ADMIN)
+		ann := createTestUser(t, repos, "ann", model.ROLE_WORKER)
+		bob := createTestUser(t, repos, "bob", model.ROLE_WORKER)
+
+		user, err := repos.User.GetUserByID(admin)
+		if err != nil {
+			t.Fatalf("GetUserByID: %v", err)
+		}
+		if user.Username != "admin" || user.Role != model.ROLE_ADMIN || user.DateOfBirth != nil {
+			t.Errorf("GetUserByID = %+v", user)
+		}
+		if user, err := repos.User.GetUserByIDForUpdate(bob); err != nil || user.Username != "bob" {
+			t.Errorf("GetUserByIDForUpdate = %v, %v", user, err)
+		}
+		if _, err := repos.User.GetUserByID(bob + 100); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("GetUserByID of a missing user: err = %v, want sql.ErrNoRows", err)
+		}
+
+		workers, err := repos.User.GetUsersByRole(model.ROLE_WORKER)
+		if err != nil {
+			t.Fatalf("GetUsersByRole: %v", err)
+		}
+		got := make(map[int64]bool)
+		for _, worker := range workers {
+			got[worker.ID] = true
+		}
+		if len(workers) != 2 || !got[ann] || !got[bob] {
+			t.Errorf("GetUsersByRole(WORKER) returned %d users %v, want ann and bob", len(workers), got)
+		}
+	})
+}
+
+func TestUserSessionRepoRotate(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos *repository.Repositories) {
+		ann := createTestUser(t, repos, "ann", model.ROLE_WORKER)
+		session := &model.UserSession{
+			ID: "session", UserAccountID: ann, RefreshTokenHash: "first",
+			ExpiresAt: time.Now().Add(time.Hour).Truncate(time.Second),
+		}
+		if err := repos.UserSession.CreateUserSession(session); err != nil {
+			t.Fatalf("CreateUserSession: %v", err)
+		}
+		got, err := repos.UserSession.GetUserSessionByID("session")
+		if err != nil {
+			t.Fatalf("GetUserSessionByID: %v", err)
+		}
+		if got.RefreshTokenHash != "first" || got.PreviousTokenHash != nil {
+			t.Errorf("new session has hash %q and previous %v, want first and none", got.RefreshTokenHash, got.PreviousTokenHash)
+		}
+
+		expiresAt := session.ExpiresAt.Add(time.Hour)
+		if err := repos.UserSession.RotateUserSession("session", "second", expiresAt); err != nil {
+			t.Fatalf("RotateUserSession: %v", err)
+		}
+		got, err = repos.UserSession.GetUserSessionByID("session")
+		if err != nil {
+			t.Fatalf("GetUserSessionByID: %v", err)
+		}
+		if got.RefreshTokenHash != "second" || got.PreviousTokenHash == nil || *got.PreviousTokenHash != "first" {
+			t.Errorf("rotated session has hash %q and previous %v, want second and first", got.RefreshTokenHash, got.PreviousTokenHash)
+		}
+		if !got.ExpiresAt.Equal(expiresAt) {
+			t.Errorf("expires at %v, want %v", got.ExpiresAt, expiresAt)
+		}
+	})
+}
@@ -3,33 +3,63 @@ package repository
 import (
 	model "dailyworkerroster/model"
 	"database/sql"
+	"strings"
 	"time"
 )
 
 type WorkerShiftRepoItf interface {
 	CreateWorkerShift(ws *model.WorkerShift) (int64, error)
 	GetWorkerShiftByID(id int64) (*model.WorkerShift, error)
+	GetWorkerShiftByIDForUpdate(id int64) (*model.WorkerShift, error)
+	ReassignWorkerShift(id, userAccountID int64, approvedBy *int64) error
+	FlagForReassignment(id int64) error
+	CreateWorkerShiftHistory(history *model.WorkerShiftHistory) (int64, error)
+	ListWorkerShiftHistory(workerShiftID int64) ([]model.WorkerShiftHistory, error)
 	GetWorkerShiftListByFilter(userAccountID *int64, status *string) ([]model.WorkerShift, error)
 	UpdatesWorkerShiftStatus(id int64, status string, approvedBy *int64) error
 	DeleteWorkerShiftByID(id int64) error
+	DeleteWorkerShiftsByShift(shiftID int64) error
 	ListWorkerShiftsByUser(userID int64) ([]*model.WorkerShift, error)
 	ListWorkerShiftsByShift(shiftID int64) ([]*model.WorkerShift, error)
-	CheckWorkerShiftLimits(userAccountID int64, date string) (bool, int, error)
+	CountWorkerShiftsByShiftIDs(shiftIDs []int64, status string) (map[int64]int, error)
 	GetWorkerShiftDetailListByFilter(queryParam *model.WorkerShiftDetailQuery) ([]model.WorkerShiftDetail, error)
+	WaitlistWorkerShift(id int64, position int) error
+	OfferWorkerShift(id int64, expiresAt time.Time) error
+}
+
+const workerShiftColumns = `id, shift_id, user_account_id, approved_by, status, created_at, updated_at,
+        waitlist_position, offer_expires_at`
+
+func scanWorkerShift(row rowScanner) (*model.WorkerShift, error) {
+	var ws model.WorkerShift
+	err := row.Scan(
+		&ws.ID, &ws.ShiftID, &ws.UserAccountID, &ws.ApprovedBy, &ws.Status, &ws.CreatedAt, &ws.UpdatedAt,
+		&ws.WaitlistPosition, &ws.OfferExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &ws, nil
 }
 
 type WorkerShiftRepository struct {
-	DB *sql.DB
+	DB      DBTX
+	Dialect Dialect
+	Zone    *time.Location // zone of locations without one
 }
 
-func NewWorkerShiftRepository(db *sql.DB) WorkerShiftRepoItf {
-	return &WorkerShiftRepository{DB: db}
+func NewWorkerShiftRepository(db DBTX, zone *time.Location) WorkerShiftRepoItf {
+	return &WorkerShiftRepository{DB: db, Dialect: DialectMySQL, Zone: zone}
+}
+
+func NewSQLiteWorkerShiftRepository(db DBTX, zone *time.Location) WorkerShiftRepoItf {
+	return &WorkerShiftRepository{DB: db, Dialect: DialectSQLite, Zone: zone}
 }
 
 func (r *WorkerShiftRepository) CreateWorkerShift(ws *model.WorkerShift) (int64, error) {
 	query := `
         INSERT INTO worker_shift (shift_id, user_account_id, approved_by, status, created_at, updated_at)
-        VALUES (?, ?, ?, ?, NOW(), NOW())
+        VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
     `
 	result, err := r.DB.Exec(query, ws.ShiftID, ws.UserAccountID, ws.ApprovedBy, ws.Status)
 	if err != nil {
@@ -40,22 +70,81 @@ func (r *WorkerShiftRepository) CreateWorkerShift(ws *model.WorkerShift) (int64,
 
 func (r *WorkerShiftRepository) GetWorkerShiftByID(id int64) (*model.WorkerShift, error) {
 	query := `
-        SELECT id, shift_id, user_account_id, approved_by, status, created_at, updated_at
+        SELECT ` + workerShiftColumns + `
         FROM worker_shift WHERE id = ?
     `
-	var ws model.WorkerShift
-	err := r.DB.QueryRow(query, id).Scan(
-		&ws.ID, &ws.ShiftID, &ws.UserAccountID, &ws.ApprovedBy, &ws.Status, &ws.CreatedAt, &ws.UpdatedAt,
-	)
+	return scanWorkerShift(r.DB.QueryRow(query, id))
+}
+
+func (r *WorkerShiftRepository) GetWorkerShiftByIDForUpdate(id int64) (*model.WorkerShift, error) {
+	query := `
+        SELECT ` + workerShiftColumns + `
+        FROM worker_shift WHERE id = ?
+    ` + r.Dialect.ForUpdate()
+	return scanWorkerShift(r.DB.QueryRow(query, id))
+}
+
+// ReassignWorkerShift moves a worker shift to another worker, keeping its status
+func (r *WorkerShiftRepository) ReassignWorkerShift(id, userAccountID int64, approvedBy *int64) error {
+	query := `
+        UPDATE worker_shift
+        SET user_account_id = ?, approved_by = ?, needs_reassignment = FALSE, updated_at = CURRENT_TIMESTAMP
+        WHERE id = ?
+    `
+	_, err := r.DB.Exec(query, userAccountID, approvedBy, id)
+	return err
+}
+
+// FlagForReassignment marks an approved shift its worker can no longer work
+func (r *WorkerShiftRepository) FlagForReassignment(id int64) error {
+	query := `
+        UPDATE worker_shift SET needs_reassignment = TRUE, updated_at = CURRENT_TIMESTAMP
+        WHERE id = ?
+    `
+	_, err := r.DB.Exec(query, id)
+	return err
+}
+
+func (r *WorkerShiftRepository) CreateWorkerShiftHistory(history *model.WorkerShiftHistory) (int64, error) {
+	query := `
+        INSERT INTO worker_shift_history (worker_shift_id, from_user_id, to_user_id, transfer_id, changed_by, created_at)
+        VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+    `
+	result, err := r.DB.Exec(query, history.WorkerShiftID, history.FromUserID, history.ToUserID, history.TransferID, history.ChangedBy)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+func (r *WorkerShiftRepository) ListWorkerShiftHistory(workerShiftID int64) ([]model.WorkerShiftHistory, error) {
+	query := `
+        SELECT id, worker_shift_id, from_user_id, to_user_id, transfer_id, changed_by, created_at
+        FROM worker_shift_history
+        WHERE worker_shift_id = ?
+        ORDER BY created_at, id
+    `
+	rows, err := r.DB.Query(query, workerShiftID)
 	if err != nil {
 		return nil, err
 	}
-	return &ws, nil
+	defer rows.Close()
+
+	var list []model.WorkerShiftHistory
+	for rows.Next() {
+		var h model.WorkerShiftHistory
+		err := rows.Scan(&h.ID, &h.WorkerShiftID, &h.FromUserID, &h.ToUserID, &h.TransferID, &h.ChangedBy, &h.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, h)
+	}
+	return list, nil
 }
 
 func (r *WorkerShiftRepository) GetWorkerShiftListByFilter(userAccountID *int64, status *string) ([]model.WorkerShift, error) {
 	query := `
-        SELECT id, shift_id, user_account_id, approved_by, status, created_at, updated_at
+        SELECT ` + workerShiftColumns + `
         FROM worker_shift WHERE 1=1
     `
 	args := []interface{}{}
@@ -79,37 +168,65 @@ func (r *WorkerShiftRepository) GetWorkerShiftListByFilter(userAccountID *int64,
 
 	var list []model.WorkerShift
 	for rows.Next() {
-		var ws model.WorkerShift
-		err := rows.Scan(
-			&ws.ID, &ws.ShiftID, &ws.UserAccountID, &ws.ApprovedBy, &ws.Status, &ws.CreatedAt, &ws.UpdatedAt,
-		)
+		ws, err := scanWorkerShift(rows)
 		if err != nil {
 			return nil, err
 		}
-		list = append(list, ws)
+		list = append(list, *ws)
 	}
 	return list, nil
 }
 
+// UpdatesWorkerShiftStatus sets the status, leaving the waitlist
 func (r *WorkerShiftRepository) UpdatesWorkerShiftStatus(id int64, status string, approvedBy *int64) error {
 	query := `
         UPDATE worker_shift
-        SET status = ?, approved_by = ?, updated_at = NOW()
+        SET status = ?, approved_by = ?, waitlist_position = NULL, offer_expires_at = NULL,
+            updated_at = CURRENT_TIMESTAMP
         WHERE id = ?
     `
 	_, err := r.DB.Exec(query, status, approvedBy, id)
 	return err
 }
 
+// WaitlistWorkerShift puts a request on its shift's waitlist at position
+func (r *WorkerShiftRepository) WaitlistWorkerShift(id int64, position int) error {
+	query := `
+        UPDATE worker_shift
+        SET status = ?, waitlist_position = ?, offer_expires_at = NULL, updated_at = CURRENT_TIMESTAMP
+        WHERE id = ?
+    `
+	_, err := r.DB.Exec(query, model.WORKER_SHIFT_WAITLISTED, position, id)
+	return err
+}
+
+// OfferWorkerShift offers a waitlisted request a place until expiresAt,
+// keeping its waitlist position
+func (r *WorkerShiftRepository) OfferWorkerShift(id int64, expiresAt time.Time) error {
+	query := `
+        UPDATE worker_shift
+        SET status = ?, offer_expires_at = ?, updated_at = CURRENT_TIMESTAMP
+        WHERE id = ?
+    `
+	_, err := r.DB.Exec(query, model.WORKER_SHIFT_OFFERED, expiresAt.UTC(), id)
+	return err
+}
+
 func (r *WorkerShiftRepository) DeleteWorkerShiftByID(id int64) error {
 	query := `DELETE FROM worker_shift WHERE id = ?`
 	_, err := r.DB.Exec(query, id)
 	return err
 }
 
+func (r *WorkerShiftRepository) DeleteWorkerShiftsByShift(shiftID int64) error {
+	query := `DELETE FROM worker_shift WHERE shift_id = ?`
+	_, err := r.DB.Exec(query, shiftID)
+	return err
+}
+
 func (r *WorkerShiftRepository) ListWorkerShiftsByUser(userID int64) ([]*model.WorkerShift, error) {
 	query := `
-        SELECT id, shift_id, user_account_id, approved_by, status, created_at, updated_at
+        SELECT ` + workerShiftColumns + `
         FROM worker_shift WHERE user_account_id = ?
     `
 	rows, err := r.DB.Query(query, userID)
@@ -120,21 +237,18 @@ func (r *WorkerShiftRepository) ListWorkerShiftsByUser(userID int64) ([]*model.W
 
 	var list []*model.WorkerShift
 	for rows.Next() {
-		var ws model.WorkerShift
-		err := rows.Scan(
-			&ws.ID, &ws.ShiftID, &ws.UserAccountID, &ws.ApprovedBy, &ws.Status, &ws.CreatedAt, &ws.UpdatedAt,
-		)
+		ws, err := scanWorkerShift(rows)
 		if err != nil {
 			return nil, err
 		}
-		list = append(list, &ws)
+		list = append(list, ws)
 	}
 	return list, nil
 }
 
 func (r *WorkerShiftRepository) ListWorkerShiftsByShift(shiftID int64) ([]*model.WorkerShift, error) {
 	query := `
-        SELECT id, shift_id, user_account_id, approved_by, status, created_at, updated_at
+        SELECT ` + workerShiftColumns + `
         FROM worker_shift WHERE shift_id = ?
     `
 	rows, err := r.DB.Query(query, shiftID)
@@ -145,53 +259,58 @@ func (r *WorkerShiftRepository) ListWorkerShiftsByShift(shiftID int64) ([]*model
 
 	var list []*model.WorkerShift
 	for rows.Next() {
-		var ws model.WorkerShift
-		err := rows.Scan(
-			&ws.ID, &ws.ShiftID, &ws.UserAccountID, &ws.ApprovedBy, &ws.Status, &ws.CreatedAt, &ws.UpdatedAt,
-		)
+		ws, err := scanWorkerShift(rows)
 		if err != nil {
 			return nil, err
 		}
-		list = append(list, &ws)
+		list = append(list, ws)
 	}
 	return list, nil
 }
 
-func (r *WorkerShiftRepository) CheckWorkerShiftLimits(userAccountID int64, date string) (hasShiftOnDay bool, shiftsThisWeek int, err error) {
+// CountWorkerShiftsByShiftIDs returns, per shift, how many worker shifts are
+// in the given status. Shifts without any are absent from the map.
+func (r *WorkerShiftRepository) CountWorkerShiftsByShiftIDs(shiftIDs []int64, status string) (map[int64]int, error) {
+	counts := make(map[int64]int)
+	if len(shiftIDs) == 0 {
+		return counts, nil
+	}
+
+	placeholders := make([]string, len(shiftIDs))
+	args := []interface{}{status}
+	for i, id := range shiftIDs {
+		placeholders[i] = "?"
+		args = append(args, id)
+	}
+
 	query := `
-        SELECT 
-            COUNT(CASE WHEN s.date = ? THEN 1 END) AS shifts_on_day,
-            COUNT(*) AS shifts_in_week
-        FROM worker_shift ws
-        JOIN shift s ON ws.shift_id = s.id
-        WHERE ws.user_account_id = ?
-            AND ws.status = 'APPROVED'
-            AND s.date BETWEEN ? AND ?
+        SELECT shift_id, COUNT(*)
+        FROM worker_shift
+        WHERE status = ? AND shift_id IN (` + strings.Join(placeholders, ",") + `)
+        GROUP BY shift_id
     `
-
-	parsedDate, err := time.Parse("2006-01-02", date)
+	rows, err := r.DB.Query(query, args...)
 	if err != nil {
-		return false, 0, err
-	}
-	weekday := int(parsedDate.Weekday())
-	if weekday == 0 {
-		weekday = 7
+		return nil, err
 	}
-	monday := parsedDate.AddDate(0, 0, -weekday+1)
-	sunday := monday.AddDate(0, 0, 6)
+	defer rows.Close()
 
-	var shiftsOnDay, shiftsInWeek int
-	err = r.DB.QueryRow(query, date, userAccountID, monday.Format("2006-01-02"), sunday.Format("2006-01-02")).Scan(&shiftsOnDay, &shiftsInWeek)
-	if err != nil {
-		return false, 0, err
+	for rows.Next() {
+		var shiftID int64
+		var count int
+		if err := rows.Scan(&shiftID, &count); err != nil {
+			return nil, err
+		}
+		counts[shiftID] = count
 	}
-	return shiftsOnDay > 0, shiftsInWeek, nil
+	return counts, nil
 }
 
 func (r *WorkerShiftRepository) GetWorkerShiftDetailListByFilter(queryParam *model.WorkerShiftDetailQuery) ([]model.WorkerShiftDetail, error) {
 	query := `
-        SELECT ws.id, ws.shift_id, ws.user_account_id, ws.approved_by, ws.status,
-               s.date, s.start_time, s.end_time, s.role_assignment, s.location, s.isAvailable
+        SELECT ws.id, ws.shift_id, ws.user_account_id, ws.approved_by, ws.status, ws.needs_reassignment,
+               ws.waitlist_position, ws.offer_expires_at, s.date, s.start_time, s.end_time, s.start_at, s.end_at, s.role_assignment, ` + locationRef("s") + `,
+               s.isAvailable, s.headcount
         FROM worker_shift ws
         JOIN shift s ON ws.shift_id = s.id
         WHERE 1=1
@@ -210,16 +329,28 @@ func (r *WorkerShiftRepository) GetWorkerShiftDetailListByFilter(queryParam *mod
 		query += " AND s.role_assignment = ?"
 		args = append(args, *queryParam.Role)
 	}
-	if queryParam.Location != nil {
-		query += " AND s.location = ?"
-		args = append(args, *queryParam.Location)
+	if queryParam.LocationID != nil {
+		query += " AND s.location_id = ?"
+		args = append(args, *queryParam.LocationID)
+	}
+	if queryParam.NeedsReassignment != nil {
+		query += " AND ws.needs_reassignment = ?"
+		args = append(args, *queryParam.NeedsReassignment)
+	}
+	if queryParam.DateFrom != nil {
+		query += " AND s.date >= ?"
+		args = append(args, *queryParam.DateFrom)
+	}
+	if queryParam.DateTo != nil {
+		query += " AND s.date <= ?"
+		args = append(args, *queryParam.DateTo)
 	}
 	query += " ORDER BY ws.updated_at DESC"
 	if queryParam.Limit != nil {
 		query += " LIMIT ?"
 		args = append(args, *queryParam.Limit)
 	}
-	if queryParam.Offset != nil {
+	if queryParam.Limit != nil && queryParam.Offset != nil {
 		query += " OFFSET ?"
 		args = append(args, *queryParam.Offset)
 	}
@@ -233,13 +364,20 @@ func (r *WorkerShiftRepository) GetWorkerShiftDetailListByFilter(queryParam *mod
 	var list []model.WorkerShiftDetail
 	for rows.Next() {
 		var ws model.WorkerShiftDetail
+		var zone sql.NullString
 		err := rows.Scan(
-			&ws.ID, &ws.ShiftID, &ws.UserAccountID, &ws.ApprovedBy, &ws.Status,
-			&ws.Date, &ws.StartTime, &ws.EndTime, &ws.RoleAssignment, &ws.Location, &ws.IsAvailable,
+			&ws.ID, &ws.ShiftID, &ws.UserAccountID, &ws.ApprovedBy, &ws.Status, &ws.NeedsReassignment,
+			&ws.WaitlistPosition, &ws.OfferExpiresAt, (*dateColumn)(&ws.Date), &ws.StartTime, &ws.EndTime, (*utcColumn)(&ws.StartAt), (*utcColumn)(&ws.EndAt), &ws.RoleAssignment,
+			&ws.LocationID, &ws.Location, &zone, &ws.IsAvailable, &ws.Headcount,
 		)
 		if err != nil {
 			return nil, err
 		}
+		loc, err := inZone(zone.String, r.Zone, &ws.StartAt, &ws.EndAt)
+		if err != nil {
+			return nil, err
+		}
+		ws.TimeZone = loc.String()
 		list = append(list, ws)
 	}
 	return list, nil
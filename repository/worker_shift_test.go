@@ -0,0 +1,148 @@
+package repository_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"dailyworkerroster/model"
+	"dailyworkerroster/repository"
+)
+
+func TestWorkerShiftRepoStatus(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos *repository.Repositories) {
+		admin := createTestUser(t, repos, "admin", model.ROLE_ADMIN)
+		ann := createTestUser(t, repos, "ann", model.ROLE_WORKER)
+		shiftID := createTestShift(t, repos, amsterdamShift(createTestLocation(t, repos, "Store", ""), "2026-07-01", 9))
+
+		id, err := repos.WorkerShift.CreateWorkerShift(&model.WorkerShift{
+			ShiftID: shiftID, UserAccountID: ann, Status: model.WORKER_SHIFT_PENDING,
+		})
+		if err != nil {
+			t.Fatalf("CreateWorkerShift: %v", err)
+		}
+		ws, err := repos.WorkerShift.GetWorkerShiftByID(id)
+		if err != nil {
+			t.Fatalf("GetWorkerShiftByID: %v", err)
+		}
+		if ws.ShiftID != shiftID || ws.UserAccountID != ann || ws.Status != model.WORKER_SHIFT_PENDING ||
+			ws.ApprovedBy != nil || ws.WaitlistPosition != nil || ws.OfferExpiresAt != nil {
+			t.Errorf("created %+v", ws)
+		}
+
+		if err := repos.WorkerShift.UpdatesWorkerShiftStatus(id, model.WORKER_SHIFT_APPROVED, &admin); err != nil {
+			t.Fatalf("UpdatesWorkerShiftStatus: %v", err)
+		}
+		ws, err = repos.WorkerShift.GetWorkerShiftByIDForUpdate(id)
+		if err != nil {
+			t.Fatalf("GetWorkerShiftByIDForUpdate: %v", err)
+		}
+		if ws.Status != model.WORKER_SHIFT_APPROVED || ws.ApprovedBy == nil || *ws.ApprovedBy != admin {
+			t.Errorf("approved %+v", ws)
+		}
+
+		if _, err := repos.WorkerShift.GetWorkerShiftByID(id + 100); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("GetWorkerShiftByID of a missing request: err = %v, want sql.ErrNoRows", err)
+		}
+	})
+}
+
+func TestWorkerShiftRepoWaitlist(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos *repository.Repositories) {
+		ann := createTestUser(t, repos, "ann", model.ROLE_WORKER)
+		shiftID := createTestShift(t, repos, amsterdamShift(createTestLocation(t, repos, "Store", ""), "2026-07-01", 9))
+		id, err := repos.WorkerShift.CreateWorkerShift(&model.WorkerShift{
+			ShiftID: shiftID, UserAccountID: ann, Status: model.WORKER_SHIFT_PENDING,
+		})
+		if err != nil {
+			t.Fatalf("CreateWorkerShift: %v", err)
+		}
+
+		if err := repos.WorkerShift.WaitlistWorkerShift(id, 3); err != nil {
+			t.Fatalf("WaitlistWorkerShift: %v", err)
+		}
+		expires := time.Date(2026, 6, 30, 18, 30, 0, 0, time.UTC)
+		if err := repos.WorkerShift.OfferWorkerShift(id, expires); err != nil {
+			t.Fatalf("OfferWorkerShift: %v", err)
+		}
+		ws, err := repos.WorkerShift.GetWorkerShiftByID(id)
+		if err != nil {
+			t.Fatalf("GetWorkerShiftByID: %v", err)
+		}
+		if ws.Status != model.WORKER_SHIFT_OFFERED || ws.WaitlistPosition == nil || *ws.WaitlistPosition != 3 {
+			t.Errorf("offered %+v, want OFFERED at position 3", ws)
+		}
+		if ws.OfferExpiresAt == nil || !ws.OfferExpiresAt.Equal(expires) {
+			t.Errorf("offer expires at %v, want %v", ws.OfferExpiresAt, expires)
+		}
+
+		// A status change takes the request off the waitlist
+		if err := repos.WorkerShift.UpdatesWorkerShiftStatus(id, model.WORKER_SHIFT_APPROVED, nil); err != nil {
+			t.Fatalf("UpdatesWorkerShiftStatus: %v", err)
+		}
+		ws, err = repos.WorkerShift.GetWorkerShiftByID(id)
+		if err != nil {
+			t.Fatalf("GetWorkerShiftByID: %v", err)
+		}
+		if ws.WaitlistPosition != nil || ws.OfferExpiresAt != nil {
+			t.Errorf("approved request kept its waitlist place: %+v", ws)
+		}
+	})
+}
+
+func TestWorkerShiftRepoListAndCount(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos *repository.Repositories) {
+		ann := createTestUser(t, repos, "ann", model.ROLE_WORKER)
+		bob := createTestUser(t, repos, "bob", model.ROLE_WORKER)
+		locationID := createTestLocation(t, repos, "Store", "")
+		first := createTestShift(t, repos, amsterdamShift(locationID, "2026-07-01", 9))
+		second := createTestShift(t, repos, amsterdamShift(locationID, "2026-07-02", 9))
+
+		create := func(shiftID, userID int64, status string) int64 {
+			id, err := repos.WorkerShift.CreateWorkerShift(&model.WorkerShift{
+				ShiftID: shiftID, UserAccountID: userID, Status: status,
+			})
+			if err != nil {
+				t.Fatalf("CreateWorkerShift: %v", err)
+			}
+			return id
+		}
+		create(first, ann, model.WORKER_SHIFT_APPROVED)
+		create(first, bob, model.WORKER_SHIFT_APPROVED)
+		create(second, ann, model.WORKER_SHIFT_PENDING)
+
+		byShift, err := repos.WorkerShift.ListWorkerShiftsByShift(first)
+		if err != nil || len(byShift) != 2 {
+			t.Errorf("ListWorkerShiftsByShift = %d requests, %v, want 2", len(byShift), err)
+		}
+		byUser, err := repos.WorkerShift.ListWorkerShiftsByUser(ann)
+		if err != nil || len(byUser) != 2 {
+			t.Errorf("ListWorkerShiftsByUser = %d requests, %v, want 2", len(byUser), err)
+		}
+
+		counts, err := repos.WorkerShift.CountWorkerShiftsByShiftIDs([]int64{first, second}, model.WORKER_SHIFT_APPROVED)
+		if err != nil {
+			t.Fatalf("CountWorkerShiftsByShiftIDs: %v", err)
+		}
+		if len(counts) != 1 || counts[first] != 2 {
+			t.Errorf("approved counts = %v, want only shift %d with 2", counts, first)
+		}
+
+		status := model.WORKER_SHIFT_PENDING
+		pending, err := repos.WorkerShift.GetWorkerShiftListByFilter(&ann, &status)
+		if err != nil || len(pending) != 1 || pending[0].ShiftID != second {
+			t.Errorf("GetWorkerShiftListByFilter(ann, PENDING) = %v, %v", pending, err)
+		}
+
+		if err := repos.WorkerShift.DeleteWorkerShiftsByShift(first); err != nil {
+			t.Fatalf("DeleteWorkerShiftsByShift: %v", err)
+		}
+		if byShift, err := repos.WorkerShift.ListWorkerShiftsByShift(first); err != nil || len(byShift) != 0 {
+			t.Errorf("after delete ListWorkerShiftsByShift = %d requests, %v, want none", len(byShift), err)
+		}
+		if byUser, err := repos.WorkerShift.ListWorkerShiftsByUser(ann); err != nil || len(byUser) != 1 {
+			t.Errorf("after delete ListWorkerShiftsByUser = %d requests, %v, want 1", len(byUser), err)
+		}
+	})
+}
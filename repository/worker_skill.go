@@ -0,0 +1,103 @@
+package repository
+
+import (
+	model "dailyworkerroster/model"
+)
+
+type WorkerSkillRepoItf interface {
+	CreateWorkerSkill(workerSkill *model.WorkerSkill) (int64, error)
+	GetWorkerSkill(userAccountID, skillID int64) (*model.WorkerSkill, error)
+	ListWorkerSkills(userAccountID int64) ([]*model.WorkerSkill, error)
+	UpdateWorkerSkill(workerSkill *model.WorkerSkill) error
+	DeleteWorkerSkill(userAccountID, skillID int64) error
+	DeleteWorkerSkillsBySkill(skillID int64) error
+}
+
+const workerSkillQuery = `
+        SELECT ws.id, ws.user_account_id, ws.skill_id, s.code, s.name, ws.expires_on, ws.created_at, ws.updated_at
+        FROM worker_skill ws
+        JOIN skill s ON ws.skill_id = s.id
+    `
+
+func scanWorkerSkill(row rowScanner) (*model.WorkerSkill, error) {
+	var ws model.WorkerSkill
+	var expiresOn nullDateColumn
+	err := row.Scan(
+		&ws.ID, &ws.UserAccountID, &ws.SkillID, &ws.SkillCode, &ws.SkillName, &expiresOn,
+		&ws.CreatedAt, &ws.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if expiresOn.Valid {
+		ws.ExpiresOn = &expiresOn.String
+	}
+	return &ws, nil
+}
+
+type WorkerSkillRepository struct {
+	DB      DBTX
+	Dialect Dialect
+}
+
+func NewWorkerSkillRepository(db DBTX) WorkerSkillRepoItf {
+	return &WorkerSkillRepository{DB: db, Dialect: DialectMySQL}
+}
+
+func NewSQLiteWorkerSkillRepository(db DBTX) WorkerSkillRepoItf {
+	return &WorkerSkillRepository{DB: db, Dialect: DialectSQLite}
+}
+
+func (r *WorkerSkillRepository) CreateWorkerSkill(workerSkill *model.WorkerSkill) (int64, error) {
+	query := `
+        INSERT INTO worker_skill (user_account_id, skill_id, expires_on, created_at, updated_at)
+        VALUES (?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+    `
+	result, err := r.DB.Exec(query, workerSkill.UserAccountID, workerSkill.SkillID, workerSkill.ExpiresOn)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+func (r *WorkerSkillRepository) GetWorkerSkill(userAccountID, skillID int64) (*model.WorkerSkill, error) {
+	query := workerSkillQuery + ` WHERE ws.user_account_id = ? AND ws.skill_id = ?`
+	return scanWorkerSkill(r.DB.QueryRow(query, userAccountID, skillID))
+}
+
+func (r *WorkerSkillRepository) ListWorkerSkills(userAccountID int64) ([]*model.WorkerSkill, error) {
+	rows, err := r.DB.Query(workerSkillQuery+` WHERE ws.user_account_id = ? ORDER BY s.code`, userAccountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := make([]*model.WorkerSkill, 0)
+	for rows.Next() {
+		ws, err := scanWorkerSkill(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, ws)
+	}
+	return list, nil
+}
+
+func (r *WorkerSkillRepository) UpdateWorkerSkill(workerSkill *model.WorkerSkill) error {
+	query := `
+        UPDATE worker_skill SET expires_on = ?, updated_at = CURRENT_TIMESTAMP
+        WHERE id = ?
+    `
+	_, err := r.DB.Exec(query, workerSkill.ExpiresOn, workerSkill.ID)
+	return err
+}
+
+func (r *WorkerSkillRepository) DeleteWorkerSkill(userAccountID, skillID int64) error {
+	_, err := r.DB.Exec(`DELETE FROM worker_skill WHERE user_account_id = ? AND skill_id = ?`, userAccountID, skillID)
+	return err
+}
+
+func (r *WorkerSkillRepository) DeleteWorkerSkillsBySkill(skillID int64) error {
+	_, err := r.DB.Exec(`DELETE FROM worker_skill WHERE skill_id = ?`, skillID)
+	return err
+}
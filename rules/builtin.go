@@ -0,0 +1,367 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"dailyworkerroster/config"
+	"dailyworkerroster/model"
+)
+
+// interval is a shift with its parsed bounds. Shifts whose times cannot be
+// parsed are skipped by the rules; they are rejected when created.
+type interval struct {
+	shift      *model.Shift
+	start, end time.Time
+}
+
+func (i interval) hours() float64 {
+	return i.end.Sub(i.start).Hours()
+}
+
+// hoursOn returns the hours of the interval falling on the calendar day
+// starting at day.
+func (i interval) hoursOn(day time.Time) float64 {
+	from, until := i.start, i.end
+	if next := day.AddDate(0, 0, 1); until.After(next) {
+		until = next
+	}
+	if from.Before(day) {
+		from = day
+	}
+	if !until.After(from) {
+		return 0
+	}
+	return until.Sub(from).Hours()
+}
+
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// intervals returns the shifts' bounds in loc, the zone of the shift being
+// checked, so that days and weeks are those of its location.
+func intervals(shifts []*model.Shift, loc *time.Location) []interval {
+	list := make([]interval, 0, len(shifts))
+	for _, shift := range shifts {
+		start, end, err := Bounds(shift)
+		if err != nil {
+			continue
+		}
+		list = append(list, interval{shift: shift, start: start.In(loc), end: end.In(loc)})
+	}
+	return list
+}
+
+func candidate(in *Input) (interval, bool) {
+	start, end, err := Bounds(in.Shift)
+	if err != nil {
+		return interval{}, false
+	}
+	return interval{shift: in.Shift, start: start, end: end}, true
+}
+
+// skillRule requires the worker to hold the skill named by the shift's
+// role, valid on the day the shift starts.
+type skillRule struct{}
+
+func newSkillRule(config.RuleSet) Rule { return skillRule{} }
+
+func (skillRule) Check(in *Input) []Violation {
+	for _, skill := range in.Skills {
+		if skill.SkillCode != in.Shift.RoleAssignment {
+			continue
+		}
+		if skill.ExpiresOn != nil && *skill.ExpiresOn < in.Shift.Date {
+			return []Violation{{
+				Code:    CodeSkill,
+				Message: fmt.Sprintf("%s qualification expired on %s", skill.SkillCode, *skill.ExpiresOn),
+			}}
+		}
+		return nil
+	}
+	return []Violation{{
+		Code:    CodeSkill,
+		Message: fmt.Sprintf("not qualified as %s", in.Shift.RoleAssignment),
+	}}
+}
+
+// timeOffRule keeps workers from shifts during their approved leave.
+type timeOffRule struct{}
+
+func newTimeOffRule(config.RuleSet) Rule { return timeOffRule{} }
+
+func (timeOffRule) Check(in *Input) []Violation {
+	for _, timeOff := range in.TimeOff {
+		if DuringTimeOff(in.Shift, timeOff) {
+			return []Violation{{
+				Code:    CodeTimeOff,
+				Message: fmt.Sprintf("on %s leave from %s to %s", strings.ToLower(timeOff.Type), timeOff.StartDate, timeOff.EndDate),
+			}}
+		}
+	}
+	return nil
+}
+
+type overlapRule struct{}
+
+func newOverlapRule(config.RuleSet) Rule { return overlapRule{} }
+
+func (overlapRule) Check(in *Input) []Violation {
+	c, ok := candidate(in)
+	if !ok {
+		return nil
+	}
+	for _, other := range intervals(in.Assigned, c.start.Location()) {
+		if c.start.Before(other.end) && other.start.Before(c.end) {
+			return []Violation{{
+				Code:    CodeOverlap,
+				Message: fmt.Sprintf("overlaps shift %d on %s", other.shift.ID, other.shift.Date),
+			}}
+		}
+	}
+	return nil
+}
+
+type maxShiftsPerDayRule struct{ limit int }
+
+func newMaxShiftsPerDayRule(set config.RuleSet) Rule {
+	if set.MaxShiftsPerDay == nil || *set.MaxShiftsPerDay <= 0 {
+		return nil
+	}
+	return maxShiftsPerDayRule{limit: *set.MaxShiftsPerDay}
+}
+
+func (r maxShiftsPerDayRule) Check(in *Input) []Violation {
+	c, ok := candidate(in)
+	if !ok {
+		return nil
+	}
+	day := midnight(c.start)
+	count := 0
+	for _, other := range intervals(in.Assigned, c.start.Location()) {
+		if midnight(other.start).Equal(day) {
+			count++
+		}
+	}
+	if count >= r.limit {
+		return []Violation{{
+			Code:    CodeMaxShiftsPerDay,
+			Message: fmt.Sprintf("already has %d shift(s) on %s, the limit is %d", count, day.Format(dateLayout), r.limit),
+		}}
+	}
+	return nil
+}
+
+type maxShiftsPerWeekRule struct{ limit int }
+
+func newMaxShiftsPerWeekRule(set config.RuleSet) Rule {
+	if set.MaxShiftsPerWeek == nil || *set.MaxShiftsPerWeek <= 0 {
+		return nil
+	}
+	return maxShiftsPerWeekRule{limit: *set.MaxShiftsPerWeek}
+}
+
+func (r maxShiftsPerWeekRule) Check(in *Input) []Violation {
+	c, ok := candidate(in)
+	if !ok {
+		return nil
+	}
+	monday := WeekStart(c.start)
+	count := 0
+	for _, other := range intervals(in.Assigned, c.start.Location()) {
+		if WeekStart(other.start).Equal(monday) {
+			count++
+		}
+	}
+	if count >= r.limit {
+		return []Violation{{
+			Code:    CodeMaxShiftsPerWeek,
+			Message: fmt.Sprintf("already has %d shifts in the week of %s, the limit is %d", count, monday.Format(dateLayout), r.limit),
+		}}
+	}
+	return nil
+}
+
+type maxHoursPerDayRule struct{ limit float64 }
+
+func newMaxHoursPerDayRule(set config.RuleSet) Rule {
+	if set.MaxHoursPerDay == nil || *set.MaxHoursPerDay <= 0 {
+		return nil
+	}
+	return maxHoursPerDayRule{limit: *set.MaxHoursPerDay}
+}
+
+func (r maxHoursPerDayRule) Check(in *Input) []Violation {
+	c, ok := candidate(in)
+	if !ok {
+		return nil
+	}
+	assigned := intervals(in.Assigned, c.start.Location())
+	// Hours count towards the calendar day they are worked on, so a night
+	// shift is split over the two days it touches.
+	for day := midnight(c.start); day.Before(c.end); day = day.AddDate(0, 0, 1) {
+		hours := c.hoursOn(day)
+		for _, other := range assigned {
+			hours += other.hoursOn(day)
+		}
+		if hours > r.limit {
+			return []Violation{{
+				Code:    CodeMaxHoursPerDay,
+				Message: fmt.Sprintf("would work %.1f hours on %s, the limit is %.1f", hours, day.Format(dateLayout), r.limit),
+			}}
+		}
+	}
+	return nil
+}
+
+type maxHoursPerWeekRule struct{ limit float64 }
+
+func newMaxHoursPerWeekRule(set config.RuleSet) Rule {
+	if set.MaxHoursPerWeek == nil || *set.MaxHoursPerWeek <= 0 {
+		return nil
+	}
+	return maxHoursPerWeekRule{limit: *set.MaxHoursPerWeek}
+}
+
+func (r maxHoursPerWeekRule) Check(in *Input) []Violation {
+	c, ok := candidate(in)
+	if !ok {
+		return nil
+	}
+	monday := WeekStart(c.start)
+	hours := c.hours()
+	for _, other := range intervals(in.Assigned, c.start.Location()) {
+		if WeekStart(other.start).Equal(monday) {
+			hours += other.hours()
+		}
+	}
+	if hours > r.limit {
+		return []Violation{{
+			Code:    CodeMaxHoursPerWeek,
+			Message: fmt.Sprintf("would work %.1f hours in the week of %s, the limit is %.1f", hours, monday.Format(dateLayout), r.limit),
+		}}
+	}
+	return nil
+}
+
+type minRestRule struct{ rest time.Duration }
+
+func newMinRestRule(set config.RuleSet) Rule {
+	if set.MinRestHours == nil || *set.MinRestHours <= 0 {
+		return nil
+	}
+	return minRestRule{rest: time.Duration(*set.MinRestHours * float64(time.Hour))}
+}
+
+func (r minRestRule) Check(in *Input) []Violation {
+	c, ok := candidate(in)
+	if !ok {
+		return nil
+	}
+	for _, other := range intervals(in.Assigned, c.start.Location()) {
+		var gap time.Duration
+		switch {
+		case !other.start.Before(c.end):
+			gap = other.start.Sub(c.end)
+		case !c.start.Before(other.end):
+			gap = c.start.Sub(other.end)
+		default:
+			continue // overlapping, reported by the overlap rule
+		}
+		if gap < r.rest {
+			return []Violation{{
+				Code: CodeMinRest,
+				Message: fmt.Sprintf("only %s rest next to shift %d on %s, at least %s is required",
+					gap, other.shift.ID, other.shift.Date, r.rest),
+			}}
+		}
+	}
+	return nil
+}
+
+type maxConsecutiveDaysRule struct{ limit int }
+
+func newMaxConsecutiveDaysRule(set config.RuleSet) Rule {
+	if set.MaxConsecutiveDays == nil || *set.MaxConsecutiveDays <= 0 {
+		return nil
+	}
+	return maxConsecutiveDaysRule{limit: *set.MaxConsecutiveDays}
+}
+
+func (r maxConsecutiveDaysRule) Check(in *Input) []Violation {
+	c, ok := candidate(in)
+	if !ok {
+		return nil
+	}
+	worked := make(map[string]bool)
+	for _, other := range intervals(in.Assigned, c.start.Location()) {
+		worked[other.start.Format(dateLayout)] = true
+	}
+	day := midnight(c.start)
+
+	run := 1
+	for d := day.AddDate(0, 0, -1); worked[d.Format(dateLayout)]; d = d.AddDate(0, 0, -1) {
+		run++
+	}
+	for d := day.AddDate(0, 0, 1); worked[d.Format(dateLayout)]; d = d.AddDate(0, 0, 1) {
+		run++
+	}
+	if run > r.limit {
+		return []Violation{{
+			Code:    CodeMaxConsecutiveDays,
+			Message: fmt.Sprintf("would work %d consecutive days, the limit is %d", run, r.limit),
+		}}
+	}
+	return nil
+}
+
+type minorCurfewRule struct{ curfew config.CurfewRule }
+
+func newMinorCurfewRule(set config.RuleSet) Rule {
+	if set.MinorCurfew == nil {
+		return nil
+	}
+	return minorCurfewRule{curfew: *set.MinorCurfew}
+}
+
+func (r minorCurfewRule) Check(in *Input) []Violation {
+	if in.Worker == nil || in.Worker.DateOfBirth == nil {
+		return nil
+	}
+	c, ok := candidate(in)
+	if !ok {
+		return nil
+	}
+	birth, err := time.ParseInLocation(dateLayout, *in.Worker.DateOfBirth, c.start.Location())
+	if err != nil {
+		return nil
+	}
+	if !c.start.Before(birth.AddDate(r.curfew.MinAge, 0, 0)) {
+		return nil
+	}
+
+	from, errFrom := parseClock(r.curfew.From)
+	until, errUntil := parseClock(r.curfew.Until)
+	if errFrom != nil || errUntil != nil {
+		return nil
+	}
+	// Check the curfew windows starting the day before the shift through the
+	// day it ends, which covers shifts crossing midnight.
+	for day := c.start.AddDate(0, 0, -1); !day.After(c.end); day = day.AddDate(0, 0, 1) {
+		start := atClock(day, from)
+		end := atClock(day, until)
+		if !end.After(start) {
+			end = end.AddDate(0, 0, 1)
+		}
+		if c.start.Before(end) && start.Before(c.end) {
+			return []Violation{{
+				Code: CodeMinorCurfew,
+				Message: fmt.Sprintf("workers under %d may not work between %s and %s",
+					r.curfew.MinAge, r.curfew.From, r.curfew.Until),
+			}}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+// Package rules holds the labour rules a worker has to satisfy to be
+// assigned a shift. Rules are built per shift from the configured rule set
+// for its role and location, and every violated rule is reported.
+package rules
+
+import (
+	"dailyworkerroster/clock"
+	"errors"
+	"strings"
+	"time"
+
+	"dailyworkerroster/config"
+	errs "dailyworkerroster/error"
+	"dailyworkerroster/model"
+)
+
+const (
+	CodeSkill              = "SKILL"
+	CodeTimeOff            = "TIME_OFF"
+	CodeOverlap            = "OVERLAP"
+	CodeMaxShiftsPerDay    = "MAX_SHIFTS_PER_DAY"
+	CodeMaxShiftsPerWeek   = "MAX_SHIFTS_PER_WEEK"
+	CodeMaxHoursPerDay     = "MAX_HOURS_PER_DAY"
+	CodeMaxHoursPerWeek    = "MAX_HOURS_PER_WEEK"
+	CodeMinRest            = "MIN_REST"
+	CodeMaxConsecutiveDays = "MAX_CONSECUTIVE_DAYS"
+	CodeMinorCurfew        = "MINOR_CURFEW"
+)
+
+// Violation is one failed rule. Code is stable and meant for clients.
+type Violation struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Input is what a rule sees: the worker, their skills and approved leave,
+// the shift they would get, and the shifts already assigned to them around
+// it.
+type Input struct {
+	Worker   *model.User
+	Skills   []*model.WorkerSkill
+	TimeOff  []*model.TimeOff
+	Shift    *model.Shift
+	Assigned []*model.Shift
+}
+
+type Rule interface {
+	Check(in *Input) []Violation
+}
+
+// Factory builds a rule from a rule set, or returns nil when the set leaves
+// the rule disabled.
+type Factory func(set config.RuleSet) Rule
+
+// DefaultFactories are the built-in rules.
+var DefaultFactories = []Factory{
+	newSkillRule,
+	newTimeOffRule,
+	newOverlapRule,
+	newMaxShiftsPerDayRule,
+	newMaxShiftsPerWeekRule,
+	newMaxHoursPerDayRule,
+	newMaxHoursPerWeekRule,
+	newMinRestRule,
+	newMaxConsecutiveDaysRule,
+	newMinorCurfewRule,
+}
+
+// LookbackDays is how far around the shift the assigned shifts in Input
+// must reach for every built-in rule to see what it needs.
+const LookbackDays = 31
+
+type Engine struct {
+	Config    config.RulesConfig
+	Factories []Factory
+}
+
+func NewEngine(cfg config.RulesConfig, factories ...Factory) *Engine {
+	if len(factories) == 0 {
+		factories = DefaultFactories
+	}
+	return &Engine{Config: cfg, Factories: factories}
+}
+
+// Evaluate runs every rule that applies to in.Shift and returns all
+// violations, or nil when the worker may take the shift.
+func (e *Engine) Evaluate(in *Input) []Violation {
+	set := e.Config.For(in.Shift.RoleAssignment, in.Shift.Location)
+	var violations []Violation
+	for _, factory := range e.Factories {
+		rule := factory(set)
+		if rule == nil {
+			continue
+		}
+		violations = append(violations, rule.Check(in)...)
+	}
+	return violations
+}
+
+// ViolationError carries the violations of a rejected assignment. It
+// matches errs.ErrRuleViolation with errors.Is.
+type ViolationError struct {
+	Violations []Violation
+}
+
+func (e *ViolationError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = v.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e *ViolationError) Unwrap() error {
+	return errs.ErrRuleViolation
+}
+
+// AsViolations returns the violations carried by err, if any.
+func AsViolations(err error) ([]Violation, bool) {
+	var violationErr *ViolationError
+	if errors.As(err, &violationErr) {
+		return violationErr.Violations, true
+	}
+	return nil, false
+}
+
+// Bounds returns the start and end instants of a shift. Shifts not yet
+// carrying them are read in their time zone, UTC when it is not known
+// either, see BoundsIn.
+func Bounds(shift *model.Shift) (time.Time, time.Time, error) {
+	if !shift.StartAt.IsZero() && !shift.EndAt.IsZero() {
+		return shift.StartAt, shift.EndAt, nil
+	}
+	loc, err := clock.LoadLocation(shift.TimeZone, time.UTC)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return BoundsIn(shift, loc)
+}
+
+// BoundsIn reads the start and end of a shift from its date and clock times
+// as wall clock times in loc, so a shift keeps its clock times across DST
+// changes. An end time not after the start time means the shift ends the
+// next day.
+func BoundsIn(shift *model.Shift, loc *time.Location) (time.Time, time.Time, error) {
+	day, err := time.ParseInLocation(dateLayout, shift.Date, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	startClock, err := parseClock(shift.StartTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	endClock, err := parseClock(shift.EndTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	start := atClock(day, startClock)
+	end := atClock(day, endClock)
+	if !end.After(start) {
+		end = atClock(day.AddDate(0, 0, 1), endClock)
+	}
+	return start, end, nil
+}
+
+// Hours returns how long a shift lasts, derived from its start and end time.
+func Hours(shift *model.Shift) (float64, error) {
+	start, end, err := Bounds(shift)
+	if err != nil {
+		return 0, err
+	}
+	return end.Sub(start).Hours(), nil
+}
+
+// DuringTimeOff reports whether the shift falls on a day of the leave. A
+// night shift touches both days, counted in the zone of its location.
+func DuringTimeOff(shift *model.Shift, timeOff *model.TimeOff) bool {
+	start, end, err := Bounds(shift)
+	if err != nil {
+		return shift.Date >= timeOff.StartDate && shift.Date <= timeOff.EndDate
+	}
+	for day := midnight(start); day.Before(end); day = day.AddDate(0, 0, 1) {
+		date := day.Format(dateLayout)
+		if date >= timeOff.StartDate && date <= timeOff.EndDate {
+			return true
+		}
+	}
+	return false
+}
+
+// WeekStart returns midnight on the Monday of the week containing t.
+func WeekStart(t time.Time) time.Time {
+	weekday := int(t.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	return midnight(t).AddDate(0, 0, 1-weekday)
+}
+
+const dateLayout = "2006-01-02"
+
+func parseClock(value string) (time.Time, error) {
+	if t, err := time.Parse("15:04", value); err == nil {
+		return t, nil
+	}
+	return time.Parse("15:04:05", value)
+}
+
+func atClock(day, clock time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(),
+		clock.Hour(), clock.Minute(), clock.Second(), 0, day.Location())
+}
@@ -0,0 +1,256 @@
+package rules_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"dailyworkerroster/config"
+	errs "dailyworkerroster/error"
+	"dailyworkerroster/model"
+	"dailyworkerroster/rules"
+)
+
+func intPtr(v int) *int           { return &v }
+func floatPtr(v float64) *float64 { return &v }
+func hours(n int) time.Duration   { return time.Duration(n) * time.Hour }
+
+// at is the given hour on a day of October 2026; the 19th is a Monday.
+func at(day, hour int) time.Time {
+	return time.Date(2026, 10, day, hour, 0, 0, 0, time.UTC)
+}
+
+func codes(vs []rules.Violation) []string {
+	list := make([]string, len(vs))
+	for i, v := range vs {
+		list[i] = v.Code
+	}
+	return list
+}
+
+var nextShiftID int64
+
+// shift is a cleaner shift at the store from start for length.
+func shift(start time.Time, length time.Duration) *model.Shift {
+	nextShiftID++
+	end := start.Add(length)
+	return &model.Shift{
+		ID:             nextShiftID,
+		Date:           start.Format("2006-01-02"),
+		StartTime:      start.Format("15:04:05"),
+		EndTime:        end.Format("15:04:05"),
+		StartAt:        start,
+		EndAt:          end,
+		RoleAssignment: "CLEANER",
+		Location:       "Store",
+	}
+}
+
+func TestRulesConfigFor(t *testing.T) {
+	cfg := config.RulesConfig{
+		Default: config.RuleSet{MaxHoursPerDay: floatPtr(12), MaxHoursPerWeek: floatPtr(40)},
+		Roles: map[string]config.RuleSet{
+			"CLEANER": {MaxHoursPerDay: floatPtr(10), MinRestHours: floatPtr(11)},
+		},
+		Locations: map[string]config.RuleSet{
+			"Store": {MaxHoursPerDay: floatPtr(8)},
+		},
+	}
+	tests := []struct {
+		name          string
+		role, place   string
+		wantDay       float64
+		wantWeek      float64
+		wantRestIsSet bool
+	}{
+		{"default", "COOK", "Depot", 12, 40, false},
+		{"role overrides default", "CLEANER", "Depot", 10, 40, true},
+		{"location overrides role", "CLEANER", "Store", 8, 40, true},
+		{"location overrides default", "COOK", "Store", 8, 40, false},
+		{"location name ignores case", "COOK", "sToRe", 8, 40, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := cfg.For(tt.role, tt.place)
+			if *set.MaxHoursPerDay != tt.wantDay || *set.MaxHoursPerWeek != tt.wantWeek {
+				t.Errorf("caps %v/day %v/week, want %v and %v", *set.MaxHoursPerDay, *set.MaxHoursPerWeek, tt.wantDay, tt.wantWeek)
+			}
+			if (set.MinRestHours != nil) != tt.wantRestIsSet {
+				t.Errorf("min rest set = %v, want %v", set.MinRestHours != nil, tt.wantRestIsSet)
+			}
+		})
+	}
+}
+
+func TestEngineEvaluate(t *testing.T) {
+	cleaner := []*model.WorkerSkill{{SkillCode: "CLEANER"}}
+	tests := []struct {
+		name     string
+		set      config.RuleSet
+		shift    *model.Shift
+		assigned []*model.Shift
+		want     []string
+	}{
+		{
+			name:  "no rules set",
+			shift: shift(at(19, 8), hours(8)),
+			want:  nil,
+		},
+		{
+			name:     "overlap always applies",
+			shift:    shift(at(19, 8), hours(8)),
+			assigned: []*model.Shift{shift(at(19, 14), hours(4))},
+			want:     []string{rules.CodeOverlap},
+		},
+		{
+			name:     "daily hours within cap",
+			set:      config.RuleSet{MaxHoursPerDay: floatPtr(12)},
+			shift:    shift(at(19, 6), hours(6)),
+			assigned: []*model.Shift{shift(at(19, 14), hours(6))},
+			want:     nil,
+		},
+		{
+			name:     "daily hours over cap",
+			set:      config.RuleSet{MaxHoursPerDay: floatPtr(10)},
+			shift:    shift(at(19, 6), hours(6)),
+			assigned: []*model.Shift{shift(at(19, 14), hours(6))},
+			want:     []string{rules.CodeMaxHoursPerDay},
+		},
+		{
+			// 22:00-06:00 puts 2 hours on the 19th and 6 on the 20th
+			name:     "night shift counts towards the next day",
+			set:      config.RuleSet{MaxHoursPerDay: floatPtr(10)},
+			shift:    shift(at(19, 22), hours(8)),
+			assigned: []*model.Shift{shift(at(20, 12), hours(5))},
+			want:     []string{rules.CodeMaxHoursPerDay},
+		},
+		{
+			name:     "weekly hours over cap",
+			set:      config.RuleSet{MaxHoursPerWeek: floatPtr(20)},
+			shift:    shift(at(22, 8), hours(8)),
+			assigned: []*model.Shift{shift(at(19, 8), hours(8)), shift(at(20, 8), hours(8))},
+			want:     []string{rules.CodeMaxHoursPerWeek},
+		},
+		{
+			// The 19th is a Monday, the 18th the Sunday before
+			name:     "weekly hours start on Monday",
+			set:      config.RuleSet{MaxHoursPerWeek: floatPtr(20)},
+			shift:    shift(at(22, 8), hours(8)),
+			assigned: []*model.Shift{shift(at(18, 8), hours(8)), shift(at(20, 8), hours(8))},
+			want:     nil,
+		},
+		{
+			name:     "rest gap too short",
+			set:      config.RuleSet{MinRestHours: floatPtr(11)},
+			shift:    shift(at(20, 6), hours(8)),
+			assigned: []*model.Shift{shift(at(19, 14), hours(8))},
+			want:     []string{rules.CodeMinRest},
+		},
+		{
+			name:     "rest gap long enough",
+			set:      config.RuleSet{MinRestHours: floatPtr(11)},
+			shift:    shift(at(20, 9), hours(8)),
+			assigned: []*model.Shift{shift(at(19, 14), hours(8))},
+			want:     nil,
+		},
+		{
+			name:     "every violated rule is reported",
+			set:      config.RuleSet{MaxShiftsPerDay: intPtr(1), MaxHoursPerDay: floatPtr(8)},
+			shift:    shift(at(19, 14), hours(6)),
+			assigned: []*model.Shift{shift(at(19, 6), hours(6))},
+			want:     []string{rules.CodeMaxShiftsPerDay, rules.CodeMaxHoursPerDay},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := rules.NewEngine(config.RulesConfig{Default: tt.set})
+			got := codes(engine.Evaluate(&rules.Input{
+				Worker:   &model.User{ID: 1},
+				Skills:   cleaner,
+				Shift:    tt.shift,
+				Assigned: tt.assigned,
+			}))
+			if len(got) != len(tt.want) {
+				t.Fatalf("violations %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("violations %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestEngineAppliesOverrides(t *testing.T) {
+	engine := rules.NewEngine(config.RulesConfig{
+		Default:   config.RuleSet{MaxHoursPerDay: floatPtr(12)},
+		Roles:     map[string]config.RuleSet{"COOK": {MaxHoursPerDay: floatPtr(6)}},
+		Locations: map[string]config.RuleSet{"Depot": {MaxHoursPerDay: floatPtr(4)}},
+	})
+	skills := []*model.WorkerSkill{{SkillCode: "CLEANER"}, {SkillCode: "COOK"}}
+	tests := []struct {
+		name           string
+		role, location string
+		want           []string
+	}{
+		{"default allows 8 hours", "CLEANER", "Store", nil},
+		{"role cap", "COOK", "Store", []string{rules.CodeMaxHoursPerDay}},
+		{"location cap", "CLEANER", "Depot", []string{rules.CodeMaxHoursPerDay}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := shift(at(19, 8), hours(8))
+			s.RoleAssignment, s.Location = tt.role, tt.location
+			got := codes(engine.Evaluate(&rules.Input{Worker: &model.User{ID: 1}, Skills: skills, Shift: s}))
+			if len(got) != len(tt.want) || (len(got) > 0 && got[0] != tt.want[0]) {
+				t.Errorf("violations %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSkillRule(t *testing.T) {
+	engine := rules.NewEngine(config.RulesConfig{})
+	expired := "2026-10-18"
+	tests := []struct {
+		name   string
+		skills []*model.WorkerSkill
+		want   []string
+	}{
+		{"qualified", []*model.WorkerSkill{{SkillCode: "CLEANER"}}, nil},
+		{"other skill", []*model.WorkerSkill{{SkillCode: "COOK"}}, []string{rules.CodeSkill}},
+		{"expired before the shift", []*model.WorkerSkill{{SkillCode: "CLEANER", ExpiresOn: &expired}}, []string{rules.CodeSkill}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := codes(engine.Evaluate(&rules.Input{Worker: &model.User{ID: 1}, Skills: tt.skills, Shift: shift(at(19, 8), hours(8))}))
+			if len(got) != len(tt.want) || (len(got) > 0 && got[0] != tt.want[0]) {
+				t.Errorf("violations %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestViolationError(t *testing.T) {
+	violations := []rules.Violation{
+		{Code: rules.CodeOverlap, Message: "overlaps shift 1"},
+		{Code: rules.CodeMinRest, Message: "too little rest"},
+	}
+	var err error = &rules.ViolationError{Violations: violations}
+
+	if !errors.Is(err, errs.ErrRuleViolation) {
+		t.Error("ViolationError does not match ErrRuleViolation")
+	}
+	if got, want := err.Error(), "overlaps shift 1; too little rest"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	got, ok := rules.AsViolations(fmt.Errorf("request 1: %w", err))
+	if !ok || len(got) != 2 || got[1].Code != rules.CodeMinRest {
+		t.Errorf("AsViolations = %v, %v; want the two violations", got, ok)
+	}
+	if _, ok := rules.AsViolations(errs.ErrShiftNotFound); ok {
+		t.Error("AsViolations found violations in an unrelated error")
+	}
+}
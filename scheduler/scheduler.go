@@ -0,0 +1,101 @@
+// Package scheduler runs periodic background jobs inside the server process.
+// Every run first takes a lease in the job_lock table, so with several
+// replicas only one of them runs a given job per interval.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"dailyworkerroster/clock"
+	"dailyworkerroster/repository"
+)
+
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+type Scheduler struct {
+	Locks repository.JobLockRepoItf
+	Clock clock.Clock
+	Owner string // identifies this replica in job_lock
+	Jobs  []Job
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewScheduler(locks repository.JobLockRepoItf, clk clock.Clock, jobs ...Job) *Scheduler {
+	return &Scheduler{
+		Locks: locks,
+		Clock: clk,
+		Owner: replicaID(),
+		Jobs:  jobs,
+	}
+}
+
+// Start runs every job once right away and then on its interval until Stop
+// is called or ctx is done.
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+	for _, job := range s.Jobs {
+		job := job
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			ticker := time.NewTicker(job.Interval)
+			defer ticker.Stop()
+			for {
+				s.RunOnce(ctx, job)
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}()
+	}
+}
+
+// Stop stops the jobs and waits for running ones to return.
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+// RunOnce runs job if this replica can take its lease. The lease is held
+// for the whole interval, so the job runs at most once per interval across
+// replicas, and another replica takes over once a holder stops renewing.
+func (s *Scheduler) RunOnce(ctx context.Context, job Job) {
+	funcName := "/scheduler/RunOnce"
+
+	now := s.Clock.Now()
+	acquired, err := s.Locks.AcquireJobLock(job.Name, s.Owner, now, now.Add(job.Interval))
+	if err != nil {
+		log.Printf("%s: AcquireJobLock error for %s: %v", funcName, job.Name, err)
+		return
+	}
+	if !acquired {
+		return
+	}
+	if err := job.Run(ctx); err != nil {
+		log.Printf("%s: job %s error: %v", funcName, job.Name, err)
+	}
+}
+
+func replicaID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%d-%d", host, os.Getpid(), rand.Int63())
+}
@@ -0,0 +1,76 @@
+package scheduler
+
+import (
+	"context"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"dailyworkerroster/clock"
+	"dailyworkerroster/config"
+	"dailyworkerroster/migration"
+	"dailyworkerroster/repository"
+)
+
+func newTestLocks(t *testing.T) repository.JobLockRepoItf {
+	t.Helper()
+
+	// Migrations log every step they apply
+	out := log.Writer()
+	log.SetOutput(io.Discard)
+	t.Cleanup(func() { log.SetOutput(out) })
+
+	db, dialect, err := repository.Open(config.DatabaseConfig{DSN: "sqlite://:memory:"})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	migrator, err := migration.NewMigrator(db, dialect, time.UTC)
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	return repository.NewRepositories(db, dialect, time.UTC).JobLock
+}
+
+func TestRunOnceLeaseKeepsOtherOwnersOut(t *testing.T) {
+	locks := newTestLocks(t)
+	clk := clock.NewFake(time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC))
+
+	runs := make(map[string]int)
+	job := func(owner string) Job {
+		return Job{Name: "shift_lifecycle", Interval: time.Minute, Run: func(ctx context.Context) error {
+			runs[owner]++
+			return nil
+		}}
+	}
+	first := NewScheduler(locks, clk)
+	first.Owner = "first"
+	second := NewScheduler(locks, clk)
+	second.Owner = "second"
+
+	first.RunOnce(context.Background(), job("first"))
+	second.RunOnce(context.Background(), job("second"))
+	if runs["first"] != 1 || runs["second"] != 0 {
+		t.Fatalf("while first holds the lease: runs %v, want only first", runs)
+	}
+
+	// The holder renews its own lease before it runs out
+	clk.Advance(30 * time.Second)
+	first.RunOnce(context.Background(), job("first"))
+	second.RunOnce(context.Background(), job("second"))
+	if runs["first"] != 2 || runs["second"] != 0 {
+		t.Fatalf("after renewal: runs %v, want first twice", runs)
+	}
+
+	// Once first stops renewing, second takes over
+	clk.Advance(time.Minute)
+	second.RunOnce(context.Background(), job("second"))
+	first.RunOnce(context.Background(), job("first"))
+	if runs["first"] != 2 || runs["second"] != 1 {
+		t.Errorf("after the lease ran out: runs %v, want second to take over", runs)
+	}
+}
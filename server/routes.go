@@ -10,29 +10,142 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func SetupRoutes(router *gin.Engine, shiftHandler *handler.ShiftHandler, userHandler *handler.UserHandler) {
+func SetupRoutes(
+	router *gin.Engine,
+	authMiddleware gin.HandlerFunc,
+	shiftHandler *handler.ShiftHandler,
+	userHandler *handler.UserHandler,
+	shiftTemplateHandler *handler.ShiftTemplateHandler,
+	shiftTransferHandler *handler.ShiftTransferHandler,
+	locationHandler *handler.LocationHandler,
+	skillHandler *handler.SkillHandler,
+	availabilityHandler *handler.AvailabilityHandler,
+	timeOffHandler *handler.TimeOffHandler,
+	rosterHandler *handler.RosterHandler,
+	fairnessHandler *handler.FairnessHandler,
+	attendanceHandler *handler.AttendanceHandler,
+) {
 	// Swagger endpoint
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
 
 	router.POST("/signup", userHandler.SignUp)
 	router.POST("/login", userHandler.Login)
+	router.POST("/token/refresh", userHandler.RefreshToken)
 	userGroup := router.Group("/")
-	userGroup.Use(middleware.AuthMiddleware())
+	userGroup.Use(authMiddleware)
 	{
-		userGroup.GET("/workers", userHandler.GetAllWorkers)
-		userGroup.GET("/worker/:id", userHandler.GetWorkerByID)
+		// Worker scoped routes are limited to the worker themself, or an admin
+		owner := middleware.WorkerOwnership("workerID")
+
+		userGroup.POST("/logout", userHandler.Logout)
+		userGroup.GET("/workers", middleware.AdminMiddleware(), userHandler.GetAllWorkers)
+		userGroup.GET("/worker/:workerID", owner, userHandler.GetWorkerByID)
 		userGroup.GET("/worker/assigned", shiftHandler.GetAssignedShifts)
-		userGroup.GET("/worker/available/:workerID", shiftHandler.GetAvailableShifts)
-		userGroup.POST("/shift/:shiftID/request/:workerID", shiftHandler.RequestShift)
-		userGroup.GET("/worker/requests/:workerID", shiftHandler.GetAllRequestedShifts)
+		userGroup.GET("/worker/available/:workerID", owner, shiftHandler.GetAvailableShifts)
+		userGroup.POST("/shift/:shiftID/request/:workerID", owner, shiftHandler.RequestShift)
+		userGroup.POST("/shift/:shiftID/withdraw/:workerID", owner, shiftHandler.WithdrawShiftRequest)
+		userGroup.POST("/shift/:shiftID/cancel/:workerID", owner, shiftHandler.CancelShift)
+		userGroup.POST("/shift/:shiftID/accept-offer/:workerID", owner, shiftHandler.AcceptOffer)
+		userGroup.POST("/shift/:shiftID/decline-offer/:workerID", owner, shiftHandler.DeclineOffer)
+		userGroup.POST("/shift/:shiftID/clock-in/:workerID", owner, attendanceHandler.ClockIn)
+		userGroup.POST("/shift/:shiftID/clock-out/:workerID", owner, attendanceHandler.ClockOut)
+		userGroup.POST("/shift/:shiftID/break-start/:workerID", owner, attendanceHandler.StartBreak)
+		userGroup.POST("/shift/:shiftID/break-end/:workerID", owner, attendanceHandler.EndBreak)
+		userGroup.GET("/worker/requests/:workerID", owner, shiftHandler.GetAllRequestedShifts)
+		userGroup.GET("/worker/hours/:workerID", owner, shiftHandler.GetWorkerHours)
+		userGroup.GET("/worker/attendance/:workerID", owner, attendanceHandler.GetWorkerAttendance)
+		userGroup.GET("/worker/skills/:workerID", owner, skillHandler.GetWorkerSkills)
+		userGroup.GET("/worker/availability/:workerID", owner, availabilityHandler.GetAvailability)
+		userGroup.PUT("/worker/availability/:workerID", owner, availabilityHandler.SetAvailability)
+		userGroup.POST("/worker/unavailable/:workerID", owner, availabilityHandler.MarkUnavailable)
+		userGroup.DELETE("/worker/unavailable/:workerID/:date", owner, availabilityHandler.ClearUnavailable)
+		userGroup.POST("/worker/time-off/:workerID", owner, timeOffHandler.RequestTimeOff)
+		userGroup.GET("/worker/time-off/:workerID", owner, timeOffHandler.GetWorkerTimeOff)
+		userGroup.POST("/worker-shift/:workerShiftID/offer/:workerID", owner, shiftTransferHandler.OfferShift)
+		userGroup.GET("/worker/transfers/:workerID", owner, shiftTransferHandler.GetWorkerTransfers)
+		userGroup.POST("/transfer/:transferID/accept/:workerID", owner, shiftTransferHandler.AcceptTransfer)
+		userGroup.POST("/transfer/:transferID/cancel/:workerID", owner, shiftTransferHandler.CancelTransfer)
+	}
+
+	// Same as the worker scoped routes above, for the authenticated user
+	meGroup := router.Group("/me")
+	meGroup.Use(authMiddleware, middleware.Self("workerID"))
+	{
+		meGroup.GET("/assigned", shiftHandler.GetAssignedShifts)
+		meGroup.GET("/available", shiftHandler.GetAvailableShifts)
+		meGroup.POST("/shift/:shiftID/request", shiftHandler.RequestShift)
+		meGroup.POST("/shift/:shiftID/withdraw", shiftHandler.WithdrawShiftRequest)
+		meGroup.POST("/shift/:shiftID/cancel", shiftHandler.CancelShift)
+		meGroup.POST("/shift/:shiftID/accept-offer", shiftHandler.AcceptOffer)
+		meGroup.POST("/shift/:shiftID/decline-offer", shiftHandler.DeclineOffer)
+		meGroup.POST("/shift/:shiftID/clock-in", attendanceHandler.ClockIn)
+		meGroup.POST("/shift/:shiftID/clock-out", attendanceHandler.ClockOut)
+		meGroup.POST("/shift/:shiftID/break-start", attendanceHandler.StartBreak)
+		meGroup.POST("/shift/:shiftID/break-end", attendanceHandler.EndBreak)
+		meGroup.GET("/requests", shiftHandler.GetAllRequestedShifts)
+		meGroup.GET("/hours", shiftHandler.GetWorkerHours)
+		meGroup.GET("/attendance", attendanceHandler.GetWorkerAttendance)
+		meGroup.GET("/skills", skillHandler.GetWorkerSkills)
+		meGroup.GET("/availability", availabilityHandler.GetAvailability)
+		meGroup.PUT("/availability", availabilityHandler.SetAvailability)
+		meGroup.POST("/unavailable", availabilityHandler.MarkUnavailable)
+		meGroup.DELETE("/unavailable/:date", availabilityHandler.ClearUnavailable)
+		meGroup.POST("/time-off", timeOffHandler.RequestTimeOff)
+		meGroup.GET("/time-off", timeOffHandler.GetWorkerTimeOff)
+		meGroup.POST("/worker-shift/:workerShiftID/offer", shiftTransferHandler.OfferShift)
+		meGroup.GET("/transfers", shiftTransferHandler.GetWorkerTransfers)
+		meGroup.POST("/transfer/:transferID/accept", shiftTransferHandler.AcceptTransfer)
+		meGroup.POST("/transfer/:transferID/cancel", shiftTransferHandler.CancelTransfer)
 	}
 
 	adminGroup := router.Group("/admin")
-	adminGroup.Use(middleware.AuthMiddleware(), middleware.AdminMiddleware())
+	adminGroup.Use(authMiddleware, middleware.AdminMiddleware())
 	{
+		adminGroup.POST("/user/:userID/revoke-sessions", userHandler.RevokeUserSessions)
+		adminGroup.PUT("/user/:userID/skills/:skillID", skillHandler.SetWorkerSkill)
+		adminGroup.DELETE("/user/:userID/skills/:skillID", skillHandler.RemoveWorkerSkill)
+
 		adminGroup.POST("/shift", shiftHandler.CreateShift)
+		adminGroup.PUT("/shift/:shiftID", shiftHandler.UpdateShift)
+		adminGroup.DELETE("/shift/:shiftID", shiftHandler.DeleteShift)
+		adminGroup.GET("/requests", shiftHandler.GetAllShiftRequests)
 		adminGroup.PUT("/shift/:shiftID/approve/:workerID", shiftHandler.ApproveShiftRequest)
 		adminGroup.PUT("/shift/:shiftID/reject/:workerID", shiftHandler.RejectShiftRequest)
+		adminGroup.GET("/shift/:shiftID/applicants", fairnessHandler.RankApplicants)
+		adminGroup.PUT("/shift/:shiftID/approve-top", fairnessHandler.ApproveTopApplicants)
+		adminGroup.PUT("/worker-shift/:workerShiftID/no-show", shiftHandler.MarkNoShow)
+		adminGroup.GET("/worker-shift/:workerShiftID/time-entry", attendanceHandler.GetTimeEntry)
+		adminGroup.PUT("/worker-shift/:workerShiftID/time-entry", attendanceHandler.CorrectTimeEntry)
+		adminGroup.GET("/attendance", attendanceHandler.GetAttendanceStats)
 		adminGroup.GET("/shifts/day", shiftHandler.GetShiftsByDay)
+		adminGroup.GET("/roster/proposal", rosterHandler.ProposeRoster)
+		adminGroup.POST("/roster/commit", rosterHandler.CommitRoster)
+
+		adminGroup.GET("/time-off", timeOffHandler.GetTimeOffRequests)
+		adminGroup.PUT("/time-off/:timeOffID/approve", timeOffHandler.ApproveTimeOff)
+		adminGroup.PUT("/time-off/:timeOffID/reject", timeOffHandler.RejectTimeOff)
+
+		adminGroup.POST("/locations", locationHandler.CreateLocation)
+		adminGroup.GET("/locations", locationHandler.GetLocations)
+		adminGroup.GET("/locations/:locationID", locationHandler.GetLocationByID)
+		adminGroup.PUT("/locations/:locationID", locationHandler.UpdateLocation)
+		adminGroup.DELETE("/locations/:locationID", locationHandler.DeleteLocation)
+
+		adminGroup.POST("/skills", skillHandler.CreateSkill)
+		adminGroup.GET("/skills", skillHandler.GetSkills)
+		adminGroup.GET("/skills/:skillID", skillHandler.GetSkillByID)
+		adminGroup.PUT("/skills/:skillID", skillHandler.UpdateSkill)
+		adminGroup.DELETE("/skills/:skillID", skillHandler.DeleteSkill)
+
+		adminGroup.POST("/shift-templates", shiftTemplateHandler.CreateTemplate)
+		adminGroup.GET("/shift-templates", shiftTemplateHandler.GetTemplates)
+		adminGroup.GET("/shift-templates/:templateID", shiftTemplateHandler.GetTemplateByID)
+		adminGroup.PUT("/shift-templates/:templateID", shiftTemplateHandler.UpdateTemplate)
+		adminGroup.POST("/shift-templates/:templateID/generate", shiftTemplateHandler.GenerateShifts)
+
+		adminGroup.GET("/transfers", shiftTransferHandler.GetTransfers)
+		adminGroup.PUT("/transfer/:transferID/approve", shiftTransferHandler.ApproveTransfer)
+		adminGroup.PUT("/transfer/:transferID/reject", shiftTransferHandler.RejectTransfer)
+		adminGroup.GET("/worker-shift/:workerShiftID/history", shiftTransferHandler.GetWorkerShiftHistory)
 	}
 }
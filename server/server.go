@@ -1,56 +1,147 @@
 package server
 
 import (
-	"database/sql"
+	"context"
 	"log"
-	"os"
+	"strings"
+	"time"
 
+	"dailyworkerroster/clock"
+	"dailyworkerroster/config"
 	handler "dailyworkerroster/handlers"
+	"dailyworkerroster/middleware"
+	"dailyworkerroster/migration"
+	"dailyworkerroster/model"
 	"dailyworkerroster/repository"
+	"dailyworkerroster/rules"
+	"dailyworkerroster/scheduler"
 	"dailyworkerroster/service"
 
 	_ "dailyworkerroster/docs"
 
 	"github.com/gin-gonic/gin"
-	_ "github.com/go-sql-driver/mysql"
 )
 
-func NewServer() {
-	// Example: Get DB connection string from environment variable
-	dsn := os.Getenv("DATABASE_DSN")
-	if dsn == "" {
-		dsn = "root:password@tcp(127.0.0.1:3306)/dailyworkerroster?parseTime=true"
+// NewServer wires the application from cfg and serves it on cfg.Port
+func NewServer(cfg *config.Config) {
+	// Shifts at locations without a time zone, and the worker dashboards,
+	// use the configured zone
+	loc, err := clock.LoadLocation(cfg.TimeZone, time.UTC)
+	if err != nil {
+		log.Fatalf("failed to load time zone: %v", err)
 	}
 
-	db, err := sql.Open("mysql", dsn)
+	db, dialect, err := repository.Open(cfg.Database)
 	if err != nil {
 		log.Fatalf("failed to connect to DB: %v", err)
 	}
 
-	userRepo := &repository.UserRepository{DB: db}
-	shiftRepo := &repository.ShiftRepository{DB: db}
-	workerShiftRepo := &repository.WorkerShiftRepository{DB: db}
+	if cfg.Database.AutoMigrate {
+		migrator, err := migration.NewMigrator(db, dialect, loc)
+		if err != nil {
+			log.Fatalf("failed to load migrations: %v", err)
+		}
+		if _, err := migrator.Up(context.Background()); err != nil {
+			log.Fatalf("failed to migrate DB: %v", err)
+		}
+	}
+
+	repos := repository.NewRepositories(db, dialect, loc)
+	unitOfWork := repository.NewUnitOfWork(db, dialect, loc)
 
-	userService := service.NewUserService(userRepo)
-	shiftService := &service.ShiftService{
-		ShiftRepo:       shiftRepo,
-		WorkerShiftRepo: workerShiftRepo,
+	labourRules := rules.NewEngine(cfg.Rules)
+	checkRuleLocations(repos.Location, cfg.Rules)
+	clk := clock.New()
+
+	userService := service.NewUserService(repos.User, repos.UserSession, unitOfWork, cfg.Auth)
+	shiftService := service.NewShiftService(repos.Shift, repos.WorkerShift, repos.Location, repos.Skill, repos.Availability, unitOfWork, cfg.Shift, labourRules, loc, clk)
+	shiftTemplateService := service.NewShiftTemplateService(repos.ShiftTemplate, repos.Location, repos.Skill, unitOfWork, cfg.Shift, labourRules, loc, clk)
+	skillService := service.NewSkillService(repos.Skill, repos.WorkerSkill, unitOfWork)
+	availabilityService := service.NewAvailabilityService(repos.Availability, unitOfWork, clk)
+	timeOffService := service.NewTimeOffService(repos.TimeOff, unitOfWork)
+	rosterService := service.NewRosterService(unitOfWork, labourRules, clk)
+	fairnessService := service.NewFairnessService(repos.Shift, unitOfWork, cfg.Fairness, labourRules, clk)
+	attendanceService := service.NewAttendanceService(unitOfWork, cfg.Attendance, clk)
+	locationService := service.NewLocationService(repos.Location, unitOfWork, loc, clk)
+	shiftTransferService := service.NewShiftTransferService(repos.ShiftTransfer, repos.WorkerShift, unitOfWork, labourRules, clk)
+
+	if interval := cfg.Scheduler.Interval.Std(); interval > 0 {
+		lifecycleService := service.NewShiftLifecycleService(repos.Shift, repos.WorkerShift, unitOfWork, cfg.Shift, cfg.Attendance, labourRules, clk)
+		jobList := []scheduler.Job{{
+			Name:     "shift_lifecycle",
+			Interval: interval,
+			Run: func(ctx context.Context) error {
+				result, err := lifecycleService.Advance(ctx)
+				if err == nil && *result != (model.ShiftLifecycleResult{}) {
+					log.Printf("shift lifecycle: %d done, %d no-shows, %d expired, %d offers expired, %d offered, %d closed, %d failed",
+						result.Done, result.NoShows, result.Expired, result.OffersExpired, result.Offered, result.Closed, result.Failed)
+				}
+				return err
+			},
+		}}
+		if cfg.Fairness.AutoApproveBefore > 0 {
+			jobList = append(jobList, scheduler.Job{
+				Name:     "shift_auto_approve",
+				Interval: interval,
+				Run: func(ctx context.Context) error {
+					result, err := fairnessService.AutoApprove(ctx)
+					if err == nil && (result.Approved > 0 || result.Failed > 0) {
+						log.Printf("shift auto-approve: %d requests approved on %d shifts, %d shifts failed", result.Approved, result.Shifts, result.Failed)
+					}
+					return err
+				},
+			})
+		}
+		jobs := scheduler.NewScheduler(repos.JobLock, clk, jobList...)
+		jobs.Start(context.Background())
 	}
 
 	userHandler := handler.NewUserHandler(userService)
 	shiftHandler := handler.NewShiftHandler(shiftService)
+	shiftTemplateHandler := handler.NewShiftTemplateHandler(shiftTemplateService)
+	shiftTransferHandler := handler.NewShiftTransferHandler(shiftTransferService)
+	locationHandler := handler.NewLocationHandler(locationService)
+	skillHandler := handler.NewSkillHandler(skillService)
+	availabilityHandler := handler.NewAvailabilityHandler(availabilityService)
+	timeOffHandler := handler.NewTimeOffHandler(timeOffService)
+	rosterHandler := handler.NewRosterHandler(rosterService)
+	fairnessHandler := handler.NewFairnessHandler(fairnessService)
+	attendanceHandler := handler.NewAttendanceHandler(attendanceService)
 
 	router := gin.Default()
 
-	SetupRoutes(router, shiftHandler, userHandler)
+	authMiddleware := middleware.AuthMiddleware([]byte(cfg.Auth.JWTSecret), repos.UserSession)
+	SetupRoutes(router, authMiddleware, shiftHandler, userHandler, shiftTemplateHandler, shiftTransferHandler, locationHandler, skillHandler, availabilityHandler, timeOffHandler, rosterHandler, fairnessHandler, attendanceHandler)
 
 	// Start server
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
-	}
-	log.Printf("Server running at http://localhost:%s", port)
-	if err := router.Run(":" + port); err != nil {
+	log.Printf("Server running at http://localhost:%s (%s)", cfg.Port, cfg.Env)
+	if err := router.Run(":" + cfg.Port); err != nil {
 		log.Fatalf("failed to start server: %v", err)
 	}
 }
+
+// checkRuleLocations warns about location rules that match no location.
+// Rules are keyed by location name, so a typo or a renamed location leaves
+// them silently unused.
+func checkRuleLocations(locations repository.LocationRepoItf, cfg config.RulesConfig) {
+	if len(cfg.Locations) == 0 {
+		return
+	}
+	list, err := locations.ListLocations(nil)
+	if err != nil {
+		log.Printf("failed to check location rules: %v", err)
+		return
+	}
+	for name := range cfg.Locations {
+		found := false
+		for _, location := range list {
+			if strings.EqualFold(location.Name, name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			log.Printf("warning: rules.locations.%s matches no location and is not applied", name)
+		}
+	}
+}
@@ -0,0 +1,627 @@
+package service
+
+import (
+	"context"
+	"dailyworkerroster/auth"
+	"dailyworkerroster/clock"
+	"dailyworkerroster/config"
+	errs "dailyworkerroster/error"
+	"dailyworkerroster/model"
+	"dailyworkerroster/repository"
+	"dailyworkerroster/rules"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxAttendanceDays bounds the range of the attendance statistics.
+const maxAttendanceDays = 366
+
+type AttendanceServiceItf interface {
+	// // Worker
+	ClockIn(ctx context.Context, shiftID, workerID int64) (*model.TimeEntry, error)
+	ClockOut(ctx context.Context, shiftID, workerID int64) (*model.TimeEntry, error)
+	StartBreak(ctx context.Context, shiftID, workerID int64) (*model.TimeEntry, error)
+	EndBreak(ctx context.Context, shiftID, workerID int64) (*model.TimeEntry, error)
+
+	// // Admin
+	GetTimeEntry(ctx context.Context, workerShiftID int64) (*model.TimeEntry, error)
+	CorrectTimeEntry(ctx context.Context, workerShiftID int64, correction *model.TimeEntryCorrection) (*model.TimeEntry, error)
+	GetAttendanceStats(ctx context.Context, query model.AttendanceQuery) (*model.AttendanceStats, error)
+}
+
+type AttendanceService struct {
+	UnitOfWork repository.UnitOfWorkItf
+	Config     config.AttendanceConfig
+	Clock      clock.Clock
+}
+
+func NewAttendanceService(
+	unitOfWork repository.UnitOfWorkItf,
+	cfg config.AttendanceConfig,
+	clk clock.Clock) AttendanceServiceItf {
+	return &AttendanceService{
+		UnitOfWork: unitOfWork,
+		Config:     cfg,
+		Clock:      clk,
+	}
+}
+
+// ClockIn starts the time entry of the worker's approved shift, from
+// Config.ClockInEarly before its start until its end. Clocking in more than
+// Config.LateTolerance after the start counts as late.
+func (s *AttendanceService) ClockIn(ctx context.Context, shiftID, workerID int64) (*model.TimeEntry, error) {
+	funcName := "/service/attendance/ClockIn"
+
+	now := s.Clock.Now()
+	var entry *model.TimeEntry
+	err := s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		assignment, shift, err := lockWorkerShiftOnShift(repos, shiftID, workerID, model.WORKER_SHIFT_APPROVED)
+		if err != nil {
+			log.Printf("%s: lockWorkerShiftOnShift error: %v", funcName, err)
+			return err
+		}
+		if assignment == nil {
+			return errs.ErrNoApprovedShift
+		}
+
+		_, err = repos.TimeEntry.GetTimeEntryByWorkerShiftIDForUpdate(assignment.ID)
+		if err == nil {
+			return errs.ErrAlreadyClockedIn
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("%s: GetTimeEntryByWorkerShiftIDForUpdate error: %v", funcName, err)
+			return err
+		}
+
+		start, end, err := rules.Bounds(shift)
+		if err != nil {
+			return err
+		}
+		if now.Before(start.Add(-s.Config.ClockInEarly.Std())) || !now.Before(end) {
+			return errs.ErrClockInNotOpen
+		}
+
+		_, err = repos.TimeEntry.CreateTimeEntry(&model.TimeEntry{WorkerShiftID: assignment.ID, ClockInAt: now})
+		if err != nil {
+			log.Printf("%s: CreateTimeEntry error: %v", funcName, err)
+			return err
+		}
+		entry, err = loadTimeEntry(repos, s.Config, assignment.ID, shift, now)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// ClockOut ends the time entry of the worker's approved shift, together with
+// a break still in progress, and marks the shift DONE. Clocking out more
+// than Config.EarlyLeaveTolerance before the end counts as leaving early.
+func (s *AttendanceService) ClockOut(ctx context.Context, shiftID, workerID int64) (*model.TimeEntry, error) {
+	funcName := "/service/attendance/ClockOut"
+
+	now := s.Clock.Now()
+	var entry *model.TimeEntry
+	err := s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		assignment, shift, current, err := lockOpenTimeEntry(repos, shiftID, workerID)
+		if err != nil {
+			log.Printf("%s: lockOpenTimeEntry error: %v", funcName, err)
+			return err
+		}
+
+		if err := clockOut(repos, current, now); err != nil {
+			log.Printf("%s: clockOut error: %v", funcName, err)
+			return err
+		}
+		err = repos.WorkerShift.UpdatesWorkerShiftStatus(assignment.ID, model.WORKER_SHIFT_DONE, assignment.ApprovedBy)
+		if err != nil {
+			log.Printf("%s: UpdatesWorkerShiftStatus error: %v", funcName, err)
+			return err
+		}
+		entry, err = loadTimeEntry(repos, s.Config, assignment.ID, shift, now)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// StartBreak starts a break while the worker is clocked in.
+func (s *AttendanceService) StartBreak(ctx context.Context, shiftID, workerID int64) (*model.TimeEntry, error) {
+	funcName := "/service/attendance/StartBreak"
+
+	now := s.Clock.Now()
+	var entry *model.TimeEntry
+	err := s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		assignment, shift, current, err := lockOpenTimeEntry(repos, shiftID, workerID)
+		if err != nil {
+			log.Printf("%s: lockOpenTimeEntry error: %v", funcName, err)
+			return err
+		}
+		if openBreak(current) != nil {
+			return errs.ErrBreakInProgress
+		}
+
+		_, err = repos.TimeEntry.CreateTimeEntryBreak(&model.TimeEntryBreak{TimeEntryID: current.ID, StartAt: now})
+		if err != nil {
+			log.Printf("%s: CreateTimeEntryBreak error: %v", funcName, err)
+			return err
+		}
+		entry, err = loadTimeEntry(repos, s.Config, assignment.ID, shift, now)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// EndBreak ends the break in progress.
+func (s *AttendanceService) EndBreak(ctx context.Context, shiftID, workerID int64) (*model.TimeEntry, error) {
+	funcName := "/service/attendance/EndBreak"
+
+	now := s.Clock.Now()
+	var entry *model.TimeEntry
+	err := s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		assignment, shift, current, err := lockOpenTimeEntry(repos, shiftID, workerID)
+		if err != nil {
+			log.Printf("%s: lockOpenTimeEntry error: %v", funcName, err)
+			return err
+		}
+		b := openBreak(current)
+		if b == nil {
+			return errs.ErrNoBreakInProgress
+		}
+
+		if err := repos.TimeEntry.EndTimeEntryBreak(b.ID, now); err != nil {
+			log.Printf("%s: EndTimeEntryBreak error: %v", funcName, err)
+			return err
+		}
+		entry, err = loadTimeEntry(repos, s.Config, assignment.ID, shift, now)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (s *AttendanceService) GetTimeEntry(ctx context.Context, workerShiftID int64) (*model.TimeEntry, error) {
+	funcName := "/service/attendance/GetTimeEntry"
+
+	var entry *model.TimeEntry
+	err := s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		assignment, err := repos.WorkerShift.GetWorkerShiftByID(workerShiftID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return errs.ErrWorkerShiftNotFound
+		}
+		if err != nil {
+			log.Printf("%s: GetWorkerShiftByID error: %v", funcName, err)
+			return err
+		}
+		shift, err := repos.Shift.GetShiftByID(assignment.ShiftID)
+		if err != nil {
+			log.Printf("%s: GetShiftByID error: %v", funcName, err)
+			return err
+		}
+		entry, err = loadTimeEntry(repos, s.Config, assignment.ID, shift, s.Clock.Now())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// CorrectTimeEntry lets an admin set the times of an approved, done or
+// no-show shift, recording who corrected them and why. A clock-out time
+// makes the shift DONE; without one the worker is still clocked in and the
+// shift is APPROVED again.
+func (s *AttendanceService) CorrectTimeEntry(ctx context.Context, workerShiftID int64, correction *model.TimeEntryCorrection) (*model.TimeEntry, error) {
+	funcName := "/service/attendance/CorrectTimeEntry"
+
+	now := s.Clock.Now()
+	reason := strings.TrimSpace(correction.Reason)
+	if reason == "" {
+		return nil, fmt.Errorf("%w: reason is required", errs.ErrInvalidTimeEntry)
+	}
+	if len(reason) > 255 {
+		return nil, fmt.Errorf("%w: reason is longer than 255 characters", errs.ErrInvalidTimeEntry)
+	}
+
+	var entry *model.TimeEntry
+	err := s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		assignment, err := repos.WorkerShift.GetWorkerShiftByIDForUpdate(workerShiftID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return errs.ErrWorkerShiftNotFound
+		}
+		if err != nil {
+			log.Printf("%s: GetWorkerShiftByIDForUpdate error: %v", funcName, err)
+			return err
+		}
+		switch assignment.Status {
+		case model.WORKER_SHIFT_APPROVED, model.WORKER_SHIFT_DONE, model.WORKER_SHIFT_NO_SHOW:
+		default:
+			return errs.ErrTimeEntryState
+		}
+		shift, err := repos.Shift.GetShiftByID(assignment.ShiftID)
+		if err != nil {
+			log.Printf("%s: GetShiftByID error: %v", funcName, err)
+			return err
+		}
+
+		current, err := repos.TimeEntry.GetTimeEntryByWorkerShiftIDForUpdate(assignment.ID)
+		if errors.Is(err, sql.ErrNoRows) {
+			current = &model.TimeEntry{WorkerShiftID: assignment.ID}
+		} else if err != nil {
+			log.Printf("%s: GetTimeEntryByWorkerShiftIDForUpdate error: %v", funcName, err)
+			return err
+		}
+
+		breaks := correction.Breaks
+		if breaks == nil {
+			breaks = current.Breaks
+		}
+		if err := validateTimeEntry(correction.ClockInAt, correction.ClockOutAt, breaks, now); err != nil {
+			return err
+		}
+
+		current.ClockInAt = correction.ClockInAt
+		current.ClockOutAt = correction.ClockOutAt
+		current.AutoClockOut = false
+		current.CorrectedBy = auth.UserID(ctx)
+		current.CorrectionReason = reason
+		current.CorrectedAt = &now
+		if current.ID == 0 {
+			current.ID, err = repos.TimeEntry.CreateTimeEntry(current)
+		} else {
+			err = repos.TimeEntry.UpdateTimeEntry(current)
+		}
+		if err != nil {
+			log.Printf("%s: save time entry error: %v", funcName, err)
+			return err
+		}
+
+		if correction.Breaks != nil {
+			if err := repos.TimeEntry.DeleteTimeEntryBreaks(current.ID); err != nil {
+				log.Printf("%s: DeleteTimeEntryBreaks error: %v", funcName, err)
+				return err
+			}
+			for _, b := range correction.Breaks {
+				b.TimeEntryID = current.ID
+				if _, err := repos.TimeEntry.CreateTimeEntryBreak(b); err != nil {
+					log.Printf("%s: CreateTimeEntryBreak error: %v", funcName, err)
+					return err
+				}
+			}
+		}
+
+		status := model.WORKER_SHIFT_APPROVED
+		if correction.ClockOutAt != nil {
+			status = model.WORKER_SHIFT_DONE
+		}
+		if assignment.Status != status {
+			err := repos.WorkerShift.UpdatesWorkerShiftStatus(assignment.ID, status, assignment.ApprovedBy)
+			if err != nil {
+				log.Printf("%s: UpdatesWorkerShiftStatus error: %v", funcName, err)
+				return err
+			}
+		}
+
+		entry, err = loadTimeEntry(repos, s.Config, assignment.ID, shift, now)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// validateTimeEntry checks that the times are in the past and in order, and
+// that the breaks fall within them without overlapping. Only the last break
+// may still be in progress, and only while the worker is clocked in.
+func validateTimeEntry(clockIn time.Time, clockOut *time.Time, breaks []*model.TimeEntryBreak, now time.Time) error {
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: %s", errs.ErrInvalidTimeEntry, fmt.Sprintf(format, args...))
+	}
+
+	if clockIn.IsZero() {
+		return invalid("clock_in_at is required")
+	}
+	if clockIn.After(now) {
+		return invalid("clock_in_at is in the future")
+	}
+	end := now
+	if clockOut != nil {
+		if !clockOut.After(clockIn) {
+			return invalid("clock_out_at must be after clock_in_at")
+		}
+		if clockOut.After(now) {
+			return invalid("clock_out_at is in the future")
+		}
+		end = *clockOut
+	}
+
+	sort.SliceStable(breaks, func(i, j int) bool { return breaks[i].StartAt.Before(breaks[j].StartAt) })
+	previousEnd := clockIn
+	for i, b := range breaks {
+		if b.StartAt.Before(previousEnd) {
+			return invalid("breaks must start after clock_in_at and not overlap")
+		}
+		if b.EndAt == nil {
+			if clockOut != nil || i != len(breaks)-1 {
+				return invalid("only the last break may be in progress, while clocked in")
+			}
+			if b.StartAt.After(end) {
+				return invalid("break starts in the future")
+			}
+			continue
+		}
+		if !b.EndAt.After(b.StartAt) {
+			return invalid("a break must end after it starts")
+		}
+		if b.EndAt.After(end) {
+			return invalid("breaks must end before clock_out_at")
+		}
+		previousEnd = *b.EndAt
+	}
+	return nil
+}
+
+// GetAttendanceStats sums up, per worker, the shifts dated in the range
+// that are DONE or NO_SHOW: how often they clocked in, were late, left
+// early or did not turn up, and the hours worked against the hours scheduled.
+func (s *AttendanceService) GetAttendanceStats(ctx context.Context, query model.AttendanceQuery) (*model.AttendanceStats, error) {
+	funcName := "/service/attendance/GetAttendanceStats"
+
+	from, err := time.Parse(dateLayout, query.DateFrom)
+	if err != nil {
+		return nil, fmt.Errorf("%w: from must be YYYY-MM-DD", errs.ErrInvalidAttendanceQuery)
+	}
+	to, err := time.Parse(dateLayout, query.DateTo)
+	if err != nil {
+		return nil, fmt.Errorf("%w: to must be YYYY-MM-DD", errs.ErrInvalidAttendanceQuery)
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: to is before from", errs.ErrInvalidAttendanceQuery)
+	}
+	if to.Sub(from) >= maxAttendanceDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: the range is longer than %d days", errs.ErrInvalidAttendanceQuery, maxAttendanceDays)
+	}
+
+	stats := &model.AttendanceStats{
+		DateFrom: query.DateFrom,
+		DateTo:   query.DateTo,
+		Workers:  make([]*model.WorkerAttendance, 0),
+	}
+	now := s.Clock.Now()
+	// The transaction only gives the statistics a consistent view
+	err = s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		var settled []model.WorkerShiftDetail
+		for _, status := range []string{model.WORKER_SHIFT_DONE, model.WORKER_SHIFT_NO_SHOW} {
+			status := status
+			list, err := repos.WorkerShift.GetWorkerShiftDetailListByFilter(&model.WorkerShiftDetailQuery{
+				UserAccountID: query.UserAccountID,
+				Status:        &status,
+				DateFrom:      &query.DateFrom,
+				DateTo:        &query.DateTo,
+			})
+			if err != nil {
+				log.Printf("%s: GetWorkerShiftDetailListByFilter error: %v", funcName, err)
+				return err
+			}
+			settled = append(settled, list...)
+		}
+
+		workerShiftIDs := make([]int64, 0, len(settled))
+		for i := range settled {
+			workerShiftIDs = append(workerShiftIDs, settled[i].ID)
+		}
+		entries, err := repos.TimeEntry.ListTimeEntriesByWorkerShiftIDs(workerShiftIDs)
+		if err != nil {
+			log.Printf("%s: ListTimeEntriesByWorkerShiftIDs error: %v", funcName, err)
+			return err
+		}
+		entryByWorkerShift := make(map[int64]*model.TimeEntry, len(entries))
+		for _, entry := range entries {
+			entryByWorkerShift[entry.WorkerShiftID] = entry
+		}
+
+		byWorker := make(map[int64]*model.WorkerAttendance)
+		for i := range settled {
+			detail := &settled[i]
+			worker, ok := byWorker[detail.UserAccountID]
+			if !ok {
+				user, err := repos.User.GetUserByID(detail.UserAccountID)
+				if err != nil {
+					log.Printf("%s: GetUserByID error: %v", funcName, err)
+					return err
+				}
+				worker = &model.WorkerAttendance{UserAccountID: user.ID, WorkerName: user.Name}
+				byWorker[detail.UserAccountID] = worker
+				stats.Workers = append(stats.Workers, worker)
+			}
+
+			shift := detail.AsShift()
+			start, end, err := rules.Bounds(shift)
+			if err != nil {
+				log.Printf("%s: Bounds error for shift %d: %v", funcName, detail.ShiftID, err)
+				continue
+			}
+			worker.Shifts++
+			worker.ScheduledHours += end.Sub(start).Hours()
+			if detail.Status == model.WORKER_SHIFT_NO_SHOW {
+				worker.NoShows++
+				continue
+			}
+
+			entry, ok := entryByWorkerShift[detail.ID]
+			if !ok {
+				continue
+			}
+			worker.ClockedIn++
+			worked, err := summarizeTimeEntry(entry, shift, s.Config, now)
+			if err != nil {
+				return err
+			}
+			worker.WorkedHours += worked.Hours()
+			if entry.Late {
+				worker.Late++
+				worker.LateMinutes += entry.LateMinutes
+			}
+			if entry.LeftEarly {
+				worker.EarlyLeaves++
+				worker.EarlyLeaveMinutes += entry.EarlyLeaveMinutes
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(stats.Workers, func(i, j int) bool {
+		return stats.Workers[i].UserAccountID < stats.Workers[j].UserAccountID
+	})
+	return stats, nil
+}
+
+// lockOpenTimeEntry locks the worker's approved shift and returns it with
+// its time entry, which must still be clocked in.
+func lockOpenTimeEntry(repos *repository.Repositories, shiftID, workerID int64) (*model.WorkerShift, *model.Shift, *model.TimeEntry, error) {
+	assignment, shift, err := lockWorkerShiftOnShift(repos, shiftID, workerID, model.WORKER_SHIFT_APPROVED)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if assignment == nil {
+		return nil, nil, nil, errs.ErrNoApprovedShift
+	}
+	entry, err := repos.TimeEntry.GetTimeEntryByWorkerShiftIDForUpdate(assignment.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, nil, errs.ErrNotClockedIn
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if entry.ClockOutAt != nil {
+		return nil, nil, nil, errs.ErrNotClockedIn
+	}
+	return assignment, shift, entry, nil
+}
+
+// clockOut ends the time entry at, together with a break in progress.
+func clockOut(repos *repository.Repositories, entry *model.TimeEntry, at time.Time) error {
+	if b := openBreak(entry); b != nil {
+		if err := repos.TimeEntry.EndTimeEntryBreak(b.ID, at); err != nil {
+			return err
+		}
+		b.EndAt = &at
+	}
+	entry.ClockOutAt = &at
+	return repos.TimeEntry.UpdateTimeEntry(entry)
+}
+
+// settleAttendance closes the attendance of an approved shift once clocking
+// out is over. A worker still clocked in is clocked out at the end of the
+// shift and the shift is DONE; one who never clocked in is a NO_SHOW. It
+// returns the new status of the shift.
+func settleAttendance(repos *repository.Repositories, assignment *model.WorkerShift, end time.Time) (string, error) {
+	entry, err := repos.TimeEntry.GetTimeEntryByWorkerShiftIDForUpdate(assignment.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		err := repos.WorkerShift.UpdatesWorkerShiftStatus(assignment.ID, model.WORKER_SHIFT_NO_SHOW, assignment.ApprovedBy)
+		return model.WORKER_SHIFT_NO_SHOW, err
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if entry.ClockOutAt == nil {
+		// Never before the worker clocked in or went on a break
+		at := end
+		if entry.ClockInAt.After(at) {
+			at = entry.ClockInAt
+		}
+		if b := openBreak(entry); b != nil && b.StartAt.After(at) {
+			at = b.StartAt
+		}
+		entry.AutoClockOut = true
+		if err := clockOut(repos, entry, at); err != nil {
+			return "", err
+		}
+	}
+	err = repos.WorkerShift.UpdatesWorkerShiftStatus(assignment.ID, model.WORKER_SHIFT_DONE, assignment.ApprovedBy)
+	return model.WORKER_SHIFT_DONE, err
+}
+
+// loadTimeEntry reads the time entry of a worker shift with the figures
+// derived from it.
+func loadTimeEntry(repos *repository.Repositories, cfg config.AttendanceConfig, workerShiftID int64, shift *model.Shift, now time.Time) (*model.TimeEntry, error) {
+	entry, err := repos.TimeEntry.GetTimeEntryByWorkerShiftID(workerShiftID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errs.ErrTimeEntryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := summarizeTimeEntry(entry, shift, cfg, now); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// summarizeTimeEntry fills in how late the worker clocked in, how early
+// they clocked out and the time spent on breaks and working, counting up to
+// now while still clocked in or on a break. It returns the time worked.
+func summarizeTimeEntry(entry *model.TimeEntry, shift *model.Shift, cfg config.AttendanceConfig, now time.Time) (time.Duration, error) {
+	start, shiftEnd, err := rules.Bounds(shift)
+	if err != nil {
+		return 0, err
+	}
+	if late := entry.ClockInAt.Sub(start); late > 0 {
+		entry.LateMinutes = int(late / time.Minute)
+		entry.Late = late > cfg.LateTolerance.Std()
+	}
+	if entry.ClockOutAt != nil {
+		if early := shiftEnd.Sub(*entry.ClockOutAt); early > 0 {
+			entry.EarlyLeaveMinutes = int(early / time.Minute)
+			entry.LeftEarly = early > cfg.EarlyLeaveTolerance.Std()
+		}
+	}
+
+	end := now
+	if entry.ClockOutAt != nil {
+		end = *entry.ClockOutAt
+	}
+	var breaks time.Duration
+	for _, b := range entry.Breaks {
+		breakEnd := end
+		if b.EndAt != nil {
+			breakEnd = *b.EndAt
+		}
+		if breakEnd.After(b.StartAt) {
+			breaks += breakEnd.Sub(b.StartAt)
+		}
+	}
+	worked := end.Sub(entry.ClockInAt) - breaks
+	if worked < 0 {
+		worked = 0
+	}
+	entry.BreakMinutes = int(breaks / time.Minute)
+	entry.WorkedMinutes = int(worked / time.Minute)
+	return worked, nil
+}
+
+// openBreak returns the break in progress, or nil.
+func openBreak(entry *model.TimeEntry) *model.TimeEntryBreak {
+	if n := len(entry.Breaks); n > 0 && entry.Breaks[n-1].EndAt == nil {
+		return entry.Breaks[n-1]
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	errs "dailyworkerroster/error"
+	"dailyworkerroster/model"
+	"dailyworkerroster/service"
+)
+
+func (f *fixture) attendance() service.AttendanceServiceItf {
+	return service.NewAttendanceService(f.uow, f.cfg.Attendance, f.clock)
+}
+
+func TestClockInWindow(t *testing.T) {
+	start := time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC)
+	end := start.Add(4 * time.Hour)
+	f := newFixture(t, start)
+	early := f.cfg.Attendance.ClockInEarly.Std()
+	shift := f.shift(t, start, end.Sub(start))
+	f.request(t, shift.ID, f.worker, model.WORKER_SHIFT_APPROVED)
+	bob := f.user(t, "bob", model.ROLE_WORKER)
+	f.request(t, shift.ID, bob, model.WORKER_SHIFT_APPROVED)
+	svc := f.attendance()
+
+	f.clock.Set(start.Add(-early - time.Minute))
+	if _, err := svc.ClockIn(context.Background(), shift.ID, f.worker); !errors.Is(err, errs.ErrClockInNotOpen) {
+		t.Errorf("ClockIn before the window: err = %v, want ErrClockInNotOpen", err)
+	}
+
+	f.clock.Set(start.Add(-early))
+	entry, err := svc.ClockIn(context.Background(), shift.ID, f.worker)
+	if err != nil {
+		t.Fatalf("ClockIn as the window opens: %v", err)
+	}
+	if entry.Late || entry.LateMinutes != 0 {
+		t.Errorf("early clock-in: late %v, %d minutes; want on time", entry.Late, entry.LateMinutes)
+	}
+	if _, err := svc.ClockIn(context.Background(), shift.ID, f.worker); !errors.Is(err, errs.ErrAlreadyClockedIn) {
+		t.Errorf("second ClockIn: err = %v, want ErrAlreadyClockedIn", err)
+	}
+
+	f.clock.Set(end)
+	if _, err := svc.ClockIn(context.Background(), shift.ID, bob); !errors.Is(err, errs.ErrClockInNotOpen) {
+		t.Errorf("ClockIn at the end: err = %v, want ErrClockInNotOpen", err)
+	}
+}
+
+func TestClockInLate(t *testing.T) {
+	start := time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC)
+	f := newFixture(t, start)
+	tolerance := f.cfg.Attendance.LateTolerance.Std()
+	shift := f.shift(t, start, 4*time.Hour)
+	f.request(t, shift.ID, f.worker, model.WORKER_SHIFT_APPROVED)
+	bob := f.user(t, "bob", model.ROLE_WORKER)
+	f.request(t, shift.ID, bob, model.WORKER_SHIFT_APPROVED)
+	svc := f.attendance()
+
+	f.clock.Set(start.Add(tolerance))
+	entry, err := svc.ClockIn(context.Background(), shift.ID, f.worker)
+	if err != nil {
+		t.Fatalf("ClockIn within the tolerance: %v", err)
+	}
+	if entry.Late {
+		t.Errorf("clock-in within the tolerance counts as late")
+	}
+
+	f.clock.Set(start.Add(tolerance + 3*time.Minute))
+	entry, err = svc.ClockIn(context.Background(), shift.ID, bob)
+	if err != nil {
+		t.Fatalf("ClockIn after the tolerance: %v", err)
+	}
+	if want := int((tolerance + 3*time.Minute) / time.Minute); !entry.Late || entry.LateMinutes != want {
+		t.Errorf("late clock-in: late %v, %d minutes; want late by %d", entry.Late, entry.LateMinutes, want)
+	}
+}
+
+func TestClockOutEarlyIsCounted(t *testing.T) {
+	start := time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC)
+	end := start.Add(4 * time.Hour)
+	f := newFixture(t, start)
+	tolerance := f.cfg.Attendance.EarlyLeaveTolerance.Std()
+	shift := f.shift(t, start, end.Sub(start))
+	stayed := f.request(t, shift.ID, f.worker, model.WORKER_SHIFT_APPROVED)
+	bob := f.user(t, "bob", model.ROLE_WORKER)
+	left := f.request(t, shift.ID, bob, model.WORKER_SHIFT_APPROVED)
+	svc := f.attendance()
+	for _, worker := range []int64{f.worker, bob} {
+		if _, err := svc.ClockIn(context.Background(), shift.ID, worker); err != nil {
+			t.Fatalf("ClockIn: %v", err)
+		}
+	}
+
+	f.clock.Set(end.Add(-time.Hour))
+	entry, err := svc.ClockOut(context.Background(), shift.ID, bob)
+	if err != nil {
+		t.Fatalf("ClockOut an hour early: %v", err)
+	}
+	if !entry.LeftEarly || entry.EarlyLeaveMinutes != 60 {
+		t.Errorf("early clock-out: left early %v, %d minutes; want 60", entry.LeftEarly, entry.EarlyLeaveMinutes)
+	}
+
+	f.clock.Set(end.Add(-tolerance))
+	entry, err = svc.ClockOut(context.Background(), shift.ID, f.worker)
+	if err != nil {
+		t.Fatalf("ClockOut within the tolerance: %v", err)
+	}
+	if entry.LeftEarly {
+		t.Errorf("clock-out within the tolerance counts as leaving early")
+	}
+
+	for _, id := range []int64{stayed, left} {
+		if got := f.status(t, id); got != model.WORKER_SHIFT_DONE {
+			t.Errorf("worker shift %d is %s, want DONE", id, got)
+		}
+	}
+	stats, err := svc.GetAttendanceStats(context.Background(), model.AttendanceQuery{DateFrom: shift.Date, DateTo: shift.Date})
+	if err != nil {
+		t.Fatalf("GetAttendanceStats: %v", err)
+	}
+	early := make(map[int64]int)
+	for _, worker := range stats.Workers {
+		early[worker.UserAccountID] = worker.EarlyLeaves
+		if worker.UserAccountID == bob && worker.EarlyLeaveMinutes != 60 {
+			t.Errorf("early leave minutes = %d, want 60", worker.EarlyLeaveMinutes)
+		}
+	}
+	if early[bob] != 1 || early[f.worker] != 0 {
+		t.Errorf("early leaves by worker = %v, want only %d once", early, bob)
+	}
+}
+
+func TestAutoClockOutIsNotAnEarlyLeave(t *testing.T) {
+	start := time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC)
+	end := start.Add(4 * time.Hour)
+	f := newFixture(t, start)
+	shift := f.shift(t, start, end.Sub(start))
+	worked := f.request(t, shift.ID, f.worker, model.WORKER_SHIFT_APPROVED)
+	if _, err := f.attendance().ClockIn(context.Background(), shift.ID, f.worker); err != nil {
+		t.Fatalf("ClockIn: %v", err)
+	}
+
+	f.clock.Set(end.Add(f.cfg.Attendance.ClockOutGrace.Std()))
+	if _, err := f.lifecycle().Advance(context.Background()); err != nil {
+		t.Fatalf("Advance: %v", err)
+	}
+	entry, err := f.attendance().GetTimeEntry(context.Background(), worked)
+	if err != nil {
+		t.Fatalf("GetTimeEntry: %v", err)
+	}
+	if !entry.AutoClockOut || entry.ClockOutAt == nil || !entry.ClockOutAt.Equal(end) {
+		t.Errorf("clocked out at %v, auto %v; want auto clock-out at %v", entry.ClockOutAt, entry.AutoClockOut, end)
+	}
+	if entry.LeftEarly || entry.WorkedMinutes != 240 {
+		t.Errorf("left early %v, worked %d minutes; want the whole shift", entry.LeftEarly, entry.WorkedMinutes)
+	}
+}
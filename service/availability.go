@@ -0,0 +1,330 @@
+package service
+
+import (
+	"context"
+	"dailyworkerroster/clock"
+	errs "dailyworkerroster/error"
+	"dailyworkerroster/model"
+	"dailyworkerroster/repository"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+type AvailabilityServiceItf interface {
+	GetAvailability(ctx context.Context, workerID int64) (*model.WorkerAvailability, error)
+	SetAvailability(ctx context.Context, availability *model.WorkerAvailability) error
+	MarkUnavailable(ctx context.Context, unavailable *model.UnavailableDate) error
+	ClearUnavailable(ctx context.Context, workerID int64, date string) error
+}
+
+type AvailabilityService struct {
+	AvailabilityRepo repository.AvailabilityRepoItf
+	UnitOfWork       repository.UnitOfWorkItf
+	Clock            clock.Clock
+}
+
+func NewAvailabilityService(
+	availabilityRepo repository.AvailabilityRepoItf,
+	unitOfWork repository.UnitOfWorkItf,
+	clk clock.Clock) AvailabilityServiceItf {
+	return &AvailabilityService{
+		AvailabilityRepo: availabilityRepo,
+		UnitOfWork:       unitOfWork,
+		Clock:            clk,
+	}
+}
+
+func (s *AvailabilityService) GetAvailability(ctx context.Context, workerID int64) (*model.WorkerAvailability, error) {
+	funcName := "/service/availability/GetAvailability"
+
+	availability, err := loadAvailability(s.AvailabilityRepo, workerID, s.Clock.Now())
+	if err != nil {
+		log.Printf("%s: loadAvailability error: %v", funcName, err)
+		return nil, err
+	}
+	return availability, nil
+}
+
+// SetAvailability replaces the worker's weekly windows and preferences.
+// Unavailable dates are kept; they are managed one by one.
+func (s *AvailabilityService) SetAvailability(ctx context.Context, availability *model.WorkerAvailability) error {
+	funcName := "/service/availability/SetAvailability"
+
+	if err := normalizeWindows(availability.Windows); err != nil {
+		return err
+	}
+
+	return s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		if err := lockWorker(repos, availability.UserAccountID); err != nil {
+			return err
+		}
+
+		locationIDs := make([]int64, 0, len(availability.PreferredLocationIDs))
+		seenLocations := make(map[int64]bool)
+		for _, id := range availability.PreferredLocationIDs {
+			if seenLocations[id] {
+				continue
+			}
+			seenLocations[id] = true
+			if _, err := repos.Location.GetLocationByID(id); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return fmt.Errorf("%w: unknown location %d", errs.ErrInvalidAvailability, id)
+				}
+				log.Printf("%s: GetLocationByID error: %v", funcName, err)
+				return err
+			}
+			locationIDs = append(locationIDs, id)
+		}
+
+		skillIDs := make([]int64, 0, len(availability.PreferredRoles))
+		seenSkills := make(map[int64]bool)
+		for _, role := range availability.PreferredRoles {
+			code := strings.ToUpper(strings.TrimSpace(role))
+			skill, err := repos.Skill.GetSkillByCode(code)
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%w: unknown role %q", errs.ErrInvalidAvailability, role)
+			}
+			if err != nil {
+				log.Printf("%s: GetSkillByCode error: %v", funcName, err)
+				return err
+			}
+			if !seenSkills[skill.ID] {
+				seenSkills[skill.ID] = true
+				skillIDs = append(skillIDs, skill.ID)
+			}
+		}
+
+		if err := repos.Availability.ReplaceAvailabilityWindows(availability.UserAccountID, availability.Windows); err != nil {
+			log.Printf("%s: ReplaceAvailabilityWindows error: %v", funcName, err)
+			return err
+		}
+		if err := repos.Availability.ReplacePreferredLocations(availability.UserAccountID, locationIDs); err != nil {
+			log.Printf("%s: ReplacePreferredLocations error: %v", funcName, err)
+			return err
+		}
+		if err := repos.Availability.ReplacePreferredRoles(availability.UserAccountID, skillIDs); err != nil {
+			log.Printf("%s: ReplacePreferredRoles error: %v", funcName, err)
+			return err
+		}
+		return nil
+	})
+}
+
+// MarkUnavailable marks a day the worker cannot work, or replaces the reason
+// of a day already marked.
+func (s *AvailabilityService) MarkUnavailable(ctx context.Context, unavailable *model.UnavailableDate) error {
+	funcName := "/service/availability/MarkUnavailable"
+
+	if _, err := time.Parse(dateLayout, unavailable.Date); err != nil {
+		return fmt.Errorf("%w: date must be YYYY-MM-DD", errs.ErrInvalidAvailability)
+	}
+	unavailable.Reason = strings.TrimSpace(unavailable.Reason)
+	if len(unavailable.Reason) > 255 {
+		return fmt.Errorf("%w: reason is longer than 255 characters", errs.ErrInvalidAvailability)
+	}
+
+	return s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		if err := lockWorker(repos, unavailable.UserAccountID); err != nil {
+			return err
+		}
+
+		current, err := repos.Availability.GetUnavailableDate(unavailable.UserAccountID, unavailable.Date)
+		if err == nil {
+			unavailable.ID = current.ID
+			if err := repos.Availability.UpdateUnavailableDate(unavailable); err != nil {
+				log.Printf("%s: UpdateUnavailableDate error: %v", funcName, err)
+				return err
+			}
+			return nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("%s: GetUnavailableDate error: %v", funcName, err)
+			return err
+		}
+
+		if _, err := repos.Availability.CreateUnavailableDate(unavailable); err != nil {
+			log.Printf("%s: CreateUnavailableDate error: %v", funcName, err)
+			return err
+		}
+		return nil
+	})
+}
+
+func (s *AvailabilityService) ClearUnavailable(ctx context.Context, workerID int64, date string) error {
+	funcName := "/service/availability/ClearUnavailable"
+
+	return s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		if _, err := repos.Availability.GetUnavailableDate(workerID, date); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errs.ErrUnavailableDateNotFound
+			}
+			log.Printf("%s: GetUnavailableDate error: %v", funcName, err)
+			return err
+		}
+		if err := repos.Availability.DeleteUnavailableDate(workerID, date); err != nil {
+			log.Printf("%s: DeleteUnavailableDate error: %v", funcName, err)
+			return err
+		}
+		return nil
+	})
+}
+
+// lockWorker locks the user and checks they are a worker.
+func lockWorker(repos *repository.Repositories, workerID int64) error {
+	worker, err := repos.User.GetUserByIDForUpdate(workerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errs.ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if worker.Role != model.ROLE_WORKER {
+		return fmt.Errorf("%w: user %d is not a worker", errs.ErrInvalidAvailability, worker.ID)
+	}
+	return nil
+}
+
+func normalizeWindows(windows []*model.AvailabilityWindow) error {
+	for _, window := range windows {
+		window.Weekday = strings.ToUpper(strings.TrimSpace(window.Weekday))
+		if _, ok := templateWeekdays[window.Weekday]; !ok {
+			return fmt.Errorf("%w: unknown weekday %q", errs.ErrInvalidAvailability, window.Weekday)
+		}
+		start, err := parseClock(window.StartTime)
+		if err != nil {
+			return fmt.Errorf("%w: start_time must be HH:MM", errs.ErrInvalidAvailability)
+		}
+		end, err := parseClock(window.EndTime)
+		if err != nil {
+			return fmt.Errorf("%w: end_time must be HH:MM", errs.ErrInvalidAvailability)
+		}
+		window.StartTime = start.Format("15:04:05")
+		window.EndTime = end.Format("15:04:05")
+	}
+	return nil
+}
+
+// loadAvailability reads a worker's calendar. Unavailable dates are listed
+// from the day before now on, which covers today in every time zone.
+func loadAvailability(repo repository.AvailabilityRepoItf, workerID int64, now time.Time) (*model.WorkerAvailability, error) {
+	availability := &model.WorkerAvailability{UserAccountID: workerID}
+
+	var err error
+	if availability.Windows, err = repo.ListAvailabilityWindows(workerID); err != nil {
+		return nil, err
+	}
+	from := now.AddDate(0, 0, -1).Format(dateLayout)
+	if availability.UnavailableDates, err = repo.ListUnavailableDates(workerID, from); err != nil {
+		return nil, err
+	}
+	if availability.PreferredLocationIDs, err = repo.ListPreferredLocations(workerID); err != nil {
+		return nil, err
+	}
+	if availability.PreferredRoles, err = repo.ListPreferredRoles(workerID); err != nil {
+		return nil, err
+	}
+	return availability, nil
+}
+
+// availabilityConflicts describes how a shift clashes with the worker's
+// calendar: days of the shift the worker marked unavailable, and times not
+// covered by their weekly windows. Clock times are those of the shift's
+// location.
+func availabilityConflicts(availability *model.WorkerAvailability, shift *model.Shift) []string {
+	var conflicts []string
+
+	unavailable := make(map[string]*model.UnavailableDate)
+	for _, date := range availability.UnavailableDates {
+		unavailable[date.Date] = date
+	}
+	for day := startOfDay(shift.StartAt); day.Before(shift.EndAt); day = day.AddDate(0, 0, 1) {
+		date, ok := unavailable[day.Format(dateLayout)]
+		if !ok {
+			continue
+		}
+		conflict := "unavailable on " + date.Date
+		if date.Reason != "" {
+			conflict += ": " + date.Reason
+		}
+		conflicts = append(conflicts, conflict)
+	}
+
+	if len(availability.Windows) > 0 && !withinWindows(availability.Windows, shift.StartAt, shift.EndAt) {
+		conflicts = append(conflicts, "outside weekly availability")
+	}
+	return conflicts
+}
+
+// withinWindows reports whether the weekly windows, joined where they touch,
+// cover the period from start to end.
+func withinWindows(windows []*model.AvailabilityWindow, start, end time.Time) bool {
+	type period struct{ from, to time.Time }
+
+	// Windows of the day before may run past midnight into the shift
+	var periods []period
+	for day := startOfDay(start).AddDate(0, 0, -1); day.Before(end); day = day.AddDate(0, 0, 1) {
+		for _, window := range windows {
+			if templateWeekdays[window.Weekday] != day.Weekday() {
+				continue
+			}
+			startClock, err := parseClock(window.StartTime)
+			if err != nil {
+				continue
+			}
+			endClock, err := parseClock(window.EndTime)
+			if err != nil {
+				continue
+			}
+			from := onDay(day, startClock)
+			to := onDay(day, endClock)
+			if !to.After(from) {
+				to = onDay(day.AddDate(0, 0, 1), endClock)
+			}
+			periods = append(periods, period{from, to})
+		}
+	}
+	sort.Slice(periods, func(i, j int) bool { return periods[i].from.Before(periods[j].from) })
+
+	covered := start
+	for _, p := range periods {
+		if p.from.After(covered) {
+			break
+		}
+		if p.to.After(covered) {
+			covered = p.to
+		}
+	}
+	return !covered.Before(end)
+}
+
+// preferenceScore counts the worker's preferences a shift matches.
+func preferenceScore(availability *model.WorkerAvailability, shift *model.Shift) int {
+	score := 0
+	for _, id := range availability.PreferredLocationIDs {
+		if id == shift.LocationID {
+			score++
+			break
+		}
+	}
+	for _, role := range availability.PreferredRoles {
+		if role == shift.RoleAssignment {
+			score++
+			break
+		}
+	}
+	return score
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// onDay is the given clock time on day, in day's zone.
+func onDay(day, clock time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, day.Location())
+}
@@ -0,0 +1,356 @@
+package service
+
+import (
+	"context"
+	"dailyworkerroster/clock"
+	"dailyworkerroster/config"
+	errs "dailyworkerroster/error"
+	"dailyworkerroster/model"
+	"dailyworkerroster/repository"
+	"dailyworkerroster/rules"
+	"database/sql"
+	"errors"
+	"log"
+	"math"
+	"sort"
+	"time"
+)
+
+type FairnessServiceItf interface {
+	RankApplicants(ctx context.Context, shiftID int64) (*model.ApplicantRanking, error)
+	ApproveTopApplicants(ctx context.Context, shiftID int64) ([]*model.ApplicantScore, error)
+	// AutoApprove fills the open shifts starting within
+	// Config.AutoApproveBefore with their top ranked applicants. A shift that
+	// fails is logged, counted and left for the next run.
+	AutoApprove(ctx context.Context) (*model.AutoApproveResult, error)
+}
+
+type FairnessService struct {
+	ShiftRepo  repository.ShiftRepoItf
+	UnitOfWork repository.UnitOfWorkItf
+	Config     config.FairnessConfig
+	Rules      *rules.Engine
+	Clock      clock.Clock
+}
+
+func NewFairnessService(
+	shiftRepo repository.ShiftRepoItf,
+	unitOfWork repository.UnitOfWorkItf,
+	cfg config.FairnessConfig,
+	engine *rules.Engine,
+	clk clock.Clock) FairnessServiceItf {
+	return &FairnessService{
+		ShiftRepo:  shiftRepo,
+		UnitOfWork: unitOfWork,
+		Config:     cfg,
+		Rules:      engine,
+		Clock:      clk,
+	}
+}
+
+// RankApplicants scores the pending requests for a shift with the
+// configured weights, best first. Nothing is changed.
+func (s *FairnessService) RankApplicants(ctx context.Context, shiftID int64) (*model.ApplicantRanking, error) {
+	funcName := "/service/fairness/RankApplicants"
+
+	var ranking *model.ApplicantRanking
+	// The transaction only gives the ranking a consistent view
+	err := s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		shift, err := repos.Shift.GetShiftByID(shiftID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return errs.ErrShiftNotFound
+		}
+		if err != nil {
+			log.Printf("%s: GetShiftByID error: %v", funcName, err)
+			return err
+		}
+		ranking, err = rankApplicants(repos, s.Rules, s.Config, shift, s.Clock.Now())
+		if err != nil {
+			log.Printf("%s: rankApplicants error: %v", funcName, err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ranking, nil
+}
+
+// ApproveTopApplicants approves the top ranked eligible applicants until
+// the shift is full, and returns them.
+func (s *FairnessService) ApproveTopApplicants(ctx context.Context, shiftID int64) ([]*model.ApplicantScore, error) {
+	var approved []*model.ApplicantScore
+	err := s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		var err error
+		approved, err = approveTopApplicants(ctx, repos, s.Rules, s.Config, shiftID, s.Clock.Now())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return approved, nil
+}
+
+func (s *FairnessService) AutoApprove(ctx context.Context) (*model.AutoApproveResult, error) {
+	funcName := "/service/fairness/AutoApprove"
+
+	result := &model.AutoApproveResult{}
+	lead := s.Config.AutoApproveBefore.Std()
+	if lead <= 0 {
+		return result, nil
+	}
+
+	now := s.Clock.Now()
+	// Shift dates are in the zone of their location, which may differ from
+	// the local one by a day
+	isAvailable := true
+	shifts, err := s.ShiftRepo.GetListShifts(model.ShiftListQuery{
+		IsAvailable: &isAvailable,
+		DateFrom:    now.AddDate(0, 0, -1).Format(dateLayout),
+		DateTo:      now.Add(lead).AddDate(0, 0, 1).Format(dateLayout),
+	})
+	if err != nil {
+		log.Printf("%s: GetListShifts error: %v", funcName, err)
+		return nil, err
+	}
+
+	for _, shift := range shifts {
+		start, err := shiftStart(shift)
+		if err != nil {
+			log.Printf("%s: shiftStart error for shift %d: %v", funcName, shift.ID, err)
+			continue
+		}
+		if !start.After(now) || start.Sub(now) > lead {
+			continue
+		}
+
+		var approved []*model.ApplicantScore
+		err = s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+			approved, err = approveTopApplicants(ctx, repos, s.Rules, s.Config, shift.ID, now)
+			return err
+		})
+		if errors.Is(err, errs.ErrShiftNotOpen) || errors.Is(err, errs.ErrNoEligibleApplicant) {
+			continue
+		}
+		if err != nil {
+			log.Printf("%s: approveTopApplicants error for shift %d: %v", funcName, shift.ID, err)
+			result.Failed++
+			continue
+		}
+		result.Shifts++
+		result.Approved += len(approved)
+	}
+	return result, nil
+}
+
+// approveTopApplicants locks the shift, ranks its applicants and approves
+// the eligible ones in rank order until the shift is full.
+func approveTopApplicants(ctx context.Context, repos *repository.Repositories, engine *rules.Engine, cfg config.FairnessConfig, shiftID int64, now time.Time) ([]*model.ApplicantScore, error) {
+	funcName := "/service/fairness/approveTopApplicants"
+
+	shift, err := repos.Shift.GetShiftByIDForUpdate(shiftID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errs.ErrShiftNotFound
+	}
+	if err != nil {
+		log.Printf("%s: GetShiftByIDForUpdate error: %v", funcName, err)
+		return nil, err
+	}
+	ranking, err := rankApplicants(repos, engine, cfg, shift, now)
+	if err != nil {
+		log.Printf("%s: rankApplicants error: %v", funcName, err)
+		return nil, err
+	}
+	open := ranking.Headcount - ranking.Filled
+	if !shift.IsAvailable || open <= 0 {
+		return nil, errs.ErrShiftNotOpen
+	}
+
+	var approved []*model.ApplicantScore
+	for _, applicant := range ranking.Applicants {
+		// Eligible applicants rank first
+		if len(approved) == open || !applicant.Eligible {
+			break
+		}
+		if _, _, err := approvePendingRequest(ctx, repos, engine, shift.ID, applicant.UserAccountID, now); err != nil {
+			return nil, err
+		}
+		approved = append(approved, applicant)
+	}
+	if len(approved) == 0 {
+		return nil, errs.ErrNoEligibleApplicant
+	}
+	return approved, nil
+}
+
+// rankApplicants scores the pending requests for the shift. Each factor is
+// scaled between the applicants, so weights compare factors with different
+// units, and a factor every applicant shares gives them all full points.
+// Eligible applicants come first, then by score and by request time.
+func rankApplicants(repos *repository.Repositories, engine *rules.Engine, cfg config.FairnessConfig, shift *model.Shift, now time.Time) (*model.ApplicantRanking, error) {
+	day, err := time.ParseInLocation(dateLayout, shift.Date, shift.StartAt.Location())
+	if err != nil {
+		return nil, err
+	}
+	weekFrom := rules.WeekStart(day).Format(dateLayout)
+	weekTo := rules.WeekStart(day).AddDate(0, 0, 6).Format(dateLayout)
+	periodFrom := rules.WeekStart(day).AddDate(0, 0, 7-cfg.PeriodDays).Format(dateLayout)
+	// Cancellations and no-shows look back from today, so they count for
+	// shifts far ahead too
+	historyFrom := now.AddDate(0, 0, -cfg.PeriodDays).Format(dateLayout)
+	queryFrom := periodFrom
+	if historyFrom < queryFrom {
+		queryFrom = historyFrom
+	}
+
+	workerShifts, err := repos.WorkerShift.ListWorkerShiftsByShift(shift.ID)
+	if err != nil {
+		return nil, err
+	}
+	ranking := &model.ApplicantRanking{
+		ShiftID:     shift.ID,
+		Headcount:   shift.Headcount,
+		PeriodFrom:  periodFrom,
+		HistoryFrom: historyFrom,
+		PeriodTo:    weekTo,
+		Applicants:  make([]*model.ApplicantScore, 0),
+	}
+	for _, ws := range workerShifts {
+		if holdsPlace(ws) {
+			ranking.Filled++
+		}
+		if ws.Status != model.WORKER_SHIFT_PENDING {
+			continue
+		}
+
+		worker, err := repos.User.GetUserByID(ws.UserAccountID)
+		if err != nil {
+			return nil, err
+		}
+		applicant := &model.ApplicantScore{
+			WorkerShiftID: ws.ID,
+			UserAccountID: worker.ID,
+			WorkerName:    worker.Name,
+			RequestedAt:   ws.CreatedAt,
+			SeniorityDays: int(now.Sub(worker.CreatedAt).Hours() / 24),
+			Eligible:      true,
+		}
+
+		history, err := repos.WorkerShift.GetWorkerShiftDetailListByFilter(&model.WorkerShiftDetailQuery{
+			UserAccountID: &worker.ID,
+			DateFrom:      &queryFrom,
+			DateTo:        &weekTo,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for i := range history {
+			detail := &history[i]
+			switch {
+			case detail.Status == model.WORKER_SHIFT_APPROVED || detail.Status == model.WORKER_SHIFT_DONE:
+				if detail.Date < periodFrom {
+					continue
+				}
+				start, end, err := rules.Bounds(detail.AsShift())
+				if err != nil {
+					return nil, err
+				}
+				hours := end.Sub(start).Hours()
+				applicant.PeriodHours += hours
+				if detail.Date >= weekFrom {
+					applicant.WeekHours += hours
+				}
+			case detail.Date < historyFrom:
+			case detail.Status == model.WORKER_SHIFT_CANCELLED:
+				applicant.Cancellations++
+			case detail.Status == model.WORKER_SHIFT_NO_SHOW:
+				applicant.NoShows++
+			}
+		}
+
+		if err := checkWorkerEligibility(repos, engine, shift, worker); err != nil {
+			violations, ok := rules.AsViolations(err)
+			if !ok {
+				return nil, err
+			}
+			applicant.Eligible = false
+			for _, v := range violations {
+				applicant.Ineligible = append(applicant.Ineligible, v.Message)
+			}
+		}
+
+		availability, err := loadAvailability(repos.Availability, worker.ID, now)
+		if err != nil {
+			return nil, err
+		}
+		applicant.AvailabilityConflicts = availabilityConflicts(availability, shift)
+
+		ranking.Applicants = append(ranking.Applicants, applicant)
+	}
+
+	scoreApplicants(ranking.Applicants, cfg.Weights)
+	sortApplicants(ranking.Applicants)
+	return ranking, nil
+}
+
+// sortApplicants orders scored applicants, eligible ones first, then by
+// score, by request time and by request, and numbers their ranks.
+func sortApplicants(applicants []*model.ApplicantScore) {
+	sort.SliceStable(applicants, func(i, j int) bool {
+		a, b := applicants[i], applicants[j]
+		if a.Eligible != b.Eligible {
+			return a.Eligible
+		}
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if !a.RequestedAt.Equal(b.RequestedAt) {
+			return a.RequestedAt.Before(b.RequestedAt)
+		}
+		return a.WorkerShiftID < b.WorkerShiftID
+	})
+	for i, applicant := range applicants {
+		applicant.Rank = i + 1
+	}
+}
+
+// applicantFactor reads one scored quantity of an applicant. Factors where
+// less is better are negated, so a higher value always ranks higher.
+type applicantFactor struct {
+	weight float64
+	value  func(a *model.ApplicantScore) float64
+}
+
+func scoreApplicants(applicants []*model.ApplicantScore, weights config.FairnessWeights) {
+	factors := []applicantFactor{
+		{weights.WeekHours, func(a *model.ApplicantScore) float64 { return -a.WeekHours }},
+		{weights.PeriodHours, func(a *model.ApplicantScore) float64 { return -a.PeriodHours }},
+		{weights.Seniority, func(a *model.ApplicantScore) float64 { return float64(a.SeniorityDays) }},
+		{weights.Cancellations, func(a *model.ApplicantScore) float64 { return -float64(a.Cancellations) }},
+		{weights.NoShows, func(a *model.ApplicantScore) float64 { return -float64(a.NoShows) }},
+		{weights.RequestTime, func(a *model.ApplicantScore) float64 { return -float64(a.RequestedAt.Unix()) }},
+	}
+
+	scores := make([]float64, len(applicants))
+	for _, factor := range factors {
+		if factor.weight == 0 || len(applicants) == 0 {
+			continue
+		}
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for _, a := range applicants {
+			lo = math.Min(lo, factor.value(a))
+			hi = math.Max(hi, factor.value(a))
+		}
+		for i, a := range applicants {
+			scaled := 1.0
+			if hi > lo {
+				scaled = (factor.value(a) - lo) / (hi - lo)
+			}
+			scores[i] += factor.weight * scaled
+		}
+	}
+	for i, a := range applicants {
+		a.Score = math.Round(scores[i]*100) / 100
+	}
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"dailyworkerroster/config"
+	"dailyworkerroster/model"
+)
+
+func TestScoreApplicants(t *testing.T) {
+	requested := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		weights    config.FairnessWeights
+		applicants []model.ApplicantScore
+		want       []float64
+	}{
+		{
+			name:       "scaled between least and most",
+			weights:    config.FairnessWeights{WeekHours: 1},
+			applicants: []model.ApplicantScore{{WeekHours: 0}, {WeekHours: 10}, {WeekHours: 30}},
+			want:       []float64{1, 0.67, 0},
+		},
+		{
+			name:       "shared factor gives full points",
+			weights:    config.FairnessWeights{WeekHours: 3, PeriodHours: 2},
+			applicants: []model.ApplicantScore{{WeekHours: 8, PeriodHours: 40}, {WeekHours: 8, PeriodHours: 40}},
+			want:       []float64{5, 5},
+		},
+		{
+			name:       "single applicant",
+			weights:    config.FairnessWeights{WeekHours: 3, NoShows: 3},
+			applicants: []model.ApplicantScore{{WeekHours: 20, NoShows: 4}},
+			want:       []float64{6},
+		},
+		{
+			name:       "zero weight is ignored",
+			weights:    config.FairnessWeights{NoShows: 2},
+			applicants: []model.ApplicantScore{{SeniorityDays: 900, NoShows: 1}, {SeniorityDays: 1}},
+			want:       []float64{0, 2},
+		},
+		{
+			name:    "weighted sum of factors",
+			weights: config.FairnessWeights{WeekHours: 3, Seniority: 1, Cancellations: 2},
+			applicants: []model.ApplicantScore{
+				{WeekHours: 0, SeniorityDays: 10, Cancellations: 2},
+				{WeekHours: 16, SeniorityDays: 30, Cancellations: 0},
+				{WeekHours: 8, SeniorityDays: 20, Cancellations: 1},
+			},
+			want: []float64{3 + 0 + 0, 0 + 1 + 2, 1.5 + 0.5 + 1},
+		},
+		{
+			name:    "earlier request scores higher",
+			weights: config.FairnessWeights{RequestTime: 1},
+			applicants: []model.ApplicantScore{
+				{RequestedAt: requested.Add(time.Hour)},
+				{RequestedAt: requested},
+			},
+			want: []float64{0, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			applicants := make([]*model.ApplicantScore, len(tt.applicants))
+			for i := range tt.applicants {
+				applicants[i] = &tt.applicants[i]
+			}
+			scoreApplicants(applicants, tt.weights)
+			for i, a := range applicants {
+				if a.Score != tt.want[i] {
+					t.Errorf("applicant %d scored %v, want %v", i, a.Score, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSortApplicants(t *testing.T) {
+	requested := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		applicants []model.ApplicantScore
+		want       []int64 // worker shift IDs, best first
+	}{
+		{
+			name: "by score",
+			applicants: []model.ApplicantScore{
+				{WorkerShiftID: 1, Score: 2, Eligible: true},
+				{WorkerShiftID: 2, Score: 5, Eligible: true},
+				{WorkerShiftID: 3, Score: 3.5, Eligible: true},
+			},
+			want: []int64{2, 3, 1},
+		},
+		{
+			name: "ineligible last whatever their score",
+			applicants: []model.ApplicantScore{
+				{WorkerShiftID: 1, Score: 9, Eligible: false},
+				{WorkerShiftID: 2, Score: 1, Eligible: true},
+				{WorkerShiftID: 3, Score: 0, Eligible: false},
+			},
+			want: []int64{2, 1, 3},
+		},
+		{
+			name: "ties go to the earlier request",
+			applicants: []model.ApplicantScore{
+				{WorkerShiftID: 1, Score: 4, Eligible: true, RequestedAt: requested.Add(time.Minute)},
+				{WorkerShiftID: 2, Score: 4, Eligible: true, RequestedAt: requested},
+			},
+			want: []int64{2, 1},
+		},
+		{
+			name: "then to the lower request ID",
+			applicants: []model.ApplicantScore{
+				{WorkerShiftID: 7, Score: 4, Eligible: true, RequestedAt: requested},
+				{WorkerShiftID: 3, Score: 4, Eligible: true, RequestedAt: requested},
+			},
+			want: []int64{3, 7},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			applicants := make([]*model.ApplicantScore, len(tt.applicants))
+			for i := range tt.applicants {
+				applicants[i] = &tt.applicants[i]
+			}
+			sortApplicants(applicants)
+			for i, a := range applicants {
+				if a.WorkerShiftID != tt.want[i] || a.Rank != i+1 {
+					t.Errorf("rank %d is request %d ranked %d, want request %d", i+1, a.WorkerShiftID, a.Rank, tt.want[i])
+				}
+			}
+		})
+	}
+}
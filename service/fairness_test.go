@@ -0,0 +1,75 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"dailyworkerroster/config"
+	"dailyworkerroster/model"
+	"dailyworkerroster/rules"
+	"dailyworkerroster/service"
+)
+
+func (f *fixture) fairness() service.FairnessServiceItf {
+	return service.NewFairnessService(f.repos.Shift, f.uow, f.cfg.Fairness, rules.NewEngine(f.cfg.Rules), f.clock)
+}
+
+func TestAutoApproveFillsShiftsStartingSoon(t *testing.T) {
+	now := time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC)
+	f := newFixture(t, now)
+	f.cfg.Fairness.AutoApproveBefore = config.Duration(2 * time.Hour)
+	bob := f.user(t, "bob", model.ROLE_WORKER)
+
+	// Starts first, so it is tried first, and fails
+	broken := f.shift(t, now.Add(30*time.Minute), 4*time.Hour)
+	stuck := f.request(t, broken.ID, bob, model.WORKER_SHIFT_PENDING)
+	f.failUpdates(t, broken.ID)
+
+	soon := f.shift(t, now.Add(time.Hour), 4*time.Hour)
+	first := f.request(t, soon.ID, f.worker, model.WORKER_SHIFT_PENDING)
+	second := f.request(t, soon.ID, bob, model.WORKER_SHIFT_PENDING)
+
+	later := f.shift(t, now.Add(5*time.Hour), 4*time.Hour)
+	untouched := f.request(t, later.ID, f.worker, model.WORKER_SHIFT_PENDING)
+
+	result, err := f.fairness().AutoApprove(context.Background())
+	if err != nil {
+		t.Fatalf("AutoApprove: %v", err)
+	}
+	if result.Shifts != 1 || result.Approved != 1 || result.Failed != 1 {
+		t.Errorf("result %+v, want 1 approval on 1 shift and 1 failed", result)
+	}
+	// Equal on every factor, so the earlier request wins
+	if got := f.status(t, first); got != model.WORKER_SHIFT_APPROVED {
+		t.Errorf("top applicant is %s, want APPROVED", got)
+	}
+	if got := f.status(t, second); got != model.WORKER_SHIFT_WAITLISTED {
+		t.Errorf("runner-up is %s, want WAITLISTED", got)
+	}
+	if f.isAvailable(t, soon.ID) {
+		t.Error("filled shift is still open")
+	}
+	if got := f.status(t, stuck); got != model.WORKER_SHIFT_PENDING {
+		t.Errorf("request on the failing shift is %s, want PENDING", got)
+	}
+	if got := f.status(t, untouched); got != model.WORKER_SHIFT_PENDING {
+		t.Errorf("request on a shift starting after the lead is %s, want PENDING", got)
+	}
+}
+
+func TestAutoApproveDisabled(t *testing.T) {
+	now := time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC)
+	f := newFixture(t, now)
+	f.cfg.Fairness.AutoApproveBefore = 0
+	shift := f.shift(t, now.Add(time.Hour), 4*time.Hour)
+	pending := f.request(t, shift.ID, f.worker, model.WORKER_SHIFT_PENDING)
+
+	result, err := f.fairness().AutoApprove(context.Background())
+	if err != nil {
+		t.Fatalf("AutoApprove: %v", err)
+	}
+	if *result != (model.AutoApproveResult{}) || f.status(t, pending) != model.WORKER_SHIFT_PENDING {
+		t.Errorf("result %+v, request %s; want nothing approved", result, f.status(t, pending))
+	}
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"dailyworkerroster/clock"
+	errs "dailyworkerroster/error"
+	"dailyworkerroster/model"
+	"dailyworkerroster/repository"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+type LocationServiceItf interface {
+	CreateLocation(ctx context.Context, location *model.Location) (int64, error)
+	GetLocations(ctx context.Context, active *bool) ([]*model.Location, error)
+	GetLocationByID(ctx context.Context, locationID int64) (*model.Location, error)
+	UpdateLocation(ctx context.Context, location *model.Location) error
+	DeleteLocation(ctx context.Context, locationID int64) error
+}
+
+type LocationService struct {
+	LocationRepo repository.LocationRepoItf
+	UnitOfWork   repository.UnitOfWorkItf
+	Zone         *time.Location // zone of locations without one
+	Clock        clock.Clock
+}
+
+func NewLocationService(
+	locationRepo repository.LocationRepoItf,
+	unitOfWork repository.UnitOfWorkItf,
+	zone *time.Location,
+	clk clock.Clock) LocationServiceItf {
+	return &LocationService{
+		LocationRepo: locationRepo,
+		UnitOfWork:   unitOfWork,
+		Zone:         zone,
+		Clock:        clk,
+	}
+}
+
+func (s *LocationService) CreateLocation(ctx context.Context, location *model.Location) (int64, error) {
+	funcName := "/service/location/CreateLocation"
+
+	if err := normalizeLocation(location); err != nil {
+		return 0, err
+	}
+
+	var id int64
+	err := s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		if _, err := repos.Location.GetLocationByName(location.Name); err == nil {
+			return errs.ErrLocationExists
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("%s: GetLocationByName error: %v", funcName, err)
+			return err
+		}
+
+		var err error
+		id, err = repos.Location.CreateLocation(location)
+		if err != nil {
+			log.Printf("%s: CreateLocation error: %v", funcName, err)
+			return err
+		}
+		location.ID = id
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (s *LocationService) GetLocations(ctx context.Context, active *bool) ([]*model.Location, error) {
+	funcName := "/service/location/GetLocations"
+
+	locations, err := s.LocationRepo.ListLocations(active)
+	if err != nil {
+		log.Printf("%s: ListLocations error: %v", funcName, err)
+		return nil, err
+	}
+	return locations, nil
+}
+
+func (s *LocationService) GetLocationByID(ctx context.Context, locationID int64) (*model.Location, error) {
+	funcName := "/service/location/GetLocationByID"
+
+	location, err := s.LocationRepo.GetLocationByID(locationID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errs.ErrLocationNotFound
+	}
+	if err != nil {
+		log.Printf("%s: GetLocationByID error: %v", funcName, err)
+		return nil, err
+	}
+	return location, nil
+}
+
+// UpdateLocation saves the location. Shifts keep their wall clock times, so
+// when the time zone changes the instants of its upcoming shifts move with it.
+func (s *LocationService) UpdateLocation(ctx context.Context, location *model.Location) error {
+	funcName := "/service/location/UpdateLocation"
+
+	if err := normalizeLocation(location); err != nil {
+		return err
+	}
+
+	return s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		current, err := repos.Location.GetLocationByIDForUpdate(location.ID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return errs.ErrLocationNotFound
+		}
+		if err != nil {
+			log.Printf("%s: GetLocationByIDForUpdate error: %v", funcName, err)
+			return err
+		}
+		if other, err := repos.Location.GetLocationByName(location.Name); err == nil && other.ID != location.ID {
+			return errs.ErrLocationExists
+		} else if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("%s: GetLocationByName error: %v", funcName, err)
+			return err
+		}
+
+		if err := repos.Location.UpdateLocation(location); err != nil {
+			log.Printf("%s: UpdateLocation error: %v", funcName, err)
+			return err
+		}
+		if location.TimeZone == current.TimeZone {
+			return nil
+		}
+		return reanchorLocationShifts(repos, location, s.Zone, s.Clock.Now())
+	})
+}
+
+// DeleteLocation removes a location no shift or template refers to. Used
+// locations can be deactivated instead.
+func (s *LocationService) DeleteLocation(ctx context.Context, locationID int64) error {
+	funcName := "/service/location/DeleteLocation"
+
+	return s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		if _, err := repos.Location.GetLocationByIDForUpdate(locationID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errs.ErrLocationNotFound
+			}
+			log.Printf("%s: GetLocationByIDForUpdate error: %v", funcName, err)
+			return err
+		}
+
+		inUse, err := repos.Location.IsLocationInUse(locationID)
+		if err != nil {
+			log.Printf("%s: IsLocationInUse error: %v", funcName, err)
+			return err
+		}
+		if inUse {
+			return errs.ErrLocationInUse
+		}
+
+		if err := repos.Location.DeleteLocation(locationID); err != nil {
+			log.Printf("%s: DeleteLocation error: %v", funcName, err)
+			return err
+		}
+		return nil
+	})
+}
+
+func normalizeLocation(location *model.Location) error {
+	location.Name = strings.TrimSpace(location.Name)
+	if location.Name == "" {
+		return fmt.Errorf("%w: name is required", errs.ErrInvalidLocation)
+	}
+	location.Address = strings.TrimSpace(location.Address)
+	location.TimeZone = strings.TrimSpace(location.TimeZone)
+	if _, err := clock.LoadLocation(location.TimeZone, time.UTC); err != nil {
+		return fmt.Errorf("%w: unknown time zone %q", errs.ErrInvalidLocation, location.TimeZone)
+	}
+	if (location.Latitude == nil) != (location.Longitude == nil) {
+		return fmt.Errorf("%w: latitude and longitude must be given together", errs.ErrInvalidLocation)
+	}
+	if location.Latitude != nil && (*location.Latitude < -90 || *location.Latitude > 90) {
+		return fmt.Errorf("%w: latitude must be between -90 and 90", errs.ErrInvalidLocation)
+	}
+	if location.Longitude != nil && (*location.Longitude < -180 || *location.Longitude > 180) {
+		return fmt.Errorf("%w: longitude must be between -180 and 180", errs.ErrInvalidLocation)
+	}
+	return nil
+}
+
+// reanchorLocationShifts recomputes the instants of the location's shifts
+// that have not started yet from their date and clock times in its zone.
+func reanchorLocationShifts(repos *repository.Repositories, location *model.Location, defaultZone *time.Location, now time.Time) error {
+	loc, err := clock.LoadLocation(location.TimeZone, defaultZone)
+	if err != nil {
+		return err
+	}
+
+	// A day of slack covers every zone offset
+	from := now.AddDate(0, 0, -1).Format(dateLayout)
+	shifts, err := repos.Shift.GetListShifts(model.ShiftListQuery{LocationID: location.ID, DateFrom: from})
+	if err != nil {
+		return err
+	}
+	for _, shift := range shifts {
+		if !shift.StartAt.After(now) {
+			continue
+		}
+		shift.StartAt, shift.EndAt = time.Time{}, time.Time{}
+		if err := normalizeShiftTimes(shift, loc); err != nil {
+			return err
+		}
+		if !shift.StartAt.After(now) {
+			continue
+		}
+		if err := repos.Shift.UpdateShiftByID(shift); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveLocation finds the location a shift or template refers to, by ID
+// or, without one, by name. Only the location it is at already, currentID,
+// may be inactive.
+func resolveLocation(locations repository.LocationRepoItf, id int64, name string, currentID int64) (*model.Location, error) {
+	var location *model.Location
+	var err error
+	switch {
+	case id != 0:
+		location, err = locations.GetLocationByID(id)
+	case strings.TrimSpace(name) != "":
+		location, err = locations.GetLocationByName(strings.TrimSpace(name))
+	default:
+		return nil, fmt.Errorf("%w: location_id is required", errs.ErrInvalidLocation)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: no such location", errs.ErrInvalidLocation)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !location.Active && location.ID != currentID {
+		return nil, fmt.Errorf("%w: location %q is inactive", errs.ErrInvalidLocation, location.Name)
+	}
+	return location, nil
+}
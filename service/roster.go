@@ -0,0 +1,502 @@
+package service
+
+import (
+	"context"
+	"dailyworkerroster/clock"
+	errs "dailyworkerroster/error"
+	"dailyworkerroster/model"
+	"dailyworkerroster/repository"
+	"dailyworkerroster/rules"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+)
+
+// maxRosterDays is the longest range a roster proposal covers
+const maxRosterDays = 31
+
+type RosterServiceItf interface {
+	ProposeRoster(ctx context.Context, query model.RosterQuery) (*model.RosterProposal, error)
+	CommitRoster(ctx context.Context, workerShiftIDs []int64) ([]*model.RosterChange, error)
+}
+
+type RosterService struct {
+	UnitOfWork repository.UnitOfWorkItf
+	Rules      *rules.Engine
+	Clock      clock.Clock
+}
+
+func NewRosterService(unitOfWork repository.UnitOfWorkItf, engine *rules.Engine, clk clock.Clock) RosterServiceItf {
+	return &RosterService{
+		UnitOfWork: unitOfWork,
+		Rules:      engine,
+		Clock:      clk,
+	}
+}
+
+// ProposeRoster picks, among the pending requests for the open shifts in
+// the range, approvals that fill as many places as it can find. It is a
+// heuristic: a greedy fill improved by single moves of a worker between
+// shifts, which may miss the best roster when only a chain of moves frees
+// a place. Every approval passes the labour rules together with the others,
+// and requests clashing with the worker's availability are left out.
+// Nothing is saved.
+func (s *RosterService) ProposeRoster(ctx context.Context, query model.RosterQuery) (*model.RosterProposal, error) {
+	funcName := "/service/roster/ProposeRoster"
+
+	from, err := time.Parse(dateLayout, query.DateFrom)
+	if err != nil {
+		return nil, fmt.Errorf("%w: from must be YYYY-MM-DD", errs.ErrInvalidRoster)
+	}
+	to, err := time.Parse(dateLayout, query.DateTo)
+	if err != nil {
+		return nil, fmt.Errorf("%w: to must be YYYY-MM-DD", errs.ErrInvalidRoster)
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: to is before from", errs.ErrInvalidRoster)
+	}
+	if to.Sub(from) >= maxRosterDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: the range is longer than %d days", errs.ErrInvalidRoster, maxRosterDays)
+	}
+
+	var proposal *model.RosterProposal
+	// The transaction only gives the solver a consistent view
+	err = s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		r, err := loadRoster(repos, query, from, to, s.Clock.Now())
+		if err != nil {
+			log.Printf("%s: loadRoster error: %v", funcName, err)
+			return err
+		}
+		r.solve(s.Rules)
+		proposal = r.proposal(s.Rules)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	proposal.DateFrom = query.DateFrom
+	proposal.DateTo = query.DateTo
+	return proposal, nil
+}
+
+// CommitRoster approves the given pending requests in one transaction, as
+// proposed by ProposeRoster. Each approval is checked again, and when one
+// fails, because the request was decided meanwhile or breaks a rule, none
+// is saved.
+func (s *RosterService) CommitRoster(ctx context.Context, workerShiftIDs []int64) ([]*model.RosterChange, error) {
+	funcName := "/service/roster/CommitRoster"
+
+	if len(workerShiftIDs) == 0 {
+		return nil, fmt.Errorf("%w: no request to approve", errs.ErrInvalidRoster)
+	}
+
+	changes := make([]*model.RosterChange, 0, len(workerShiftIDs))
+	err := s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		seen := make(map[int64]bool)
+		for _, id := range workerShiftIDs {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			ws, err := repos.WorkerShift.GetWorkerShiftByIDForUpdate(id)
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%w: request %d", errs.ErrWorkerShiftNotFound, id)
+			}
+			if err != nil {
+				log.Printf("%s: GetWorkerShiftByIDForUpdate error: %v", funcName, err)
+				return err
+			}
+			if ws.Status != model.WORKER_SHIFT_PENDING {
+				return fmt.Errorf("%w: request %d is %s", errs.ErrRosterStale, id, ws.Status)
+			}
+
+			shift, waitlisted, err := approvePendingRequest(ctx, repos, s.Rules, ws.ShiftID, ws.UserAccountID, s.Clock.Now())
+			if err != nil {
+				return fmt.Errorf("request %d: %w", id, err)
+			}
+
+			for _, decided := range append([]*model.WorkerShift{ws}, waitlisted...) {
+				worker, err := repos.User.GetUserByID(decided.UserAccountID)
+				if err != nil {
+					log.Printf("%s: GetUserByID error: %v", funcName, err)
+					return err
+				}
+				to := model.WORKER_SHIFT_WAITLISTED
+				if decided.ID == ws.ID {
+					to = model.WORKER_SHIFT_APPROVED
+				}
+				changes = append(changes, newRosterChange(decided, shift, worker, to))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+func newRosterChange(ws *model.WorkerShift, shift *model.Shift, worker *model.User, to string) *model.RosterChange {
+	return &model.RosterChange{
+		WorkerShiftID:  ws.ID,
+		ShiftID:        shift.ID,
+		UserAccountID:  worker.ID,
+		WorkerName:     worker.Name,
+		Date:           shift.Date,
+		StartTime:      shift.StartTime,
+		EndTime:        shift.EndTime,
+		RoleAssignment: shift.RoleAssignment,
+		Location:       shift.Location,
+		From:           model.WORKER_SHIFT_PENDING,
+		To:             to,
+	}
+}
+
+// roster is the working state of the solver: the open shifts with their
+// pending requests, and the workers who made them.
+type roster struct {
+	slots   []*rosterSlot
+	workers map[int64]*rosterWorker
+}
+
+type rosterSlot struct {
+	shift      *model.Shift
+	filled     int // approved before the proposal
+	candidates []*rosterCandidate
+	assigned   []*rosterCandidate
+}
+
+// open is the number of places still free with the proposal so far
+func (s *rosterSlot) open() int {
+	return s.shift.Headcount - s.filled - len(s.assigned)
+}
+
+// ranked returns the requests that may be approved, from workers with the
+// fewest requests in the range first, as they have the fewest alternatives.
+func (s *rosterSlot) ranked() []*rosterCandidate {
+	list := make([]*rosterCandidate, 0, len(s.candidates))
+	for _, c := range s.candidates {
+		if len(c.excluded) == 0 {
+			list = append(list, c)
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].worker.requests < list[j].worker.requests
+	})
+	return list
+}
+
+type rosterCandidate struct {
+	request  *model.WorkerShift
+	worker   *rosterWorker
+	slot     *rosterSlot
+	excluded []string // why the request cannot be approved, whatever else is proposed
+	assigned bool
+}
+
+type rosterWorker struct {
+	user         *model.User
+	skills       []*model.WorkerSkill
+	timeOff      []*model.TimeOff
+	availability *model.WorkerAvailability
+	booked       []*model.Shift // approved and done shifts around the range
+	proposed     []*model.Shift
+	requests     int // pending requests in the range
+}
+
+// violations runs the labour rules for the shift against the worker's booked
+// shifts and those proposed to them.
+func (w *rosterWorker) violations(engine *rules.Engine, shift *model.Shift) []rules.Violation {
+	assigned := make([]*model.Shift, 0, len(w.booked)+len(w.proposed))
+	assigned = append(assigned, w.booked...)
+	for _, other := range w.proposed {
+		if other.ID != shift.ID {
+			assigned = append(assigned, other)
+		}
+	}
+	return engine.Evaluate(&rules.Input{
+		Worker:   w.user,
+		Skills:   w.skills,
+		TimeOff:  w.timeOff,
+		Shift:    shift,
+		Assigned: assigned,
+	})
+}
+
+func (w *rosterWorker) fits(engine *rules.Engine, shift *model.Shift) bool {
+	return len(w.violations(engine, shift)) == 0
+}
+
+// loadRoster reads the open shifts in the range that have not started and
+// still have free places, with their pending requests.
+func loadRoster(repos *repository.Repositories, query model.RosterQuery, from, to, now time.Time) (*roster, error) {
+	isAvailable := true
+	listQuery := model.ShiftListQuery{IsAvailable: &isAvailable, DateFrom: query.DateFrom, DateTo: query.DateTo}
+	if query.LocationID != nil {
+		listQuery.LocationID = *query.LocationID
+	}
+	shifts, err := repos.Shift.GetListShifts(listQuery)
+	if err != nil {
+		return nil, err
+	}
+	shiftIDs := make([]int64, 0, len(shifts))
+	for _, shift := range shifts {
+		shiftIDs = append(shiftIDs, shift.ID)
+	}
+	filled, err := repos.WorkerShift.CountWorkerShiftsByShiftIDs(shiftIDs, model.WORKER_SHIFT_APPROVED)
+	if err != nil {
+		return nil, err
+	}
+	// Places offered to the waitlist are kept for it
+	offered, err := repos.WorkerShift.CountWorkerShiftsByShiftIDs(shiftIDs, model.WORKER_SHIFT_OFFERED)
+	if err != nil {
+		return nil, err
+	}
+	for shiftID, n := range offered {
+		filled[shiftID] += n
+	}
+
+	r := &roster{workers: make(map[int64]*rosterWorker)}
+	for _, shift := range shifts {
+		slot := &rosterSlot{shift: shift, filled: filled[shift.ID]}
+		// Requests for shifts that have started expire instead
+		if !shift.StartAt.After(now) || slot.open() <= 0 {
+			continue
+		}
+
+		requests, err := repos.WorkerShift.ListWorkerShiftsByShift(shift.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, ws := range requests {
+			if ws.Status != model.WORKER_SHIFT_PENDING {
+				continue
+			}
+			worker, err := r.loadWorker(repos, ws.UserAccountID, from, to, now)
+			if err != nil {
+				return nil, err
+			}
+			worker.requests++
+			slot.candidates = append(slot.candidates, &rosterCandidate{
+				request:  ws,
+				worker:   worker,
+				slot:     slot,
+				excluded: availabilityConflicts(worker.availability, shift),
+			})
+		}
+		r.slots = append(r.slots, slot)
+	}
+	return r, nil
+}
+
+func (r *roster) loadWorker(repos *repository.Repositories, workerID int64, from, to, now time.Time) (*rosterWorker, error) {
+	if worker, ok := r.workers[workerID]; ok {
+		return worker, nil
+	}
+
+	user, err := repos.User.GetUserByID(workerID)
+	if err != nil {
+		return nil, err
+	}
+	worker := &rosterWorker{user: user}
+	if worker.skills, err = repos.WorkerSkill.ListWorkerSkills(workerID); err != nil {
+		return nil, err
+	}
+	approved := model.TIME_OFF_APPROVED
+	leaveFrom := from.AddDate(0, 0, -1).Format(dateLayout)
+	leaveTo := to.AddDate(0, 0, 1).Format(dateLayout)
+	worker.timeOff, err = repos.TimeOff.ListTimeOff(model.TimeOffQuery{
+		UserAccountID: &workerID,
+		Status:        &approved,
+		DateFrom:      &leaveFrom,
+		DateTo:        &leaveTo,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if worker.availability, err = loadAvailability(repos.Availability, workerID, now); err != nil {
+		return nil, err
+	}
+
+	dateFrom := from.AddDate(0, 0, -rules.LookbackDays).Format(dateLayout)
+	dateTo := to.AddDate(0, 0, rules.LookbackDays).Format(dateLayout)
+	for _, status := range []string{model.WORKER_SHIFT_APPROVED, model.WORKER_SHIFT_DONE} {
+		status := status
+		booked, err := repos.WorkerShift.GetWorkerShiftDetailListByFilter(&model.WorkerShiftDetailQuery{
+			UserAccountID: &workerID,
+			Status:        &status,
+			DateFrom:      &dateFrom,
+			DateTo:        &dateTo,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for i := range booked {
+			worker.booked = append(worker.booked, booked[i].AsShift())
+		}
+	}
+
+	r.workers[workerID] = worker
+	return worker, nil
+}
+
+// solve builds the proposal. Requests breaking the rules on their own are
+// excluded first. The shifts with the fewest candidates are then filled
+// greedily, after which places are freed up by moving workers between the
+// shifts they asked for, until no move fills another place.
+func (r *roster) solve(engine *rules.Engine) {
+	for _, slot := range r.slots {
+		for _, c := range slot.candidates {
+			if len(c.excluded) > 0 {
+				continue
+			}
+			for _, v := range c.worker.violations(engine, slot.shift) {
+				c.excluded = append(c.excluded, v.Message)
+			}
+		}
+	}
+
+	order := make([]*rosterSlot, len(r.slots))
+	copy(order, r.slots)
+	sort.SliceStable(order, func(i, j int) bool {
+		return len(order[i].ranked()) < len(order[j].ranked())
+	})
+
+	for {
+		for _, slot := range order {
+			for _, c := range slot.ranked() {
+				if slot.open() == 0 {
+					break
+				}
+				if !c.assigned && c.worker.fits(engine, slot.shift) {
+					r.assign(c)
+				}
+			}
+		}
+		if !r.improve(engine) {
+			return
+		}
+	}
+}
+
+// improve fills one more place: a worker proposed for shift P who asked for
+// an open shift S moves to S, and another candidate of P takes their place.
+// It reports whether it found such a move. Each move fills a place, so
+// repeating it ends.
+func (r *roster) improve(engine *rules.Engine) bool {
+	for _, slot := range r.slots {
+		if slot.open() == 0 {
+			continue
+		}
+		for _, c := range slot.ranked() {
+			if c.assigned {
+				continue
+			}
+			for _, held := range r.assignmentsOf(c.worker) {
+				r.unassign(held)
+				if c.worker.fits(engine, slot.shift) {
+					r.assign(c)
+					for _, other := range held.slot.ranked() {
+						if !other.assigned && other.worker != c.worker && other.worker.fits(engine, held.slot.shift) {
+							r.assign(other)
+							return true
+						}
+					}
+					r.unassign(c)
+				}
+				r.assign(held)
+			}
+		}
+	}
+	return false
+}
+
+func (r *roster) assignmentsOf(worker *rosterWorker) []*rosterCandidate {
+	var list []*rosterCandidate
+	for _, slot := range r.slots {
+		for _, c := range slot.assigned {
+			if c.worker == worker {
+				list = append(list, c)
+			}
+		}
+	}
+	return list
+}
+
+func (r *roster) assign(c *rosterCandidate) {
+	c.assigned = true
+	c.slot.assigned = append(c.slot.assigned, c)
+	c.worker.proposed = append(c.worker.proposed, c.slot.shift)
+}
+
+func (r *roster) unassign(c *rosterCandidate) {
+	c.assigned = false
+	for i, other := range c.slot.assigned {
+		if other == c {
+			c.slot.assigned = append(c.slot.assigned[:i], c.slot.assigned[i+1:]...)
+			break
+		}
+	}
+	for i, shift := range c.worker.proposed {
+		if shift.ID == c.slot.shift.ID {
+			c.worker.proposed = append(c.worker.proposed[:i], c.worker.proposed[i+1:]...)
+			break
+		}
+	}
+}
+
+// proposal lists the outcome per request, in shift order: approved,
+// waitlisted because the shift fills up as it does on approval, or left
+// pending with the reasons it could not be approved.
+func (r *roster) proposal(engine *rules.Engine) *model.RosterProposal {
+	p := &model.RosterProposal{
+		Approve:    make([]int64, 0),
+		Changes:    make([]*model.RosterChange, 0),
+		Unassigned: make([]*model.RosterChange, 0),
+		Shifts:     make([]*model.RosterShift, 0, len(r.slots)),
+	}
+	for _, slot := range r.slots {
+		shift := slot.shift
+		p.Open += shift.Headcount - slot.filled
+		p.Filled += len(slot.assigned)
+
+		for _, c := range slot.candidates {
+			change := newRosterChange(c.request, shift, c.worker.user, model.WORKER_SHIFT_PENDING)
+			switch {
+			case c.assigned:
+				change.To = model.WORKER_SHIFT_APPROVED
+				p.Approve = append(p.Approve, c.request.ID)
+				p.Changes = append(p.Changes, change)
+			case slot.open() == 0:
+				change.To = model.WORKER_SHIFT_WAITLISTED
+				change.Reasons = []string{"shift is full"}
+				p.Changes = append(p.Changes, change)
+			default:
+				change.Reasons = c.excluded
+				if len(change.Reasons) == 0 {
+					for _, v := range c.worker.violations(engine, shift) {
+						change.Reasons = append(change.Reasons, v.Message)
+					}
+				}
+				p.Unassigned = append(p.Unassigned, change)
+			}
+		}
+
+		p.Shifts = append(p.Shifts, &model.RosterShift{
+			ShiftID:        shift.ID,
+			Date:           shift.Date,
+			StartTime:      shift.StartTime,
+			EndTime:        shift.EndTime,
+			RoleAssignment: shift.RoleAssignment,
+			Location:       shift.Location,
+			Headcount:      shift.Headcount,
+			FilledBefore:   slot.filled,
+			FilledAfter:    slot.filled + len(slot.assigned),
+		})
+	}
+	return p
+}
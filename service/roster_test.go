@@ -0,0 +1,156 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	errs "dailyworkerroster/error"
+	"dailyworkerroster/model"
+	"dailyworkerroster/rules"
+	"dailyworkerroster/service"
+)
+
+func (f *fixture) roster() service.RosterServiceItf {
+	return service.NewRosterService(f.uow, rules.NewEngine(f.cfg.Rules), f.clock)
+}
+
+func sortedIDs(ids []int64) []int64 {
+	sorted := append([]int64(nil), ids...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
+
+func equalIDs(a, b []int64) bool {
+	a, b = sortedIDs(a), sortedIDs(b)
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestProposeRosterMovesWorkerToFillMorePlaces(t *testing.T) {
+	now := time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC)
+	f := newFixture(t, now)
+	bob := f.user(t, "bob", model.ROLE_WORKER)
+	carol := f.user(t, "carol", model.ROLE_WORKER)
+
+	// a and b overlap, as do b and d; e is days later
+	a := f.shift(t, time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC), 4*time.Hour)
+	b := f.shift(t, time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC), 4*time.Hour)
+	d := f.shift(t, time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC), 4*time.Hour)
+	e := f.shift(t, time.Date(2026, 10, 23, 8, 0, 0, 0, time.UTC), 4*time.Hour)
+	annA := f.request(t, a.ID, f.worker, model.WORKER_SHIFT_PENDING)
+	bobA := f.request(t, a.ID, bob, model.WORKER_SHIFT_PENDING)
+	annB := f.request(t, b.ID, f.worker, model.WORKER_SHIFT_PENDING)
+	carolB := f.request(t, b.ID, carol, model.WORKER_SHIFT_PENDING)
+	carolD := f.request(t, d.ID, carol, model.WORKER_SHIFT_PENDING)
+	bobE := f.request(t, e.ID, bob, model.WORKER_SHIFT_PENDING)
+
+	// Filling d and e first, the greedy pass gives a to ann, which leaves
+	// nobody for b; moving ann to b frees a for bob
+	proposal, err := f.roster().ProposeRoster(context.Background(), model.RosterQuery{DateFrom: "2026-10-19", DateTo: "2026-10-23"})
+	if err != nil {
+		t.Fatalf("ProposeRoster: %v", err)
+	}
+	if proposal.Open != 4 || proposal.Filled != 4 {
+		t.Errorf("proposal fills %d of %d places, want 4 of 4", proposal.Filled, proposal.Open)
+	}
+	if want := []int64{bobA, annB, carolD, bobE}; !equalIDs(proposal.Approve, want) {
+		t.Errorf("approve %v, want %v", proposal.Approve, want)
+	}
+	for _, change := range proposal.Changes {
+		if (change.WorkerShiftID == annA || change.WorkerShiftID == carolB) && change.To != model.WORKER_SHIFT_WAITLISTED {
+			t.Errorf("request %d goes to %s, want WAITLISTED", change.WorkerShiftID, change.To)
+		}
+	}
+}
+
+func TestProposeRosterLeavesRuleViolationPending(t *testing.T) {
+	now := time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC)
+	f := newFixture(t, now)
+	booked := f.shift(t, time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC), 8*time.Hour)
+	f.request(t, booked.ID, f.worker, model.WORKER_SHIFT_APPROVED)
+	clash := f.shift(t, time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC), 4*time.Hour)
+	pending := f.request(t, clash.ID, f.worker, model.WORKER_SHIFT_PENDING)
+
+	proposal, err := f.roster().ProposeRoster(context.Background(), model.RosterQuery{DateFrom: "2026-10-19", DateTo: "2026-10-19"})
+	if err != nil {
+		t.Fatalf("ProposeRoster: %v", err)
+	}
+	if len(proposal.Approve) != 0 || proposal.Filled != 0 {
+		t.Errorf("proposal approves %v, want nothing", proposal.Approve)
+	}
+	if len(proposal.Unassigned) != 1 || proposal.Unassigned[0].WorkerShiftID != pending {
+		t.Fatalf("unassigned %+v, want request %d", proposal.Unassigned, pending)
+	}
+	if len(proposal.Unassigned[0].Reasons) == 0 {
+		t.Error("request left pending without a reason")
+	}
+}
+
+func TestCommitRosterApprovesProposal(t *testing.T) {
+	now := time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC)
+	f := newFixture(t, now)
+	bob := f.user(t, "bob", model.ROLE_WORKER)
+	shift := f.shift(t, time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC), 4*time.Hour)
+	first := f.request(t, shift.ID, f.worker, model.WORKER_SHIFT_PENDING)
+	second := f.request(t, shift.ID, bob, model.WORKER_SHIFT_PENDING)
+
+	changes, err := f.roster().CommitRoster(context.Background(), []int64{first})
+	if err != nil {
+		t.Fatalf("CommitRoster: %v", err)
+	}
+	if len(changes) != 2 {
+		t.Errorf("got %d changes, want the approval and the waitlisting", len(changes))
+	}
+	if got := f.status(t, first); got != model.WORKER_SHIFT_APPROVED {
+		t.Errorf("committed request is %s, want APPROVED", got)
+	}
+	if got := f.status(t, second); got != model.WORKER_SHIFT_WAITLISTED {
+		t.Errorf("other request is %s, want WAITLISTED", got)
+	}
+	if f.isAvailable(t, shift.ID) {
+		t.Error("filled shift is still open")
+	}
+}
+
+func TestCommitRosterSavesNothingOnFailure(t *testing.T) {
+	now := time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC)
+	f := newFixture(t, now)
+	bob := f.user(t, "bob", model.ROLE_WORKER)
+	a := f.shift(t, time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC), 4*time.Hour)
+	b := f.shift(t, time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC), 4*time.Hour)
+	c := f.shift(t, time.Date(2026, 10, 20, 8, 0, 0, 0, time.UTC), 4*time.Hour)
+	annA := f.request(t, a.ID, f.worker, model.WORKER_SHIFT_PENDING)
+	annB := f.request(t, b.ID, f.worker, model.WORKER_SHIFT_PENDING)
+	rejected := f.request(t, c.ID, bob, model.WORKER_SHIFT_REJECTED)
+
+	// The second approval overlaps the first
+	_, err := f.roster().CommitRoster(context.Background(), []int64{annA, annB})
+	if !errors.Is(err, errs.ErrRuleViolation) {
+		t.Errorf("CommitRoster of overlapping shifts: err = %v, want ErrRuleViolation", err)
+	}
+	if got := f.status(t, annA); got != model.WORKER_SHIFT_PENDING {
+		t.Errorf("first request is %s, want it rolled back to PENDING", got)
+	}
+
+	// Decided since the proposal
+	_, err = f.roster().CommitRoster(context.Background(), []int64{annA, rejected})
+	if !errors.Is(err, errs.ErrRosterStale) {
+		t.Errorf("CommitRoster of a rejected request: err = %v, want ErrRosterStale", err)
+	}
+	if got := f.status(t, annA); got != model.WORKER_SHIFT_PENDING {
+		t.Errorf("first request is %s, want it rolled back to PENDING", got)
+	}
+	if !f.isAvailable(t, a.ID) {
+		t.Error("shift closed by a failed commit")
+	}
+}
@@ -2,12 +2,19 @@ package service
 
 import (
 	"context"
+	"dailyworkerroster/auth"
+	"dailyworkerroster/clock"
+	"dailyworkerroster/config"
+	errs "dailyworkerroster/error"
 	"dailyworkerroster/model"
 	"dailyworkerroster/repository"
+	"dailyworkerroster/rules"
+	"database/sql"
+	"errors"
 	"fmt"
 	"log"
-
-	"github.com/spf13/cast"
+	"sort"
+	"time"
 )
 
 type ShiftServiceItf interface {
@@ -16,37 +23,71 @@ type ShiftServiceItf interface {
 	GetAvailableShifts(ctx context.Context, workerID int64) ([]*model.ShiftStatus, error)
 	RequestShift(ctx context.Context, shiftID, workerID int64) error
 	GetAllRequestedShift(ctx context.Context, workerID int64) ([]*model.ShiftStatus, error)
+	WithdrawShiftRequest(ctx context.Context, shiftID, workerID int64) error
+	CancelShift(ctx context.Context, shiftID, workerID int64) error
+	AcceptOffer(ctx context.Context, shiftID, workerID int64) error
+	DeclineOffer(ctx context.Context, shiftID, workerID int64) error
+	GetWorkerHours(ctx context.Context, workerID int64) (*model.WorkerHours, error)
 
 	// // Admin
 	CreateShift(ctx context.Context, shift *model.Shift) (int64, error)
-	UpdateShift(ctx context.Context, shift *model.Shift) error
+	UpdateShift(ctx context.Context, shift *model.Shift, isAvailable *bool) error
 	DeleteShift(ctx context.Context, shiftID int64) error
 	GetAllShiftRequests(ctx context.Context, queryParam model.WorkerShiftDetailQuery) ([]model.WorkerShiftDetail, error)
-	ApproveShiftRequest(ctx context.Context, shiftID, workerID int64) error
+	ApproveShiftRequest(ctx context.Context, shiftID, workerID int64) ([]string, error)
 	RejectShiftRequest(ctx context.Context, shiftID, workerID int64) error
+	MarkNoShow(ctx context.Context, workerShiftID int64) error
 	GetShiftsByDay(ctx context.Context, date string) ([]*model.ShiftStatus, error)
 }
 
 type ShiftService struct {
-	ShiftRepo       repository.ShiftRepoItf
-	WorkerShiftRepo repository.WorkerShiftRepoItf
+	ShiftRepo        repository.ShiftRepoItf
+	WorkerShiftRepo  repository.WorkerShiftRepoItf
+	LocationRepo     repository.LocationRepoItf
+	SkillRepo        repository.SkillRepoItf
+	AvailabilityRepo repository.AvailabilityRepoItf
+	UnitOfWork       repository.UnitOfWorkItf
+	Config           config.ShiftConfig
+	Rules            *rules.Engine
+	Zone             *time.Location // zone of locations without one
+	Clock            clock.Clock
 }
 
 func NewShiftService(
 	shiftRepo repository.ShiftRepoItf,
-	workerShiftRepo repository.WorkerShiftRepoItf) ShiftServiceItf {
+	workerShiftRepo repository.WorkerShiftRepoItf,
+	locationRepo repository.LocationRepoItf,
+	skillRepo repository.SkillRepoItf,
+	availabilityRepo repository.AvailabilityRepoItf,
+	unitOfWork repository.UnitOfWorkItf,
+	cfg config.ShiftConfig,
+	engine *rules.Engine,
+	zone *time.Location,
+	clk clock.Clock) ShiftServiceItf {
 	return &ShiftService{
-		ShiftRepo:       shiftRepo,
-		WorkerShiftRepo: workerShiftRepo,
+		ShiftRepo:        shiftRepo,
+		WorkerShiftRepo:  workerShiftRepo,
+		LocationRepo:     locationRepo,
+		SkillRepo:        skillRepo,
+		AvailabilityRepo: availabilityRepo,
+		UnitOfWork:       unitOfWork,
+		Config:           cfg,
+		Rules:            engine,
+		Zone:             zone,
+		Clock:            clk,
 	}
 }
 
 func (s *ShiftService) GetAssignedShifts(ctx context.Context) (*model.ListShiftDetail, error) {
 	funcName := "/service/shift/GetAssignedShifts"
 
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("no authenticated user")
+	}
 	listShiftResp := &model.ListShiftDetail{
-		UserAccountID: cast.ToInt64(ctx.Value("user_account_id")),
-		Name:          cast.ToString(ctx.Value("name")),
+		UserAccountID: principal.UserID,
+		Name:          principal.Name,
 	}
 
 	shiftList := make([]model.WorkerShiftDetail, 0)
@@ -79,8 +120,11 @@ func (s *ShiftService) GetAssignedShifts(ctx context.Context) (*model.ListShiftD
 			StartTime:      s.StartTime,
 			EndTime:        s.EndTime,
 			RoleAssignment: s.RoleAssignment,
+			LocationID:     s.LocationID,
 			Location:       s.Location,
 			IsAvailable:    s.IsAvailable,
+			Headcount:      s.Headcount,
+			UserAccountID:  listShiftResp.UserAccountID,
 		}
 
 		if workerShift, ok := workerShiftMap[s.ID]; ok {
@@ -146,102 +190,468 @@ func (s *ShiftService) GetAvailableShifts(ctx context.Context, workerID int64) (
 		shiftStatusMap[shift.ShiftID] = shift.Status
 	}
 
+	filledByShift, err := s.countApproved(availableShift)
+	if err != nil {
+		log.Printf("%s: countApproved error: %v", funcName, err)
+		return nil, err
+	}
+
+	now := s.Clock.Now()
+	availability, err := loadAvailability(s.AvailabilityRepo, workerID, now)
+	if err != nil {
+		log.Printf("%s: loadAvailability error: %v", funcName, err)
+		return nil, err
+	}
+
 	for _, shift := range availableShift {
-		shiftStatus := &model.ShiftStatus{
-			ID:             shift.ID,
-			Date:           shift.Date,
-			StartTime:      shift.StartTime,
-			EndTime:        shift.EndTime,
-			RoleAssignment: shift.RoleAssignment,
-			Location:       shift.Location,
-			IsAvailable:    shift.IsAvailable,
+		// Started shifts can no longer be requested
+		if !now.Before(shift.StartAt) {
+			continue
 		}
+		shiftStatus := newShiftStatus(shift, filledByShift[shift.ID])
 
 		if status, ok := shiftStatusMap[shift.ID]; ok {
 			shiftStatus.StatusWorker = status
+		} else if len(availabilityConflicts(availability, shift)) > 0 {
+			// Shifts the worker cannot work are left out, unless they asked for it already
+			continue
 		} else {
 			shiftStatus.StatusWorker = ""
 		}
+		shiftStatus.PreferenceScore = preferenceScore(availability, shift)
 
 		availableShiftStatus = append(availableShiftStatus, shiftStatus)
 	}
 
+	// Best matches first, in date order otherwise
+	sort.SliceStable(availableShiftStatus, func(i, j int) bool {
+		return availableShiftStatus[i].PreferenceScore > availableShiftStatus[j].PreferenceScore
+	})
+
 	return availableShiftStatus, nil
 }
 
 func (s *ShiftService) RequestShift(ctx context.Context, shiftID, workerID int64) error {
 	funcName := "/service/shift/RequestShift"
 
-	shift, err := s.ShiftRepo.GetShiftByID(shiftID)
+	return s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		shift, err := repos.Shift.GetShiftByIDForUpdate(shiftID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return errs.ErrShiftNotFound
+		}
+		if err != nil {
+			log.Printf("%s: GetShiftByIDForUpdate error: %v", funcName, err)
+			return err
+		}
+		if !shift.IsAvailable {
+			log.Printf("%s: Shift is not available", funcName)
+			return fmt.Errorf("shift is not available")
+		}
+		if err := checkNotStarted(shift, s.Clock.Now()); err != nil {
+			return err
+		}
+
+		// Lock the worker so concurrent requests for the same worker are
+		// evaluated one after another against the same limits.
+		worker, err := repos.User.GetUserByIDForUpdate(workerID)
+		if err != nil {
+			log.Printf("%s: GetUserByIDForUpdate error: %v", funcName, err)
+			return err
+		}
+
+		workerShifts, err := repos.WorkerShift.ListWorkerShiftsByShift(shiftID)
+		if err != nil {
+			log.Printf("%s: ListWorkerShiftsByShift error: %v", funcName, err)
+			return err
+		}
+		for _, ws := range workerShifts {
+			if ws.UserAccountID == workerID && (ws.Status == model.WORKER_SHIFT_PENDING ||
+				ws.Status == model.WORKER_SHIFT_WAITLISTED || holdsPlace(ws)) {
+				return errs.ErrAlreadyOnShift
+			}
+		}
+
+		if err := checkWorkerEligibility(repos, s.Rules, shift, worker); err != nil {
+			log.Printf("%s: checkWorkerEligibility error: %v", funcName, err)
+			return err
+		}
+
+		ws := &model.WorkerShift{
+			ShiftID:       shiftID,
+			UserAccountID: workerID,
+			Status:        model.WORKER_SHIFT_PENDING,
+		}
+		_, err = repos.WorkerShift.CreateWorkerShift(ws)
+		if err != nil {
+			log.Printf("%s: CreateWorkerShift error: %v", funcName, err)
+			return err
+		}
+
+		return nil
+	})
+}
+
+// WithdrawShiftRequest lets a worker take back a request that is still
+// pending or on the waitlist.
+func (s *ShiftService) WithdrawShiftRequest(ctx context.Context, shiftID, workerID int64) error {
+	funcName := "/service/shift/WithdrawShiftRequest"
+
+	return s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		request, _, err := lockWorkerShiftOnShift(repos, shiftID, workerID, model.WORKER_SHIFT_PENDING, model.WORKER_SHIFT_WAITLISTED)
+		if err != nil {
+			log.Printf("%s: lockWorkerShiftOnShift error: %v", funcName, err)
+			return err
+		}
+		if request == nil {
+			return errs.ErrNoPendingRequest
+		}
+
+		err = repos.WorkerShift.UpdatesWorkerShiftStatus(request.ID, model.WORKER_SHIFT_WITHDRAWN, request.ApprovedBy)
+		if err != nil {
+			log.Printf("%s: UpdatesWorkerShiftStatus error: %v", funcName, err)
+			return err
+		}
+		return nil
+	})
+}
+
+// CancelShift lets a worker drop an approved shift up to Config.CancellationNotice
+// before it starts. The freed slot is offered to the next eligible worker on
+// the waitlist, or makes the shift available again when there is none, and
+// any open transfer of the assignment is cancelled with it.
+func (s *ShiftService) CancelShift(ctx context.Context, shiftID, workerID int64) error {
+	funcName := "/service/shift/CancelShift"
+
+	return s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		assignment, shift, err := lockWorkerShiftOnShift(repos, shiftID, workerID, model.WORKER_SHIFT_APPROVED)
+		if err != nil {
+			log.Printf("%s: lockWorkerShiftOnShift error: %v", funcName, err)
+			return err
+		}
+		if assignment == nil {
+			return errs.ErrNoApprovedShift
+		}
+
+		start, err := shiftStart(shift)
+		if err != nil {
+			log.Printf("%s: shiftStart error: %v", funcName, err)
+			return err
+		}
+		if start.Sub(s.Clock.Now()) < s.Config.CancellationNotice.Std() {
+			return errs.ErrCancellationTooLate
+		}
+
+		err = repos.WorkerShift.UpdatesWorkerShiftStatus(assignment.ID, model.WORKER_SHIFT_CANCELLED, assignment.ApprovedBy)
+		if err != nil {
+			log.Printf("%s: UpdatesWorkerShiftStatus error: %v", funcName, err)
+			return err
+		}
+
+		transfers, err := repos.ShiftTransfer.ListActiveTransfersByWorkerShift(assignment.ID)
+		if err != nil {
+			log.Printf("%s: ListActiveTransfersByWorkerShift error: %v", funcName, err)
+			return err
+		}
+		for _, transfer := range transfers {
+			transfer.Status = model.TRANSFER_CANCELLED
+			if err := repos.ShiftTransfer.UpdateShiftTransfer(&transfer.ShiftTransfer); err != nil {
+				log.Printf("%s: UpdateShiftTransfer error: %v", funcName, err)
+				return err
+			}
+		}
+
+		workerShifts, err := repos.WorkerShift.ListWorkerShiftsByShift(shiftID)
+		if err != nil {
+			log.Printf("%s: ListWorkerShiftsByShift error: %v", funcName, err)
+			return err
+		}
+		if _, err := offerVacancies(repos, s.Rules, shift, workerShifts, s.Clock.Now(), s.Config.OfferTTL.Std()); err != nil {
+			log.Printf("%s: offerVacancies error: %v", funcName, err)
+			return err
+		}
+		return nil
+	})
+}
+
+// AcceptOffer takes up the place offered to a waitlisted worker, as long as
+// the offer has not expired, the worker holds no other place on the shift
+// and still passes the labour rules.
+func (s *ShiftService) AcceptOffer(ctx context.Context, shiftID, workerID int64) error {
+	funcName := "/service/shift/AcceptOffer"
+
+	return s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		offer, shift, err := lockWorkerShiftOnShift(repos, shiftID, workerID, model.WORKER_SHIFT_OFFERED)
+		if err != nil {
+			log.Printf("%s: lockWorkerShiftOnShift error: %v", funcName, err)
+			return err
+		}
+		if offer == nil {
+			return errs.ErrNoOffer
+		}
+		now := s.Clock.Now()
+		if offer.OfferExpiresAt != nil && !now.Before(*offer.OfferExpiresAt) {
+			return errs.ErrOfferExpired
+		}
+		if err := checkNotStarted(shift, now); err != nil {
+			return err
+		}
+		workerShifts, err := repos.WorkerShift.ListWorkerShiftsByShift(shiftID)
+		if err != nil {
+			log.Printf("%s: ListWorkerShiftsByShift error: %v", funcName, err)
+			return err
+		}
+		if holdsOtherPlace(workerShifts, workerID, offer.ID) {
+			return errs.ErrAlreadyOnShift
+		}
+
+		worker, err := repos.User.GetUserByIDForUpdate(workerID)
+		if err != nil {
+			log.Printf("%s: GetUserByIDForUpdate error: %v", funcName, err)
+			return err
+		}
+		if err := checkWorkerEligibility(repos, s.Rules, shift, worker); err != nil {
+			log.Printf("%s: checkWorkerEligibility error: %v", funcName, err)
+			return err
+		}
+
+		err = repos.WorkerShift.UpdatesWorkerShiftStatus(offer.ID, model.WORKER_SHIFT_APPROVED, nil)
+		if err != nil {
+			log.Printf("%s: UpdatesWorkerShiftStatus error: %v", funcName, err)
+			return err
+		}
+		for _, ws := range workerShifts {
+			if ws.ID == offer.ID {
+				ws.Status = model.WORKER_SHIFT_APPROVED
+			}
+		}
+		if _, err := closeIfFull(repos, shift, workerShifts); err != nil {
+			log.Printf("%s: closeIfFull error: %v", funcName, err)
+			return err
+		}
+		return nil
+	})
+}
+
+// DeclineOffer turns down the place offered to a waitlisted worker, which is
+// then offered to the next one on the waitlist.
+func (s *ShiftService) DeclineOffer(ctx context.Context, shiftID, workerID int64) error {
+	funcName := "/service/shift/DeclineOffer"
+
+	return s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		offer, shift, err := lockWorkerShiftOnShift(repos, shiftID, workerID, model.WORKER_SHIFT_OFFERED)
+		if err != nil {
+			log.Printf("%s: lockWorkerShiftOnShift error: %v", funcName, err)
+			return err
+		}
+		if offer == nil {
+			return errs.ErrNoOffer
+		}
+
+		err = repos.WorkerShift.UpdatesWorkerShiftStatus(offer.ID, model.WORKER_SHIFT_WITHDRAWN, nil)
+		if err != nil {
+			log.Printf("%s: UpdatesWorkerShiftStatus error: %v", funcName, err)
+			return err
+		}
+
+		workerShifts, err := repos.WorkerShift.ListWorkerShiftsByShift(shiftID)
+		if err != nil {
+			log.Printf("%s: ListWorkerShiftsByShift error: %v", funcName, err)
+			return err
+		}
+		if _, err := offerVacancies(repos, s.Rules, shift, workerShifts, s.Clock.Now(), s.Config.OfferTTL.Std()); err != nil {
+			log.Printf("%s: offerVacancies error: %v", funcName, err)
+			return err
+		}
+		return nil
+	})
+}
+
+// GetWorkerHours returns the hours the worker has booked in the current and
+// the next week against the weekly cap. Each shift is capped by the labour
+// rules for its role and location, as when it was requested; a week shows
+// the tightest cap among its shifts, or the default cap without any.
+func (s *ShiftService) GetWorkerHours(ctx context.Context, workerID int64) (*model.WorkerHours, error) {
+	funcName := "/service/shift/GetWorkerHours"
+
+	// Weeks are in the default zone; a day of slack on either side catches
+	// shifts whose location date differs from it
+	thisWeek := rules.WeekStart(s.Clock.Now().In(s.Zone))
+	weekAfter := thisWeek.AddDate(0, 0, 14)
+	dateFrom := thisWeek.AddDate(0, 0, -1).Format(dateLayout)
+	dateTo := weekAfter.Format(dateLayout)
+	workerShifts, err := s.WorkerShiftRepo.GetWorkerShiftDetailListByFilter(&model.WorkerShiftDetailQuery{
+		UserAccountID: &workerID,
+		DateFrom:      &dateFrom,
+		DateTo:        &dateTo,
+	})
 	if err != nil {
-		log.Printf("%s: GetShiftByID error: %v", funcName, err)
-		return err
+		log.Printf("%s: GetWorkerShiftDetailListByFilter error: %v", funcName, err)
+		return nil, err
 	}
-	if !shift.IsAvailable {
-		log.Printf("%s: Shift is not available", funcName)
-		return fmt.Errorf("shift is not available")
+
+	result := &model.WorkerHours{WorkerID: workerID}
+	for week := 0; week < 2; week++ {
+		start := thisWeek.AddDate(0, 0, 7*week)
+		result.Weeks = append(result.Weeks, model.WeekHours{
+			WeekStart: start.Format(dateLayout),
+			WeekEnd:   start.AddDate(0, 0, 6).Format(dateLayout),
+		})
 	}
+	hasShifts := make([]bool, len(result.Weeks))
 
-	statuses := []string{model.WORKER_SHIFT_PENDING, model.WORKER_SHIFT_APPROVED}
-	for _, status := range statuses {
-		shifts, err := s.WorkerShiftRepo.GetWorkerShiftListByFilter(&workerID, &status)
+	for i := range workerShifts {
+		ws := &workerShifts[i]
+		start, end, err := rules.Bounds(ws.AsShift())
 		if err != nil {
-			log.Printf("%s: GetWorkerShiftListByFilter error: %v", funcName, err)
-			return err
+			log.Printf("%s: Bounds error for shift %d: %v", funcName, ws.ShiftID, err)
+			continue
 		}
-		for _, ws := range shifts {
-			if ws.ShiftID == shiftID {
-				log.Printf("%s: Already requested or assigned to this shift", funcName)
-				return fmt.Errorf("already requested or assigned to this shift")
-			}
+		if start.Before(thisWeek) || !start.Before(weekAfter) {
+			continue
+		}
+		hours := end.Sub(start).Hours()
+		index := 0
+		if !start.Before(thisWeek.AddDate(0, 0, 7)) {
+			index = 1
+		}
+		week := &result.Weeks[index]
+		switch ws.Status {
+		case model.WORKER_SHIFT_APPROVED, model.WORKER_SHIFT_DONE:
+			week.Shifts++
+			week.BookedHours += hours
+		case model.WORKER_SHIFT_PENDING, model.WORKER_SHIFT_OFFERED:
+			week.PendingHours += hours
+		default:
+			continue
+		}
+		hasShifts[index] = true
+		limit := weeklyHoursCap(s.Rules.Config.For(ws.RoleAssignment, ws.Location))
+		if limit != nil && (week.MaxHours == nil || *limit < *week.MaxHours) {
+			week.MaxHours = limit
 		}
 	}
 
-	status := model.WORKER_SHIFT_APPROVED
-	approvedShifts, err := s.WorkerShiftRepo.GetWorkerShiftListByFilter(&workerID, &status)
+	for i := range result.Weeks {
+		week := &result.Weeks[i]
+		if !hasShifts[i] {
+			week.MaxHours = weeklyHoursCap(s.Rules.Config.Default)
+		}
+		if week.MaxHours != nil {
+			remaining := *week.MaxHours - week.BookedHours
+			week.RemainingHours = &remaining
+		}
+	}
+	return result, nil
+}
+
+// weeklyHoursCap returns the weekly hours cap of the rule set, or nil when
+// it has none.
+func weeklyHoursCap(set config.RuleSet) *float64 {
+	if set.MaxHoursPerWeek == nil || *set.MaxHoursPerWeek <= 0 {
+		return nil
+	}
+	return set.MaxHoursPerWeek
+}
+
+// lockWorkerShiftOnShift locks the shift and returns the worker's request on
+// it with one of the given statuses, or nil when there is none.
+func lockWorkerShiftOnShift(repos *repository.Repositories, shiftID, workerID int64, statuses ...string) (*model.WorkerShift, *model.Shift, error) {
+	shift, err := repos.Shift.GetShiftByIDForUpdate(shiftID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, errs.ErrShiftNotFound
+	}
 	if err != nil {
-		log.Printf("%s: GetWorkerShiftListByFilter error: %v", funcName, err)
-		return err
+		return nil, nil, err
 	}
-	for _, ws := range approvedShifts {
-		otherShift, err := s.ShiftRepo.GetShiftByID(ws.ShiftID)
-		if err != nil {
+
+	workerShifts, err := repos.WorkerShift.ListWorkerShiftsByShift(shiftID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, ws := range workerShifts {
+		if ws.UserAccountID != workerID {
 			continue
 		}
-		if otherShift.Date == shift.Date {
-			// Overlap if times intersect
-			if shift.StartTime < otherShift.EndTime && shift.EndTime > otherShift.StartTime {
-				log.Printf("%s: Overlapping shift on this day", funcName)
-				return fmt.Errorf("overlapping shift on this day")
+		for _, status := range statuses {
+			if ws.Status == status {
+				return ws, shift, nil
 			}
-
-			return fmt.Errorf("already has a shift on this day")
 		}
 	}
+	return nil, shift, nil
+}
+
+// shiftStart returns the start of the shift in the server's local time.
+func shiftStart(shift *model.Shift) (time.Time, error) {
+	start, _, err := rules.Bounds(shift)
+	return start, err
+}
 
-	hasShiftOnDay, shiftsThisWeek, err := s.WorkerShiftRepo.CheckWorkerShiftLimits(workerID, shift.Date)
+// checkNotStarted fails once the shift has started. Nobody is assigned to
+// a shift after the fact; the lifecycle job would settle it at once.
+func checkNotStarted(shift *model.Shift, now time.Time) error {
+	start, err := shiftStart(shift)
 	if err != nil {
-		log.Printf("%s: CheckWorkerShiftLimits error: %v", funcName, err)
 		return err
 	}
-	if hasShiftOnDay {
-		return fmt.Errorf("already has a shift on this day")
+	if !now.Before(start) {
+		return errs.ErrShiftStarted
 	}
-	if shiftsThisWeek >= 5 {
-		return fmt.Errorf("already has 5 shifts this week")
+	return nil
+}
+
+// checkWorkerEligibility runs the labour rules for the shift against the
+// worker, their skills and approved leave and the shifts they already work
+// around it. A failed check returns a *rules.ViolationError listing every
+// violated rule.
+func checkWorkerEligibility(repos *repository.Repositories, engine *rules.Engine, shift *model.Shift, worker *model.User) error {
+	day, err := time.ParseInLocation(dateLayout, shift.Date, shift.StartAt.Location())
+	if err != nil {
+		return err
 	}
+	dateFrom := day.AddDate(0, 0, -rules.LookbackDays).Format(dateLayout)
+	dateTo := day.AddDate(0, 0, rules.LookbackDays).Format(dateLayout)
 
-	ws := &model.WorkerShift{
-		ShiftID:       shiftID,
-		UserAccountID: workerID,
-		Status:        model.WORKER_SHIFT_PENDING,
+	skills, err := repos.WorkerSkill.ListWorkerSkills(worker.ID)
+	if err != nil {
+		return err
 	}
-	_, err = s.WorkerShiftRepo.CreateWorkerShift(ws)
+	// A night shift may reach into the next day's leave
+	approved := model.TIME_OFF_APPROVED
+	leaveFrom := shift.Date
+	leaveTo := day.AddDate(0, 0, 1).Format(dateLayout)
+	timeOff, err := repos.TimeOff.ListTimeOff(model.TimeOffQuery{
+		UserAccountID: &worker.ID,
+		Status:        &approved,
+		DateFrom:      &leaveFrom,
+		DateTo:        &leaveTo,
+	})
 	if err != nil {
-		log.Printf("%s: CreateWorkerShift error: %v", funcName, err)
 		return err
 	}
+	in := &rules.Input{Worker: worker, Skills: skills, TimeOff: timeOff, Shift: shift}
+	for _, status := range []string{model.WORKER_SHIFT_APPROVED, model.WORKER_SHIFT_DONE} {
+		status := status
+		assigned, err := repos.WorkerShift.GetWorkerShiftDetailListByFilter(&model.WorkerShiftDetailQuery{
+			UserAccountID: &worker.ID,
+			Status:        &status,
+			DateFrom:      &dateFrom,
+			DateTo:        &dateTo,
+		})
+		if err != nil {
+			return err
+		}
+		for i := range assigned {
+			if assigned[i].ShiftID == shift.ID {
+				continue
+			}
+			in.Assigned = append(in.Assigned, assigned[i].AsShift())
+		}
+	}
 
+	if violations := engine.Evaluate(in); len(violations) > 0 {
+		return &rules.ViolationError{Violations: violations}
+	}
 	return nil
 }
 
@@ -267,20 +677,20 @@ func (s *ShiftService) GetAllRequestedShift(ctx context.Context, workerID int64)
 		return nil, err
 	}
 
+	filledByShift, err := s.countApproved(shift)
+	if err != nil {
+		log.Printf("%s: countApproved error: %v", funcName, err)
+		return nil, err
+	}
+
 	for _, s := range shift {
-		shiftStatus := &model.ShiftStatus{
-			ID:             s.ID,
-			Date:           s.Date,
-			StartTime:      s.StartTime,
-			EndTime:        s.EndTime,
-			RoleAssignment: s.RoleAssignment,
-			Location:       s.Location,
-			IsAvailable:    s.IsAvailable,
-		}
+		shiftStatus := newShiftStatus(s, filledByShift[s.ID])
 
 		for _, ws := range workerShift {
 			if ws.ShiftID == s.ID {
 				shiftStatus.StatusWorker = ws.Status
+				shiftStatus.WaitlistPosition = ws.WaitlistPosition
+				shiftStatus.OfferExpiresAt = ws.OfferExpiresAt
 				break
 			}
 		}
@@ -294,6 +704,30 @@ func (s *ShiftService) GetAllRequestedShift(ctx context.Context, workerID int64)
 func (s *ShiftService) CreateShift(ctx context.Context, shift *model.Shift) (int64, error) {
 	funcName := "/service/shift/CreateShift"
 
+	if shift.Headcount == 0 {
+		shift.Headcount = 1
+	}
+	if shift.Headcount < 0 {
+		return 0, errs.ErrInvalidHeadcount
+	}
+	if err := validateRole(s.SkillRepo, &shift.RoleAssignment, ""); err != nil {
+		log.Printf("%s: validateRole error: %v", funcName, err)
+		return 0, err
+	}
+	location, err := resolveLocation(s.LocationRepo, shift.LocationID, shift.Location, 0)
+	if err != nil {
+		log.Printf("%s: resolveLocation error: %v", funcName, err)
+		return 0, err
+	}
+	shift.LocationID, shift.Location = location.ID, location.Name
+	loc, err := clock.LoadLocation(location.TimeZone, s.Zone)
+	if err != nil {
+		return 0, err
+	}
+	if err := normalizeShiftTimes(shift, loc); err != nil {
+		return 0, err
+	}
+
 	shiftID, err := s.ShiftRepo.CreateShift(shift)
 	if err != nil {
 		log.Printf("%s: CreateShift error: %v", funcName, err)
@@ -302,25 +736,228 @@ func (s *ShiftService) CreateShift(ctx context.Context, shift *model.Shift) (int
 	return shiftID, nil
 }
 
-func (s *ShiftService) UpdateShift(ctx context.Context, shift *model.Shift) error {
+// UpdateShift edits a shift. Once workers are approved only the headcount
+// may change, and never below the number already approved: the assignments
+// were made for the original slot, so the admin has to reject or reassign
+// first. Pending requests stay pending and are re-checked on approval, until
+// a lower headcount fills the shift and waitlists them; a higher one offers
+// the new places to the waitlist. isAvailable, when given, opens or closes
+// the shift for requests; a full shift stays closed.
+func (s *ShiftService) UpdateShift(ctx context.Context, shift *model.Shift, isAvailable *bool) error {
 	funcName := "/service/shift/UpdateShift"
 
-	err := s.ShiftRepo.UpdateShiftByID(shift)
-	if err != nil {
-		log.Printf("%s: UpdateShift error: %v", funcName, err)
+	if shift.Headcount < 0 {
+		return errs.ErrInvalidHeadcount
 	}
 
-	return err
+	return s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		current, err := repos.Shift.GetShiftByIDForUpdate(shift.ID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return errs.ErrShiftNotFound
+		}
+		if err != nil {
+			log.Printf("%s: GetShiftByIDForUpdate error: %v", funcName, err)
+			return err
+		}
+
+		if err := validateRole(repos.Skill, &shift.RoleAssignment, current.RoleAssignment); err != nil {
+			log.Printf("%s: validateRole error: %v", funcName, err)
+			return err
+		}
+		location, err := resolveLocation(repos.Location, shift.LocationID, shift.Location, current.LocationID)
+		if err != nil {
+			log.Printf("%s: resolveLocation error: %v", funcName, err)
+			return err
+		}
+		shift.LocationID, shift.Location = location.ID, location.Name
+		loc, err := clock.LoadLocation(location.TimeZone, s.Zone)
+		if err != nil {
+			return err
+		}
+		if err := normalizeShiftTimes(shift, loc); err != nil {
+			return err
+		}
+		if shift.Headcount == 0 {
+			shift.Headcount = current.Headcount
+		}
+		// A template has at most one shift per date
+		if current.TemplateID != nil && shift.Date != current.Date {
+			taken, err := repos.Shift.GetListShifts(model.ShiftListQuery{TemplateID: current.TemplateID, Date: shift.Date})
+			if err != nil {
+				log.Printf("%s: GetListShifts error: %v", funcName, err)
+				return err
+			}
+			if len(taken) > 0 {
+				return errs.ErrTemplateDateTaken
+			}
+		}
+
+		workerShifts, err := repos.WorkerShift.ListWorkerShiftsByShift(shift.ID)
+		if err != nil {
+			log.Printf("%s: ListWorkerShiftsByShift error: %v", funcName, err)
+			return err
+		}
+		filled, recorded := 0, false
+		for _, ws := range workerShifts {
+			if holdsPlace(ws) {
+				filled++
+			}
+			recorded = recorded || onRecord(ws)
+		}
+		if recorded && !sameSlot(current, shift) {
+			return errs.ErrShiftHasAssignments
+		}
+		if shift.Headcount < filled {
+			return errs.ErrHeadcountBelowFilled
+		}
+
+		// Without an explicit choice availability follows the approval flow
+		switch {
+		case isAvailable != nil:
+			shift.IsAvailable = *isAvailable && filled < shift.Headcount
+		case filled > 0:
+			shift.IsAvailable = filled < shift.Headcount
+		default:
+			shift.IsAvailable = current.IsAvailable
+		}
+		if err := repos.Shift.UpdateShiftByID(shift); err != nil {
+			log.Printf("%s: UpdateShift error: %v", funcName, err)
+			return err
+		}
+
+		// Lowering the headcount to the approved count fills the shift,
+		// raising it or reopening the shift makes room for the waitlist
+		if filled >= shift.Headcount {
+			if _, err := closeIfFull(repos, shift, workerShifts); err != nil {
+				log.Printf("%s: closeIfFull error: %v", funcName, err)
+				return err
+			}
+		} else if shift.IsAvailable && (shift.Headcount > current.Headcount || !current.IsAvailable) {
+			if _, err := offerVacancies(repos, s.Rules, shift, workerShifts, s.Clock.Now(), s.Config.OfferTTL.Std()); err != nil {
+				log.Printf("%s: offerVacancies error: %v", funcName, err)
+				return err
+			}
+		}
+		return nil
+	})
 }
 
+// DeleteShift removes a shift together with its rejected, withdrawn and
+// expired requests. A shift a worker holds or held a place on is kept for
+// their history, and pending or waitlisted requests must be rejected first.
 func (s *ShiftService) DeleteShift(ctx context.Context, shiftID int64) error {
 	funcName := "/service/shift/DeleteShift"
 
-	err := s.ShiftRepo.DeleteShiftByID(shiftID)
+	return s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		if _, err := lockRemovableShift(repos, shiftID); err != nil {
+			log.Printf("%s: lockRemovableShift error: %v", funcName, err)
+			return err
+		}
+
+		if err := repos.WorkerShift.DeleteWorkerShiftsByShift(shiftID); err != nil {
+			log.Printf("%s: DeleteWorkerShiftsByShift error: %v", funcName, err)
+			return err
+		}
+		if err := repos.Shift.DeleteShiftByID(shiftID); err != nil {
+			log.Printf("%s: DeleteShift error: %v", funcName, err)
+			return err
+		}
+		return nil
+	})
+}
+
+// sameSlot reports whether two shifts describe the same start, end, role and location
+func sameSlot(a, b *model.Shift) bool {
+	aStart, aEnd, aErr := rules.Bounds(a)
+	bStart, bEnd, bErr := rules.Bounds(b)
+	return aErr == nil && bErr == nil &&
+		aStart.Equal(bStart) &&
+		aEnd.Equal(bEnd) &&
+		a.RoleAssignment == b.RoleAssignment &&
+		a.LocationID == b.LocationID
+}
+
+// maxShiftLength bounds a shift so it can be described by a date and two
+// clock times.
+const maxShiftLength = 24 * time.Hour
+
+// normalizeShiftTimes makes the shift's date and clock times, read as wall
+// clock times in loc, agree with its start and end instants. When start_at
+// and end_at are given they win; otherwise they are derived from date,
+// start_time and end_time, with an end time before the start time ending
+// the shift on the next day.
+func normalizeShiftTimes(shift *model.Shift, loc *time.Location) error {
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: %s", errs.ErrInvalidShiftTime, fmt.Sprintf(format, args...))
+	}
+
+	if !shift.StartAt.IsZero() || !shift.EndAt.IsZero() {
+		if shift.StartAt.IsZero() || shift.EndAt.IsZero() {
+			return invalid("start_at and end_at must be given together")
+		}
+		start, end := shift.StartAt.In(loc), shift.EndAt.In(loc)
+		if !end.After(start) {
+			return invalid("end_at must be after start_at")
+		}
+		if end.Sub(start) > maxShiftLength {
+			return invalid("a shift may last at most %s", maxShiftLength)
+		}
+		shift.StartAt, shift.EndAt = start, end
+		shift.Date = start.Format(dateLayout)
+		shift.StartTime = start.Format("15:04:05")
+		shift.EndTime = end.Format("15:04:05")
+		shift.TimeZone = loc.String()
+		return nil
+	}
+
+	if _, err := time.Parse(dateLayout, shift.Date); err != nil {
+		return invalid("date must be YYYY-MM-DD")
+	}
+	startClock, err := parseClock(shift.StartTime)
+	if err != nil {
+		return invalid("start_time must be HH:MM")
+	}
+	endClock, err := parseClock(shift.EndTime)
+	if err != nil {
+		return invalid("end_time must be HH:MM")
+	}
+	if startClock.Equal(endClock) {
+		return invalid("end_time must differ from start_time")
+	}
+	// Stored the way MySQL returns TIME values, like shift templates
+	shift.StartTime = startClock.Format("15:04:05")
+	shift.EndTime = endClock.Format("15:04:05")
+	start, end, err := rules.BoundsIn(shift, loc)
+	if err != nil {
+		return err
+	}
+	// Clock times skipped by a DST change have moved forward
+	shift.StartAt, shift.EndAt = start, end
+	shift.StartTime = start.Format("15:04:05")
+	shift.EndTime = end.Format("15:04:05")
+	shift.TimeZone = loc.String()
+	return nil
+}
+
+// lockRemovableShift locks the shift row and fails when it does not exist or
+// checkShiftRemovable refuses its requests.
+func lockRemovableShift(repos *repository.Repositories, shiftID int64) (*model.Shift, error) {
+	shift, err := repos.Shift.GetShiftByIDForUpdate(shiftID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errs.ErrShiftNotFound
+	}
 	if err != nil {
-		log.Printf("%s: DeleteShift error: %v", funcName, err)
+		return nil, err
 	}
-	return err
+
+	workerShifts, err := repos.WorkerShift.ListWorkerShiftsByShift(shiftID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkShiftRemovable(workerShifts); err != nil {
+		return nil, err
+	}
+	return shift, nil
 }
 
 func (s *ShiftService) GetAllShiftRequests(ctx context.Context, queryParam model.WorkerShiftDetailQuery) ([]model.WorkerShiftDetail, error) {
@@ -332,70 +969,215 @@ func (s *ShiftService) GetAllShiftRequests(ctx context.Context, queryParam model
 		return nil, err
 	}
 
+	// Pending requests show what approving them would conflict with
+	availabilityByWorker := make(map[int64]*model.WorkerAvailability)
+	for i := range workerShift {
+		detail := &workerShift[i]
+		if detail.Status != model.WORKER_SHIFT_PENDING {
+			continue
+		}
+		availability, ok := availabilityByWorker[detail.UserAccountID]
+		if !ok {
+			availability, err = loadAvailability(s.AvailabilityRepo, detail.UserAccountID, s.Clock.Now())
+			if err != nil {
+				log.Printf("%s: loadAvailability error: %v", funcName, err)
+				return nil, err
+			}
+			availabilityByWorker[detail.UserAccountID] = availability
+		}
+		detail.AvailabilityConflicts = availabilityConflicts(availability, detail.AsShift())
+	}
+
 	return workerShift, nil
 }
 
-func (s *ShiftService) ApproveShiftRequest(ctx context.Context, shiftID, workerID int64) error {
+// ApproveShiftRequest approves a pending request. The shift stays open, and
+// other pending requests stay pending, until the approvals reach its
+// headcount; the remaining pending requests are waitlisted at that point.
+// Conflicts with the worker's availability do not prevent the approval and
+// are returned for the admin to see.
+func (s *ShiftService) ApproveShiftRequest(ctx context.Context, shiftID, workerID int64) ([]string, error) {
 	funcName := "/service/shift/ApproveShiftRequest"
 
-	shift, err := s.ShiftRepo.GetShiftByID(shiftID)
+	var conflicts []string
+	err := s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		shift, _, err := approvePendingRequest(ctx, repos, s.Rules, shiftID, workerID, s.Clock.Now())
+		if err != nil {
+			return err
+		}
+
+		availability, err := loadAvailability(repos.Availability, workerID, s.Clock.Now())
+		if err != nil {
+			log.Printf("%s: loadAvailability error: %v", funcName, err)
+			return err
+		}
+		conflicts = availabilityConflicts(availability, shift)
+		return nil
+	})
 	if err != nil {
-		log.Printf("%s: GetShiftByID error: %v", funcName, err)
-		return err
+		return nil, err
 	}
+	return conflicts, nil
+}
 
-	shift.IsAvailable = false
-	if err := s.ShiftRepo.UpdateShiftByID(shift); err != nil {
-		log.Printf("%s: UpdateShiftByID error: %v", funcName, err)
-		return err
+// approvePendingRequest approves the worker's pending request for the shift
+// within the caller's transaction, see ApproveShiftRequest. It returns the
+// shift and the other pending requests waitlisted because it filled up.
+func approvePendingRequest(ctx context.Context, repos *repository.Repositories, engine *rules.Engine, shiftID, workerID int64, now time.Time) (*model.Shift, []*model.WorkerShift, error) {
+	funcName := "/service/shift/approvePendingRequest"
+
+	// Locking the shift makes a concurrent approval wait here and then
+	// count the approval made by the first one.
+	shift, err := repos.Shift.GetShiftByIDForUpdate(shiftID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, errs.ErrShiftNotFound
+	}
+	if err != nil {
+		log.Printf("%s: GetShiftByIDForUpdate error: %v", funcName, err)
+		return nil, nil, err
+	}
+	if !shift.IsAvailable {
+		log.Printf("%s: Shift is not available", funcName)
+		return nil, nil, fmt.Errorf("shift is not available")
+	}
+	if err := checkNotStarted(shift, now); err != nil {
+		return nil, nil, err
 	}
 
-	workerShifts, err := s.WorkerShiftRepo.ListWorkerShiftsByShift(shiftID)
+	workerShifts, err := repos.WorkerShift.ListWorkerShiftsByShift(shiftID)
 	if err != nil {
 		log.Printf("%s: ListWorkerShiftsByShift error: %v", funcName, err)
-		return err
+		return nil, nil, err
 	}
 
+	var request *model.WorkerShift
+	filled := 0
 	for _, ws := range workerShifts {
-		if ws.UserAccountID == workerID {
-			err := s.WorkerShiftRepo.UpdatesWorkerShiftStatus(ws.ID, model.WORKER_SHIFT_APPROVED, nil)
-			if err != nil {
-				log.Printf("%s: Approve error for wsID %d: %v", funcName, ws.ID, err)
-				return err
-			}
-		} else {
-			err := s.WorkerShiftRepo.UpdatesWorkerShiftStatus(ws.ID, model.WORKER_SHIFT_REJECTED, nil)
-			if err != nil {
-				log.Printf("%s: Reject error for wsID %d: %v", funcName, ws.ID, err)
-				return err
-			}
+		// Places offered to waitlisted workers are kept for them
+		if holdsPlace(ws) {
+			filled++
+		}
+		if ws.UserAccountID == workerID && ws.Status == model.WORKER_SHIFT_PENDING {
+			request = ws
 		}
 	}
+	if request == nil {
+		log.Printf("%s: No pending request for worker %d", funcName, workerID)
+		return nil, nil, errs.ErrNoPendingRequest
+	}
+	if filled >= shift.Headcount {
+		log.Printf("%s: Shift is already full", funcName)
+		return nil, nil, fmt.Errorf("shift is not available")
+	}
 
-	return nil
+	worker, err := repos.User.GetUserByIDForUpdate(workerID)
+	if err != nil {
+		log.Printf("%s: GetUserByIDForUpdate error: %v", funcName, err)
+		return nil, nil, err
+	}
+	if err := checkWorkerEligibility(repos, engine, shift, worker); err != nil {
+		log.Printf("%s: checkWorkerEligibility error: %v", funcName, err)
+		return nil, nil, err
+	}
+
+	err = repos.WorkerShift.UpdatesWorkerShiftStatus(request.ID, model.WORKER_SHIFT_APPROVED, auth.UserID(ctx))
+	if err != nil {
+		log.Printf("%s: Approve error for wsID %d: %v", funcName, request.ID, err)
+		return nil, nil, err
+	}
+	request.Status = model.WORKER_SHIFT_APPROVED
+
+	waitlisted, err := closeIfFull(repos, shift, workerShifts)
+	if err != nil {
+		log.Printf("%s: closeIfFull error: %v", funcName, err)
+		return nil, nil, err
+	}
+	return shift, waitlisted, nil
 }
 
 func (s *ShiftService) RejectShiftRequest(ctx context.Context, shiftID, workerID int64) error {
 	funcName := "/service/shift/RejectShiftRequest"
 
-	workerShifts, err := s.WorkerShiftRepo.ListWorkerShiftsByShift(shiftID)
-	if err != nil {
-		log.Printf("%s: ListWorkerShiftsByShift error: %v", funcName, err)
-		return err
-	}
+	return s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		_, err := repos.Shift.GetShiftByIDForUpdate(shiftID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return errs.ErrShiftNotFound
+		}
+		if err != nil {
+			log.Printf("%s: GetShiftByIDForUpdate error: %v", funcName, err)
+			return err
+		}
 
-	for _, ws := range workerShifts {
-		if ws.UserAccountID == workerID {
-			err := s.WorkerShiftRepo.UpdatesWorkerShiftStatus(ws.ID, model.WORKER_SHIFT_REJECTED, nil)
-			if err != nil {
-				log.Printf("%s: Reject error for wsID %d: %v", funcName, ws.ID, err)
-				return err
+		workerShifts, err := repos.WorkerShift.ListWorkerShiftsByShift(shiftID)
+		if err != nil {
+			log.Printf("%s: ListWorkerShiftsByShift error: %v", funcName, err)
+			return err
+		}
+
+		for _, ws := range workerShifts {
+			if ws.UserAccountID == workerID &&
+				(ws.Status == model.WORKER_SHIFT_PENDING || ws.Status == model.WORKER_SHIFT_WAITLISTED) {
+				err := repos.WorkerShift.UpdatesWorkerShiftStatus(ws.ID, model.WORKER_SHIFT_REJECTED, nil)
+				if err != nil {
+					log.Printf("%s: Reject error for wsID %d: %v", funcName, ws.ID, err)
+					return err
+				}
+				return nil
 			}
-			break
 		}
-	}
 
-	return nil
+		log.Printf("%s: No pending request for worker %d", funcName, workerID)
+		return errs.ErrNoPendingRequest
+	})
+}
+
+// MarkNoShow records that the worker did not turn up for an approved shift
+// that has started and they have not clocked in for. The assignment no
+// longer counts as hours worked, and it counts against the worker when the
+// applicants of a shift are ranked.
+func (s *ShiftService) MarkNoShow(ctx context.Context, workerShiftID int64) error {
+	funcName := "/service/shift/MarkNoShow"
+
+	return s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		assignment, err := repos.WorkerShift.GetWorkerShiftByIDForUpdate(workerShiftID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return errs.ErrWorkerShiftNotFound
+		}
+		if err != nil {
+			log.Printf("%s: GetWorkerShiftByIDForUpdate error: %v", funcName, err)
+			return err
+		}
+		if assignment.Status != model.WORKER_SHIFT_APPROVED && assignment.Status != model.WORKER_SHIFT_DONE {
+			return errs.ErrNoShowNotAllowed
+		}
+
+		shift, err := repos.Shift.GetShiftByID(assignment.ShiftID)
+		if err != nil {
+			log.Printf("%s: GetShiftByID error: %v", funcName, err)
+			return err
+		}
+		start, err := shiftStart(shift)
+		if err != nil {
+			log.Printf("%s: shiftStart error: %v", funcName, err)
+			return err
+		}
+		if s.Clock.Now().Before(start) {
+			return errs.ErrNoShowNotAllowed
+		}
+		if _, err := repos.TimeEntry.GetTimeEntryByWorkerShiftID(assignment.ID); err == nil {
+			return errs.ErrNoShowNotAllowed
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("%s: GetTimeEntryByWorkerShiftID error: %v", funcName, err)
+			return err
+		}
+
+		err = repos.WorkerShift.UpdatesWorkerShiftStatus(assignment.ID, model.WORKER_SHIFT_NO_SHOW, assignment.ApprovedBy)
+		if err != nil {
+			log.Printf("%s: UpdatesWorkerShiftStatus error: %v", funcName, err)
+			return err
+		}
+		return nil
+	})
 }
 
 func (s *ShiftService) GetShiftsByDay(ctx context.Context, date string) ([]*model.ShiftStatus, error) {
@@ -409,34 +1191,43 @@ func (s *ShiftService) GetShiftsByDay(ctx context.Context, date string) ([]*mode
 		return nil, err
 	}
 
+	filledByShift, err := s.countApproved(shifts)
+	if err != nil {
+		log.Printf("%s: countApproved error: %v", funcName, err)
+		return nil, err
+	}
+
 	var result []*model.ShiftStatus
 	for _, shift := range shifts {
-		workerShifts, err := s.WorkerShiftRepo.ListWorkerShiftsByShift(shift.ID)
-		if err != nil {
-			log.Printf("%s: ListWorkerShiftsByShift error: %v", funcName, err)
-			return nil, err
-		}
+		result = append(result, newShiftStatus(shift, filledByShift[shift.ID]))
+	}
 
-		statusWorker := ""
-		for _, ws := range workerShifts {
-			if ws.Status == model.WORKER_SHIFT_APPROVED {
-				statusWorker = model.WORKER_SHIFT_APPROVED
-				break
-			}
-		}
+	return result, nil
+}
 
-		shiftStatus := &model.ShiftStatus{
-			ID:             shift.ID,
-			Date:           shift.Date,
-			StartTime:      shift.StartTime,
-			EndTime:        shift.EndTime,
-			RoleAssignment: shift.RoleAssignment,
-			Location:       shift.Location,
-			IsAvailable:    shift.IsAvailable,
-			StatusWorker:   statusWorker,
-		}
-		result = append(result, shiftStatus)
+// countApproved returns the number of approved workers per shift
+func (s *ShiftService) countApproved(shifts []*model.Shift) (map[int64]int, error) {
+	shiftIDs := make([]int64, 0, len(shifts))
+	for _, shift := range shifts {
+		shiftIDs = append(shiftIDs, shift.ID)
 	}
+	return s.WorkerShiftRepo.CountWorkerShiftsByShiftIDs(shiftIDs, model.WORKER_SHIFT_APPROVED)
+}
 
-	return result, nil
+func newShiftStatus(shift *model.Shift, filled int) *model.ShiftStatus {
+	return &model.ShiftStatus{
+		ID:             shift.ID,
+		Date:           shift.Date,
+		StartTime:      shift.StartTime,
+		EndTime:        shift.EndTime,
+		StartAt:        shift.StartAt,
+		EndAt:          shift.EndAt,
+		TimeZone:       shift.TimeZone,
+		RoleAssignment: shift.RoleAssignment,
+		LocationID:     shift.LocationID,
+		Location:       shift.Location,
+		IsAvailable:    shift.IsAvailable,
+		Headcount:      shift.Headcount,
+		Filled:         filled,
+	}
 }
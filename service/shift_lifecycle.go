@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"dailyworkerroster/clock"
+	"dailyworkerroster/config"
+	"dailyworkerroster/model"
+	"dailyworkerroster/repository"
+	"dailyworkerroster/rules"
+	"log"
+	"time"
+)
+
+type ShiftLifecycleServiceItf interface {
+	// Advance moves shifts and requests whose time has passed to their final
+	// state: approved requests become DONE, or NO_SHOW when the worker never
+	// clocked in, once clocking out is over, pending and
+	// waitlisted ones EXPIRED once it starts, and started shifts stop being
+	// available. Offers past their deadline expire and the place is offered
+	// to the next worker on the waitlist. A shift that fails is logged,
+	// counted and left as it was for the next run; the others go ahead.
+	Advance(ctx context.Context) (*model.ShiftLifecycleResult, error)
+}
+
+type ShiftLifecycleService struct {
+	ShiftRepo       repository.ShiftRepoItf
+	WorkerShiftRepo repository.WorkerShiftRepoItf
+	UnitOfWork      repository.UnitOfWorkItf
+	Config          config.ShiftConfig
+	Attendance      config.AttendanceConfig
+	Rules           *rules.Engine
+	Clock           clock.Clock
+}
+
+func NewShiftLifecycleService(
+	shiftRepo repository.ShiftRepoItf,
+	workerShiftRepo repository.WorkerShiftRepoItf,
+	unitOfWork repository.UnitOfWorkItf,
+	cfg config.ShiftConfig,
+	attendance config.AttendanceConfig,
+	engine *rules.Engine,
+	clk clock.Clock) ShiftLifecycleServiceItf {
+	return &ShiftLifecycleService{
+		ShiftRepo:       shiftRepo,
+		WorkerShiftRepo: workerShiftRepo,
+		UnitOfWork:      unitOfWork,
+		Config:          cfg,
+		Attendance:      attendance,
+		Rules:           engine,
+		Clock:           clk,
+	}
+}
+
+func (s *ShiftLifecycleService) Advance(ctx context.Context) (*model.ShiftLifecycleResult, error) {
+	funcName := "/service/shift_lifecycle/Advance"
+
+	now := s.Clock.Now()
+	// Shift dates are in the zone of their location, which may be a day
+	// ahead of the local one
+	dateTo := now.AddDate(0, 0, 1).Format(dateLayout)
+	result := &model.ShiftLifecycleResult{}
+
+	// Only shifts dated up to today can have started, and every run settles
+	// the ones it finds, so these lists stay small.
+	candidates := make(map[int64]bool)
+	for _, status := range []string{model.WORKER_SHIFT_PENDING, model.WORKER_SHIFT_WAITLISTED, model.WORKER_SHIFT_APPROVED} {
+		status := status
+		requests, err := s.WorkerShiftRepo.GetWorkerShiftDetailListByFilter(&model.WorkerShiftDetailQuery{
+			Status: &status,
+			DateTo: &dateTo,
+		})
+		if err != nil {
+			log.Printf("%s: GetWorkerShiftDetailListByFilter error: %v", funcName, err)
+			return nil, err
+		}
+		for _, ws := range requests {
+			candidates[ws.ShiftID] = true
+		}
+	}
+
+	// Offers may run out on any day before the shift
+	offered := model.WORKER_SHIFT_OFFERED
+	offers, err := s.WorkerShiftRepo.GetWorkerShiftListByFilter(nil, &offered)
+	if err != nil {
+		log.Printf("%s: GetWorkerShiftListByFilter error: %v", funcName, err)
+		return nil, err
+	}
+	for _, ws := range offers {
+		candidates[ws.ShiftID] = true
+	}
+
+	isAvailable := true
+	openShifts, err := s.ShiftRepo.GetListShifts(model.ShiftListQuery{IsAvailable: &isAvailable, DateTo: dateTo})
+	if err != nil {
+		log.Printf("%s: GetListShifts error: %v", funcName, err)
+		return nil, err
+	}
+	for _, shift := range openShifts {
+		candidates[shift.ID] = true
+	}
+
+	for shiftID := range candidates {
+		// Counted only once the shift's transaction commits
+		var shiftResult model.ShiftLifecycleResult
+		err := s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+			return advanceShift(repos, s.Rules, shiftID, now, s.Config.OfferTTL.Std(), s.Attendance.ClockOutGrace.Std(), &shiftResult)
+		})
+		if err != nil {
+			log.Printf("%s: advanceShift error for shift %d: %v", funcName, shiftID, err)
+			result.Failed++
+			continue
+		}
+		result.Add(shiftResult)
+	}
+
+	return result, nil
+}
+
+// advanceShift re-reads one shift under lock and applies the transitions
+// that are due at now.
+func advanceShift(repos *repository.Repositories, engine *rules.Engine, shiftID int64, now time.Time, offerTTL, clockOutGrace time.Duration, result *model.ShiftLifecycleResult) error {
+	shift, err := repos.Shift.GetShiftByIDForUpdate(shiftID)
+	if err != nil {
+		return err
+	}
+	start, end, err := rules.Bounds(shift)
+	if err != nil {
+		return err
+	}
+	workerShifts, err := repos.WorkerShift.ListWorkerShiftsByShift(shiftID)
+	if err != nil {
+		return err
+	}
+
+	if now.Before(start) {
+		expired := 0
+		for _, ws := range workerShifts {
+			if ws.Status != model.WORKER_SHIFT_OFFERED || ws.OfferExpiresAt == nil || now.Before(*ws.OfferExpiresAt) {
+				continue
+			}
+			if err := repos.WorkerShift.UpdatesWorkerShiftStatus(ws.ID, model.WORKER_SHIFT_EXPIRED, ws.ApprovedBy); err != nil {
+				return err
+			}
+			ws.Status = model.WORKER_SHIFT_EXPIRED
+			expired++
+		}
+		if expired == 0 {
+			return nil
+		}
+		result.OffersExpired += expired
+
+		offered, err := offerVacancies(repos, engine, shift, workerShifts, now, offerTTL)
+		if err != nil {
+			return err
+		}
+		result.Offered += len(offered)
+		return nil
+	}
+
+	for _, ws := range workerShifts {
+		switch {
+		case ws.Status == model.WORKER_SHIFT_PENDING || ws.Status == model.WORKER_SHIFT_WAITLISTED:
+			if err := repos.WorkerShift.UpdatesWorkerShiftStatus(ws.ID, model.WORKER_SHIFT_EXPIRED, ws.ApprovedBy); err != nil {
+				return err
+			}
+			result.Expired++
+		case ws.Status == model.WORKER_SHIFT_OFFERED:
+			if err := repos.WorkerShift.UpdatesWorkerShiftStatus(ws.ID, model.WORKER_SHIFT_EXPIRED, ws.ApprovedBy); err != nil {
+				return err
+			}
+			result.OffersExpired++
+		case ws.Status == model.WORKER_SHIFT_APPROVED && !now.Before(end.Add(clockOutGrace)):
+			status, err := settleAttendance(repos, ws, end)
+			if err != nil {
+				return err
+			}
+			if status == model.WORKER_SHIFT_NO_SHOW {
+				result.NoShows++
+			} else {
+				result.Done++
+			}
+		}
+	}
+
+	if shift.IsAvailable {
+		shift.IsAvailable = false
+		if err := repos.Shift.UpdateShiftByID(shift); err != nil {
+			return err
+		}
+		result.Closed++
+	}
+	return nil
+}
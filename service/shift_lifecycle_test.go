@@ -0,0 +1,147 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"dailyworkerroster/config"
+	"dailyworkerroster/model"
+	"dailyworkerroster/rules"
+	"dailyworkerroster/service"
+)
+
+func (f *fixture) lifecycle() service.ShiftLifecycleServiceItf {
+	return service.NewShiftLifecycleService(f.repos.Shift, f.repos.WorkerShift, f.uow,
+		f.cfg.Shift, f.cfg.Attendance, rules.NewEngine(f.cfg.Rules), f.clock)
+}
+
+func TestAdvanceExpiresRequestsOnceShiftStarts(t *testing.T) {
+	start := time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC)
+	f := newFixture(t, start.Add(-time.Minute))
+	shift := f.shift(t, start, 4*time.Hour)
+	pending := f.request(t, shift.ID, f.worker, model.WORKER_SHIFT_PENDING)
+	waitlisted := f.request(t, shift.ID, f.user(t, "bob", model.ROLE_WORKER), model.WORKER_SHIFT_WAITLISTED)
+
+	result, err := f.lifecycle().Advance(context.Background())
+	if err != nil {
+		t.Fatalf("Advance before start: %v", err)
+	}
+	if *result != (model.ShiftLifecycleResult{}) || f.status(t, pending) != model.WORKER_SHIFT_PENDING {
+		t.Fatalf("before start: result %+v, request %s; want nothing changed", result, f.status(t, pending))
+	}
+
+	f.clock.Set(start)
+	result, err = f.lifecycle().Advance(context.Background())
+	if err != nil {
+		t.Fatalf("Advance at start: %v", err)
+	}
+	if result.Expired != 2 || result.Closed != 1 {
+		t.Errorf("at start: result %+v, want 2 expired and 1 closed", result)
+	}
+	for _, id := range []int64{pending, waitlisted} {
+		if got := f.status(t, id); got != model.WORKER_SHIFT_EXPIRED {
+			t.Errorf("request %d is %s, want EXPIRED", id, got)
+		}
+	}
+	if f.isAvailable(t, shift.ID) {
+		t.Error("started shift is still available")
+	}
+}
+
+func TestAdvanceSettlesApprovedAfterClockOutGrace(t *testing.T) {
+	start := time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC)
+	end := start.Add(4 * time.Hour)
+	grace := config.Default().Attendance.ClockOutGrace.Std()
+	f := newFixture(t, end.Add(grace-time.Minute))
+	shift := f.shift(t, start, end.Sub(start))
+	worked := f.request(t, shift.ID, f.worker, model.WORKER_SHIFT_APPROVED)
+	missed := f.request(t, shift.ID, f.user(t, "bob", model.ROLE_WORKER), model.WORKER_SHIFT_APPROVED)
+	if _, err := f.repos.TimeEntry.CreateTimeEntry(&model.TimeEntry{WorkerShiftID: worked, ClockInAt: start}); err != nil {
+		t.Fatalf("CreateTimeEntry: %v", err)
+	}
+
+	result, err := f.lifecycle().Advance(context.Background())
+	if err != nil {
+		t.Fatalf("Advance within grace: %v", err)
+	}
+	if result.Done != 0 || result.NoShows != 0 || f.status(t, worked) != model.WORKER_SHIFT_APPROVED {
+		t.Fatalf("within grace: result %+v, request %s; want still approved", result, f.status(t, worked))
+	}
+
+	f.clock.Set(end.Add(grace))
+	result, err = f.lifecycle().Advance(context.Background())
+	if err != nil {
+		t.Fatalf("Advance after grace: %v", err)
+	}
+	if result.Done != 1 || result.NoShows != 1 {
+		t.Errorf("after grace: result %+v, want 1 done and 1 no-show", result)
+	}
+	if got := f.status(t, worked); got != model.WORKER_SHIFT_DONE {
+		t.Errorf("clocked in request is %s, want DONE", got)
+	}
+	if got := f.status(t, missed); got != model.WORKER_SHIFT_NO_SHOW {
+		t.Errorf("request without a time entry is %s, want NO_SHOW", got)
+	}
+
+	entry, err := f.repos.TimeEntry.GetTimeEntryByWorkerShiftID(worked)
+	if err != nil {
+		t.Fatalf("GetTimeEntryByWorkerShiftID: %v", err)
+	}
+	if entry.ClockOutAt == nil || !entry.ClockOutAt.Equal(end) || !entry.AutoClockOut {
+		t.Errorf("time entry clocked out at %v, auto %v; want auto clock-out at %v", entry.ClockOutAt, entry.AutoClockOut, end)
+	}
+}
+
+func TestAdvanceLeavesUpcomingShifts(t *testing.T) {
+	now := time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC)
+	f := newFixture(t, now)
+	// Dated today in the store's zone, but starting later
+	shift := f.shift(t, now.Add(3*time.Hour), 4*time.Hour)
+	pending := f.request(t, shift.ID, f.worker, model.WORKER_SHIFT_PENDING)
+	approved := f.request(t, shift.ID, f.user(t, "bob", model.ROLE_WORKER), model.WORKER_SHIFT_APPROVED)
+
+	result, err := f.lifecycle().Advance(context.Background())
+	if err != nil {
+		t.Fatalf("Advance: %v", err)
+	}
+	if *result != (model.ShiftLifecycleResult{}) {
+		t.Errorf("result %+v, want nothing changed", result)
+	}
+	if got := f.status(t, pending); got != model.WORKER_SHIFT_PENDING {
+		t.Errorf("pending request is %s", got)
+	}
+	if got := f.status(t, approved); got != model.WORKER_SHIFT_APPROVED {
+		t.Errorf("approved request is %s", got)
+	}
+	if !f.isAvailable(t, shift.ID) {
+		t.Error("upcoming shift was closed")
+	}
+}
+
+func TestAdvanceGoesOnAfterShiftFails(t *testing.T) {
+	start := time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC)
+	f := newFixture(t, start)
+	broken := f.shift(t, start, 4*time.Hour)
+	stuck := f.request(t, broken.ID, f.worker, model.WORKER_SHIFT_PENDING)
+	f.failUpdates(t, broken.ID)
+	shift := f.shift(t, start, 4*time.Hour)
+	pending := f.request(t, shift.ID, f.user(t, "bob", model.ROLE_WORKER), model.WORKER_SHIFT_PENDING)
+
+	result, err := f.lifecycle().Advance(context.Background())
+	if err != nil {
+		t.Fatalf("Advance: %v", err)
+	}
+	if result.Failed != 1 || result.Expired != 1 || result.Closed != 1 {
+		t.Errorf("result %+v, want 1 failed and the other shift expired and closed", result)
+	}
+	if got := f.status(t, pending); got != model.WORKER_SHIFT_EXPIRED {
+		t.Errorf("request on the good shift is %s, want EXPIRED", got)
+	}
+	if got := f.status(t, stuck); got != model.WORKER_SHIFT_PENDING {
+		t.Errorf("request on the failing shift is %s, want it left PENDING", got)
+	}
+	if !f.isAvailable(t, broken.ID) {
+		t.Error("failing shift was closed although its transaction failed")
+	}
+}
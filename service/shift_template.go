@@ -0,0 +1,559 @@
+package service
+
+import (
+	"context"
+	"dailyworkerroster/clock"
+	"dailyworkerroster/config"
+	errs "dailyworkerroster/error"
+	"dailyworkerroster/model"
+	"dailyworkerroster/repository"
+	"dailyworkerroster/rules"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	dateLayout = "2006-01-02"
+
+	defaultGenerationDays = 28
+	maxGenerationDays     = 366
+)
+
+var templateWeekdays = map[string]time.Weekday{
+	"MON": time.Monday,
+	"TUE": time.Tuesday,
+	"WED": time.Wednesday,
+	"THU": time.Thursday,
+	"FRI": time.Friday,
+	"SAT": time.Saturday,
+	"SUN": time.Sunday,
+}
+
+type ShiftTemplateServiceItf interface {
+	CreateTemplate(ctx context.Context, tpl *model.ShiftTemplate) (int64, error)
+	GetTemplates(ctx context.Context) ([]*model.ShiftTemplate, error)
+	GetTemplateByID(ctx context.Context, templateID int64) (*model.ShiftTemplate, error)
+	UpdateTemplate(ctx context.Context, tpl *model.ShiftTemplate, future string) (*model.ShiftGenerationResult, error)
+	GenerateShifts(ctx context.Context, templateID int64, until string) (*model.ShiftGenerationResult, error)
+}
+
+type ShiftTemplateService struct {
+	ShiftTemplateRepo repository.ShiftTemplateRepoItf
+	LocationRepo      repository.LocationRepoItf
+	SkillRepo         repository.SkillRepoItf
+	UnitOfWork        repository.UnitOfWorkItf
+	Config            config.ShiftConfig
+	Rules             *rules.Engine
+	Zone              *time.Location // zone of locations without one
+	Clock             clock.Clock
+}
+
+func NewShiftTemplateService(
+	shiftTemplateRepo repository.ShiftTemplateRepoItf,
+	locationRepo repository.LocationRepoItf,
+	skillRepo repository.SkillRepoItf,
+	unitOfWork repository.UnitOfWorkItf,
+	cfg config.ShiftConfig,
+	engine *rules.Engine,
+	zone *time.Location,
+	clk clock.Clock) ShiftTemplateServiceItf {
+	return &ShiftTemplateService{
+		ShiftTemplateRepo: shiftTemplateRepo,
+		LocationRepo:      locationRepo,
+		SkillRepo:         skillRepo,
+		UnitOfWork:        unitOfWork,
+		Config:            cfg,
+		Rules:             engine,
+		Zone:              zone,
+		Clock:             clk,
+	}
+}
+
+func (s *ShiftTemplateService) CreateTemplate(ctx context.Context, tpl *model.ShiftTemplate) (int64, error) {
+	funcName := "/service/shift_template/CreateTemplate"
+
+	if err := normalizeShiftTemplate(tpl); err != nil {
+		return 0, err
+	}
+	if err := validateRole(s.SkillRepo, &tpl.RoleAssignment, ""); err != nil {
+		log.Printf("%s: validateRole error: %v", funcName, err)
+		return 0, err
+	}
+	location, err := resolveLocation(s.LocationRepo, tpl.LocationID, tpl.Location, 0)
+	if err != nil {
+		log.Printf("%s: resolveLocation error: %v", funcName, err)
+		return 0, err
+	}
+	tpl.LocationID, tpl.Location = location.ID, location.Name
+
+	id, err := s.ShiftTemplateRepo.CreateShiftTemplate(tpl)
+	if err != nil {
+		log.Printf("%s: CreateShiftTemplate error: %v", funcName, err)
+		return 0, err
+	}
+	return id, nil
+}
+
+func (s *ShiftTemplateService) GetTemplates(ctx context.Context) ([]*model.ShiftTemplate, error) {
+	funcName := "/service/shift_template/GetTemplates"
+
+	templates, err := s.ShiftTemplateRepo.ListShiftTemplates()
+	if err != nil {
+		log.Printf("%s: ListShiftTemplates error: %v", funcName, err)
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (s *ShiftTemplateService) GetTemplateByID(ctx context.Context, templateID int64) (*model.ShiftTemplate, error) {
+	funcName := "/service/shift_template/GetTemplateByID"
+
+	tpl, err := s.ShiftTemplateRepo.GetShiftTemplateByID(templateID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errs.ErrShiftTemplateNotFound
+	}
+	if err != nil {
+		log.Printf("%s: GetShiftTemplateByID error: %v", funcName, err)
+		return nil, err
+	}
+	return tpl, nil
+}
+
+// UpdateTemplate saves the template and then applies the future mode to
+// the shifts it already generated that have not started yet:
+//   - keep leaves them untouched,
+//   - update carries the template's changes over to them (adding or
+//     dropping dates), except where an admin edited a shift by hand,
+//   - replace deletes them and generates fresh ones.
+//
+// Both update and replace stop at the furthest date generated so far and
+// never move or delete a shift that a worker holds or held a place on, nor
+// delete one with requests still waiting for an answer; those are reported
+// as conflicts instead.
+func (s *ShiftTemplateService) UpdateTemplate(ctx context.Context, tpl *model.ShiftTemplate, future string) (*model.ShiftGenerationResult, error) {
+	funcName := "/service/shift_template/UpdateTemplate"
+
+	if future == "" {
+		future = model.TEMPLATE_FUTURE_KEEP
+	}
+	if future != model.TEMPLATE_FUTURE_KEEP && future != model.TEMPLATE_FUTURE_UPDATE && future != model.TEMPLATE_FUTURE_REPLACE {
+		return nil, fmt.Errorf("%w: unknown future mode %q", errs.ErrInvalidShiftTemplate, future)
+	}
+	if err := normalizeShiftTemplate(tpl); err != nil {
+		return nil, err
+	}
+
+	today := s.Clock.Now().In(s.Zone).Format(dateLayout)
+	result := &model.ShiftGenerationResult{TemplateID: tpl.ID, From: today}
+
+	err := s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		current, err := repos.ShiftTemplate.GetShiftTemplateByIDForUpdate(tpl.ID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return errs.ErrShiftTemplateNotFound
+		}
+		if err != nil {
+			log.Printf("%s: GetShiftTemplateByIDForUpdate error: %v", funcName, err)
+			return err
+		}
+
+		if err := validateRole(repos.Skill, &tpl.RoleAssignment, current.RoleAssignment); err != nil {
+			log.Printf("%s: validateRole error: %v", funcName, err)
+			return err
+		}
+		location, err := resolveLocation(repos.Location, tpl.LocationID, tpl.Location, current.LocationID)
+		if err != nil {
+			log.Printf("%s: resolveLocation error: %v", funcName, err)
+			return err
+		}
+		tpl.LocationID, tpl.Location = location.ID, location.Name
+
+		if err := repos.ShiftTemplate.UpdateShiftTemplate(tpl); err != nil {
+			log.Printf("%s: UpdateShiftTemplate error: %v", funcName, err)
+			return err
+		}
+
+		if future == model.TEMPLATE_FUTURE_KEEP {
+			return nil
+		}
+
+		generated, err := repos.Shift.GetListShifts(model.ShiftListQuery{TemplateID: &tpl.ID, DateFrom: today})
+		if err != nil {
+			log.Printf("%s: GetListShifts error: %v", funcName, err)
+			return err
+		}
+		if len(generated) == 0 {
+			return nil
+		}
+		result.Until = generated[len(generated)-1].Date
+
+		if err := s.syncTemplateShifts(repos, current, tpl, result, future == model.TEMPLATE_FUTURE_REPLACE); err != nil {
+			log.Printf("%s: syncTemplateShifts error: %v", funcName, err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GenerateShifts materialises the template into shifts from today up to and
+// including until (default four weeks ahead), leaving out shifts that would
+// already have started. It is idempotent: dates that already have a
+// generated shift keep it as it is.
+func (s *ShiftTemplateService) GenerateShifts(ctx context.Context, templateID int64, until string) (*model.ShiftGenerationResult, error) {
+	funcName := "/service/shift_template/GenerateShifts"
+
+	now := s.Clock.Now().In(s.Zone)
+	today := now.Format(dateLayout)
+	if until == "" {
+		until = now.AddDate(0, 0, defaultGenerationDays).Format(dateLayout)
+	}
+	untilDate, err := time.Parse(dateLayout, until)
+	if err != nil {
+		return nil, fmt.Errorf("%w: until must be YYYY-MM-DD", errs.ErrInvalidShiftTemplate)
+	}
+	if untilDate.Format(dateLayout) < today {
+		return nil, fmt.Errorf("%w: until is in the past", errs.ErrInvalidShiftTemplate)
+	}
+	if untilDate.After(now.AddDate(0, 0, maxGenerationDays)) {
+		return nil, fmt.Errorf("%w: until is more than %d days ahead", errs.ErrInvalidShiftTemplate, maxGenerationDays)
+	}
+
+	result := &model.ShiftGenerationResult{TemplateID: templateID, From: today, Until: until}
+
+	err = s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		tpl, err := repos.ShiftTemplate.GetShiftTemplateByIDForUpdate(templateID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return errs.ErrShiftTemplateNotFound
+		}
+		if err != nil {
+			log.Printf("%s: GetShiftTemplateByIDForUpdate error: %v", funcName, err)
+			return err
+		}
+		if !tpl.IsActive {
+			return errs.ErrShiftTemplateInactive
+		}
+
+		if err := s.syncTemplateShifts(repos, nil, tpl, result, false); err != nil {
+			log.Printf("%s: syncTemplateShifts error: %v", funcName, err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// syncTemplateShifts makes the template's shifts between result.From and
+// result.Until match its recurrence rule. Shifts that have started are left
+// alone and none are generated after the fact. Without a previous version
+// of the template only missing dates are generated. Otherwise a field of a
+// shift follows the template only while it still holds the previous
+// version's value, so an admin's edits survive, and a shift closed by hand
+// stays closed; with replace every existing shift is deleted and generated
+// again instead.
+func (s *ShiftTemplateService) syncTemplateShifts(repos *repository.Repositories, previous, tpl *model.ShiftTemplate, result *model.ShiftGenerationResult, replace bool) error {
+	now := s.Clock.Now()
+	desired := make(map[string]bool)
+	for _, date := range templateDates(tpl, result.From, result.Until) {
+		desired[date] = true
+	}
+	location, err := repos.Location.GetLocationByID(tpl.LocationID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: the template has no location", errs.ErrInvalidLocation)
+	}
+	if err != nil {
+		return err
+	}
+	loc, err := clock.LoadLocation(location.TimeZone, s.Zone)
+	if err != nil {
+		return err
+	}
+
+	existing, err := repos.Shift.GetListShifts(model.ShiftListQuery{TemplateID: &tpl.ID, DateFrom: result.From})
+	if err != nil {
+		return err
+	}
+
+	covered := make(map[string]bool)
+	for _, shift := range existing {
+		if shift.Date > result.Until {
+			continue
+		}
+		if previous == nil || !now.Before(shift.StartAt) {
+			covered[shift.Date] = true
+			continue
+		}
+
+		workerShifts, err := repos.WorkerShift.ListWorkerShiftsByShift(shift.ID)
+		if err != nil {
+			return err
+		}
+		filled, recorded := 0, false
+		for _, ws := range workerShifts {
+			if holdsPlace(ws) {
+				filled++
+			}
+			recorded = recorded || onRecord(ws)
+		}
+
+		// A shift an admin moved to a date of their own is not the
+		// template's to drop
+		if replace || (!desired[shift.Date] && templateRecursOn(previous, shift.Date)) {
+			if err := checkShiftRemovable(workerShifts); err != nil {
+				covered[shift.Date] = true
+				result.Conflicts = append(result.Conflicts, model.ShiftGenerationConflict{
+					ShiftID: shift.ID, Date: shift.Date, Reason: err.Error(),
+				})
+				continue
+			}
+			if err := repos.WorkerShift.DeleteWorkerShiftsByShift(shift.ID); err != nil {
+				return err
+			}
+			if err := repos.Shift.DeleteShiftByID(shift.ID); err != nil {
+				return err
+			}
+			result.Deleted = append(result.Deleted, shift.ID)
+			continue
+		}
+
+		covered[shift.Date] = true
+		if !desired[shift.Date] {
+			continue
+		}
+		target, err := carryOver(previous, tpl, shift, loc)
+		if err != nil {
+			return err
+		}
+		if sameSlot(shift, target) && shift.Headcount == target.Headcount {
+			continue
+		}
+		if recorded && !sameSlot(shift, target) {
+			result.Conflicts = append(result.Conflicts, model.ShiftGenerationConflict{
+				ShiftID: shift.ID, Date: shift.Date, Reason: errs.ErrShiftHasAssignments.Error(),
+			})
+			continue
+		}
+		if target.Headcount < filled {
+			result.Conflicts = append(result.Conflicts, model.ShiftGenerationConflict{
+				ShiftID: shift.ID, Date: shift.Date, Reason: errs.ErrHeadcountBelowFilled.Error(),
+			})
+			continue
+		}
+
+		closedByHand := !shift.IsAvailable && filled < shift.Headcount
+		target.IsAvailable = !closedByHand && filled < target.Headcount
+		if err := repos.Shift.UpdateShiftByID(target); err != nil {
+			return err
+		}
+		result.Updated = append(result.Updated, shift.ID)
+
+		// As when an admin edits the shift: a full shift waitlists its
+		// pending requests, new places go to the waitlist
+		if filled >= target.Headcount {
+			if _, err := closeIfFull(repos, target, workerShifts); err != nil {
+				return err
+			}
+		} else if target.IsAvailable && target.Headcount > shift.Headcount {
+			if _, err := offerVacancies(repos, s.Rules, target, workerShifts, now, s.Config.OfferTTL.Std()); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, date := range templateDates(tpl, result.From, result.Until) {
+		if covered[date] {
+			continue
+		}
+		if !location.Active {
+			return fmt.Errorf("%w: location %q is inactive", errs.ErrInvalidLocation, location.Name)
+		}
+		shift, err := shiftFromTemplate(tpl, date, loc)
+		if err != nil {
+			return err
+		}
+		if !now.Before(shift.StartAt) {
+			continue
+		}
+		id, err := repos.Shift.CreateShift(shift)
+		if err != nil {
+			return err
+		}
+		result.Created = append(result.Created, id)
+	}
+
+	return nil
+}
+
+// carryOver returns the shift, generated from previous, with the changes
+// made in tpl; fields that no longer hold previous's value were edited by
+// hand and keep their value. loc is the zone of tpl's location.
+func carryOver(previous, tpl *model.ShiftTemplate, shift *model.Shift, loc *time.Location) (*model.Shift, error) {
+	target := *shift
+	target.StartAt, target.EndAt = time.Time{}, time.Time{}
+	if shift.StartTime == previous.StartTime && shift.EndTime == previous.EndTime {
+		target.StartTime, target.EndTime = tpl.StartTime, tpl.EndTime
+	}
+	if shift.RoleAssignment == previous.RoleAssignment {
+		target.RoleAssignment = tpl.RoleAssignment
+	}
+	if shift.LocationID == previous.LocationID {
+		target.LocationID, target.Location = tpl.LocationID, tpl.Location
+	}
+	if shift.Headcount == previous.Headcount {
+		target.Headcount = tpl.Headcount
+	}
+
+	if target.LocationID != tpl.LocationID {
+		loc = shift.StartAt.Location()
+	}
+	if err := normalizeShiftTimes(&target, loc); err != nil {
+		return nil, err
+	}
+	return &target, nil
+}
+
+func shiftFromTemplate(tpl *model.ShiftTemplate, date string, loc *time.Location) (*model.Shift, error) {
+	templateID := tpl.ID
+	shift := &model.Shift{
+		Date:           date,
+		StartTime:      tpl.StartTime,
+		EndTime:        tpl.EndTime,
+		RoleAssignment: tpl.RoleAssignment,
+		LocationID:     tpl.LocationID,
+		Location:       tpl.Location,
+		IsAvailable:    true,
+		Headcount:      tpl.Headcount,
+		TemplateID:     &templateID,
+	}
+	if err := normalizeShiftTimes(shift, loc); err != nil {
+		return nil, err
+	}
+	return shift, nil
+}
+
+// templateDates lists the dates in [from, until] on which the template
+// recurs. Inactive templates recur on no date.
+func templateDates(tpl *model.ShiftTemplate, from, until string) []string {
+	if !tpl.IsActive {
+		return nil
+	}
+	if tpl.StartDate > from {
+		from = tpl.StartDate
+	}
+	if tpl.EndDate != nil && *tpl.EndDate < until {
+		until = *tpl.EndDate
+	}
+
+	weekdays := make(map[time.Weekday]bool)
+	for _, day := range tpl.Weekdays {
+		weekdays[templateWeekdays[day]] = true
+	}
+	excluded := make(map[string]bool)
+	for _, date := range tpl.ExcludedDates {
+		excluded[date] = true
+	}
+
+	start, err := time.Parse(dateLayout, from)
+	if err != nil {
+		return nil
+	}
+	end, err := time.Parse(dateLayout, until)
+	if err != nil {
+		return nil
+	}
+
+	var dates []string
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		date := day.Format(dateLayout)
+		if weekdays[day.Weekday()] && !excluded[date] {
+			dates = append(dates, date)
+		}
+	}
+	return dates
+}
+
+// templateRecursOn reports whether the template recurs on date
+func templateRecursOn(tpl *model.ShiftTemplate, date string) bool {
+	return len(templateDates(tpl, date, date)) > 0
+}
+
+func normalizeShiftTemplate(tpl *model.ShiftTemplate) error {
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: %s", errs.ErrInvalidShiftTemplate, fmt.Sprintf(format, args...))
+	}
+
+	if strings.TrimSpace(tpl.Name) == "" {
+		return invalid("name is required")
+	}
+	if tpl.Headcount == 0 {
+		tpl.Headcount = 1
+	}
+	if tpl.Headcount < 0 {
+		return errs.ErrInvalidHeadcount
+	}
+
+	start, err := parseClock(tpl.StartTime)
+	if err != nil {
+		return invalid("start_time must be HH:MM")
+	}
+	end, err := parseClock(tpl.EndTime)
+	if err != nil {
+		return invalid("end_time must be HH:MM")
+	}
+	// An end time before the start time ends the shift on the next day
+	if end.Equal(start) {
+		return invalid("end_time must differ from start_time")
+	}
+	// Stored the way MySQL returns TIME values so generated shifts compare equal
+	tpl.StartTime = start.Format("15:04:05")
+	tpl.EndTime = end.Format("15:04:05")
+
+	if len(tpl.Weekdays) == 0 {
+		return invalid("at least one weekday is required")
+	}
+	for i, day := range tpl.Weekdays {
+		day = strings.ToUpper(strings.TrimSpace(day))
+		if _, ok := templateWeekdays[day]; !ok {
+			return invalid("unknown weekday %q", tpl.Weekdays[i])
+		}
+		tpl.Weekdays[i] = day
+	}
+
+	if _, err := time.Parse(dateLayout, tpl.StartDate); err != nil {
+		return invalid("start_date must be YYYY-MM-DD")
+	}
+	if tpl.EndDate != nil {
+		if _, err := time.Parse(dateLayout, *tpl.EndDate); err != nil {
+			return invalid("end_date must be YYYY-MM-DD")
+		}
+		if *tpl.EndDate < tpl.StartDate {
+			return invalid("end_date must not be before start_date")
+		}
+	}
+	for _, date := range tpl.ExcludedDates {
+		if _, err := time.Parse(dateLayout, date); err != nil {
+			return invalid("excluded date %q must be YYYY-MM-DD", date)
+		}
+	}
+	if tpl.ExcludedDates == nil {
+		tpl.ExcludedDates = []string{}
+	}
+	return nil
+}
+
+// parseClock accepts HH:MM and HH:MM:SS
+func parseClock(value string) (time.Time, error) {
+	if t, err := time.Parse("15:04", value); err == nil {
+		return t, nil
+	}
+	return time.Parse("15:04:05", value)
+}
@@ -0,0 +1,194 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	errs "dailyworkerroster/error"
+	"dailyworkerroster/model"
+	"dailyworkerroster/rules"
+	"dailyworkerroster/service"
+)
+
+func (f *fixture) templateService() service.ShiftTemplateServiceItf {
+	return service.NewShiftTemplateService(f.repos.ShiftTemplate, f.repos.Location, f.repos.Skill, f.uow,
+		f.cfg.Shift, rules.NewEngine(f.cfg.Rules), time.UTC, f.clock)
+}
+
+// dailyTemplate creates a template for a cleaner at the store every day
+// from 07:00 to 15:00, starting on from.
+func (f *fixture) dailyTemplate(t *testing.T, from string) *model.ShiftTemplate {
+	t.Helper()
+
+	tpl := &model.ShiftTemplate{
+		Name:           "Morning",
+		StartTime:      "07:00",
+		EndTime:        "15:00",
+		RoleAssignment: "CLEANER",
+		LocationID:     f.store,
+		Headcount:      1,
+		Weekdays:       []string{"MON", "TUE", "WED", "THU", "FRI", "SAT", "SUN"},
+		StartDate:      from,
+		IsActive:       true,
+	}
+	id, err := f.templateService().CreateTemplate(context.Background(), tpl)
+	if err != nil {
+		t.Fatalf("CreateTemplate: %v", err)
+	}
+	tpl.ID = id
+	return tpl
+}
+
+// generated returns the template's shifts by date.
+func (f *fixture) generated(t *testing.T, templateID int64) map[string]*model.Shift {
+	t.Helper()
+
+	shifts, err := f.repos.Shift.GetListShifts(model.ShiftListQuery{TemplateID: &templateID})
+	if err != nil {
+		t.Fatalf("GetListShifts: %v", err)
+	}
+	byDate := make(map[string]*model.Shift)
+	for _, shift := range shifts {
+		byDate[shift.Date] = shift
+	}
+	return byDate
+}
+
+func TestGenerateShiftsLeavesStartedAndExistingShifts(t *testing.T) {
+	// 08:00 in Amsterdam, today's shift is under way
+	now := time.Date(2026, 10, 16, 6, 0, 0, 0, time.UTC)
+	f := newFixture(t, now)
+	tpl := f.dailyTemplate(t, "2026-10-16")
+	svc := f.templateService()
+
+	result, err := svc.GenerateShifts(context.Background(), tpl.ID, "2026-10-18")
+	if err != nil {
+		t.Fatalf("GenerateShifts: %v", err)
+	}
+	if len(result.Created) != 2 {
+		t.Errorf("created %v, want the shifts of the 17th and 18th", result.Created)
+	}
+	shifts := f.generated(t, tpl.ID)
+	if _, ok := shifts["2026-10-16"]; ok {
+		t.Error("a shift was generated after it started")
+	}
+
+	// Edited by hand
+	edited := shifts["2026-10-17"]
+	edited.Headcount = 3
+	edited.IsAvailable = false
+	if err := f.repos.Shift.UpdateShiftByID(edited); err != nil {
+		t.Fatalf("UpdateShiftByID: %v", err)
+	}
+
+	result, err = svc.GenerateShifts(context.Background(), tpl.ID, "2026-10-19")
+	if err != nil {
+		t.Fatalf("GenerateShifts again: %v", err)
+	}
+	if len(result.Created) != 1 || len(result.Updated) != 0 || len(result.Deleted) != 0 {
+		t.Errorf("result %+v, want only the 19th created", result)
+	}
+	shift := f.generated(t, tpl.ID)["2026-10-17"]
+	if shift.Headcount != 3 || shift.IsAvailable {
+		t.Errorf("edited shift has headcount %d and is available %v, want 3 and closed", shift.Headcount, shift.IsAvailable)
+	}
+}
+
+func TestUpdateTemplateKeepsManualEdits(t *testing.T) {
+	now := time.Date(2026, 10, 16, 6, 0, 0, 0, time.UTC)
+	f := newFixture(t, now)
+	tpl := f.dailyTemplate(t, "2026-10-16")
+	svc := f.templateService()
+	if _, err := svc.GenerateShifts(context.Background(), tpl.ID, "2026-10-19"); err != nil {
+		t.Fatalf("GenerateShifts: %v", err)
+	}
+	shifts := f.generated(t, tpl.ID)
+	raised, closed := shifts["2026-10-17"], shifts["2026-10-18"]
+	raised.Headcount = 3
+	closed.IsAvailable = false
+	for _, shift := range []*model.Shift{raised, closed} {
+		if err := f.repos.Shift.UpdateShiftByID(shift); err != nil {
+			t.Fatalf("UpdateShiftByID: %v", err)
+		}
+	}
+
+	tpl.StartTime, tpl.Headcount = "08:00", 2
+	result, err := svc.UpdateTemplate(context.Background(), tpl, model.TEMPLATE_FUTURE_UPDATE)
+	if err != nil {
+		t.Fatalf("UpdateTemplate: %v", err)
+	}
+	if len(result.Updated) != 3 || len(result.Conflicts) != 0 {
+		t.Errorf("result %+v, want 3 shifts updated", result)
+	}
+
+	shifts = f.generated(t, tpl.ID)
+	tests := []struct {
+		date      string
+		headcount int
+		available bool
+	}{
+		{"2026-10-17", 3, true},
+		{"2026-10-18", 2, false},
+		{"2026-10-19", 2, true},
+	}
+	for _, tt := range tests {
+		shift := shifts[tt.date]
+		if shift.StartTime != "08:00:00" {
+			t.Errorf("%s starts at %s, want 08:00:00", tt.date, shift.StartTime)
+		}
+		if shift.Headcount != tt.headcount || shift.IsAvailable != tt.available {
+			t.Errorf("%s has headcount %d and is available %v, want %d and %v",
+				tt.date, shift.Headcount, shift.IsAvailable, tt.headcount, tt.available)
+		}
+	}
+}
+
+func TestUpdateTemplateRaisingHeadcountOffersWaitlist(t *testing.T) {
+	now := time.Date(2026, 10, 16, 6, 0, 0, 0, time.UTC)
+	f := newFixture(t, now)
+	tpl := f.dailyTemplate(t, "2026-10-17")
+	svc := f.templateService()
+	if _, err := svc.GenerateShifts(context.Background(), tpl.ID, "2026-10-17"); err != nil {
+		t.Fatalf("GenerateShifts: %v", err)
+	}
+	shift := f.generated(t, tpl.ID)["2026-10-17"]
+	shift.IsAvailable = false
+	if err := f.repos.Shift.UpdateShiftByID(shift); err != nil {
+		t.Fatalf("UpdateShiftByID: %v", err)
+	}
+	bob := f.user(t, "bob", model.ROLE_WORKER)
+	f.request(t, shift.ID, f.worker, model.WORKER_SHIFT_APPROVED)
+	waitlisted := f.request(t, shift.ID, bob, model.WORKER_SHIFT_WAITLISTED)
+
+	tpl.Headcount = 2
+	if _, err := svc.UpdateTemplate(context.Background(), tpl, model.TEMPLATE_FUTURE_UPDATE); err != nil {
+		t.Fatalf("UpdateTemplate: %v", err)
+	}
+	if got := f.status(t, waitlisted); got != model.WORKER_SHIFT_OFFERED {
+		t.Errorf("waitlisted request is %s, want OFFERED", got)
+	}
+}
+
+func TestUpdateShiftOntoTakenTemplateDate(t *testing.T) {
+	now := time.Date(2026, 10, 16, 6, 0, 0, 0, time.UTC)
+	f := newFixture(t, now)
+	tpl := f.dailyTemplate(t, "2026-10-17")
+	if _, err := f.templateService().GenerateShifts(context.Background(), tpl.ID, "2026-10-18"); err != nil {
+		t.Fatalf("GenerateShifts: %v", err)
+	}
+	shift := f.generated(t, tpl.ID)["2026-10-17"]
+
+	moved := &model.Shift{
+		ID:             shift.ID,
+		Date:           "2026-10-18",
+		StartTime:      shift.StartTime,
+		EndTime:        shift.EndTime,
+		RoleAssignment: shift.RoleAssignment,
+		LocationID:     shift.LocationID,
+	}
+	if err := f.shiftService().UpdateShift(context.Background(), moved, nil); !errors.Is(err, errs.ErrTemplateDateTaken) {
+		t.Errorf("UpdateShift onto a taken date: err = %v, want ErrTemplateDateTaken", err)
+	}
+}
@@ -0,0 +1,217 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"dailyworkerroster/config"
+	errs "dailyworkerroster/error"
+	"dailyworkerroster/model"
+	"dailyworkerroster/rules"
+	"dailyworkerroster/service"
+)
+
+func (f *fixture) shiftService() service.ShiftServiceItf {
+	return service.NewShiftService(f.repos.Shift, f.repos.WorkerShift, f.repos.Location, f.repos.Skill,
+		f.repos.Availability, f.uow, f.cfg.Shift, rules.NewEngine(f.cfg.Rules), time.UTC, f.clock)
+}
+
+func TestStartedShiftTakesNoRequestsOrApprovals(t *testing.T) {
+	start := time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC)
+	f := newFixture(t, start.Add(-time.Hour))
+	shift := f.shift(t, start, 4*time.Hour)
+	svc := f.shiftService()
+
+	if err := svc.RequestShift(context.Background(), shift.ID, f.worker); err != nil {
+		t.Fatalf("RequestShift before start: %v", err)
+	}
+
+	f.clock.Set(start)
+	late := f.user(t, "bob", model.ROLE_WORKER)
+	if err := svc.RequestShift(context.Background(), shift.ID, late); !errors.Is(err, errs.ErrShiftStarted) {
+		t.Errorf("RequestShift at start: err = %v, want ErrShiftStarted", err)
+	}
+	if _, err := svc.ApproveShiftRequest(context.Background(), shift.ID, f.worker); !errors.Is(err, errs.ErrShiftStarted) {
+		t.Errorf("ApproveShiftRequest at start: err = %v, want ErrShiftStarted", err)
+	}
+
+	requests, err := f.repos.WorkerShift.ListWorkerShiftsByShift(shift.ID)
+	if err != nil {
+		t.Fatalf("ListWorkerShiftsByShift: %v", err)
+	}
+	if len(requests) != 1 || requests[0].Status != model.WORKER_SHIFT_PENDING {
+		t.Errorf("requests after start = %+v, want only the pending one", requests)
+	}
+}
+
+func TestWorkerHoursUseCapOfShiftRole(t *testing.T) {
+	// A Friday; the shift falls in the current week, the next week is empty
+	now := time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC)
+	f := newFixture(t, now)
+	roleCap := 20.0
+	f.cfg.Rules.Roles = map[string]config.RuleSet{"CLEANER": {MaxHoursPerWeek: &roleCap}}
+	shift := f.shift(t, now.Add(24*time.Hour), 4*time.Hour)
+	f.request(t, shift.ID, f.worker, model.WORKER_SHIFT_APPROVED)
+
+	hours, err := f.shiftService().GetWorkerHours(context.Background(), f.worker)
+	if err != nil {
+		t.Fatalf("GetWorkerHours: %v", err)
+	}
+	if len(hours.Weeks) != 2 {
+		t.Fatalf("got %d weeks, want 2", len(hours.Weeks))
+	}
+	this, next := hours.Weeks[0], hours.Weeks[1]
+	if this.BookedHours != 4 || this.MaxHours == nil || *this.MaxHours != roleCap ||
+		this.RemainingHours == nil || *this.RemainingHours != roleCap-4 {
+		t.Errorf("this week = %+v, want 4 hours booked against the cleaner cap of %v", this, roleCap)
+	}
+	if next.MaxHours == nil || *next.MaxHours != *f.cfg.Rules.Default.MaxHoursPerWeek {
+		t.Errorf("next week cap = %v, want the default %v", next.MaxHours, *f.cfg.Rules.Default.MaxHoursPerWeek)
+	}
+}
+
+func TestApproveShiftRequestRollsBackOnFailure(t *testing.T) {
+	now := time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC)
+	f := newFixture(t, now)
+	bob := f.user(t, "bob", model.ROLE_WORKER)
+	shift := f.shift(t, now.Add(24*time.Hour), 4*time.Hour)
+	approved := f.request(t, shift.ID, f.worker, model.WORKER_SHIFT_PENDING)
+	other := f.request(t, shift.ID, bob, model.WORKER_SHIFT_PENDING)
+
+	// Waitlisting the other request, the last step, fails
+	_, err := f.db.Exec(fmt.Sprintf(`CREATE TRIGGER fail_waitlist BEFORE UPDATE ON worker_shift
+		WHEN NEW.id = %d BEGIN SELECT RAISE(ABORT, 'update failed'); END`, other))
+	if err != nil {
+		t.Fatalf("create trigger: %v", err)
+	}
+
+	if _, err := f.shiftService().ApproveShiftRequest(context.Background(), shift.ID, f.worker); err == nil {
+		t.Fatal("ApproveShiftRequest succeeded, want the failure")
+	}
+	if got := f.status(t, approved); got != model.WORKER_SHIFT_PENDING {
+		t.Errorf("request is %s, want the approval rolled back to PENDING", got)
+	}
+	if !f.isAvailable(t, shift.ID) {
+		t.Error("shift closed by a failed approval")
+	}
+}
+
+func TestConcurrentApprovalsFillShiftOnce(t *testing.T) {
+	now := time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC)
+	f := newFixture(t, now)
+	bob := f.user(t, "bob", model.ROLE_WORKER)
+	shift := f.shift(t, now.Add(24*time.Hour), 4*time.Hour)
+	f.request(t, shift.ID, f.worker, model.WORKER_SHIFT_PENDING)
+	f.request(t, shift.ID, bob, model.WORKER_SHIFT_PENDING)
+	svc := f.shiftService()
+
+	var wg sync.WaitGroup
+	results := make(chan error, 2)
+	for _, worker := range []int64{f.worker, bob} {
+		wg.Add(1)
+		go func(worker int64) {
+			defer wg.Done()
+			_, err := svc.ApproveShiftRequest(context.Background(), shift.ID, worker)
+			results <- err
+		}(worker)
+	}
+	wg.Wait()
+	close(results)
+
+	succeeded := 0
+	for err := range results {
+		if err == nil {
+			succeeded++
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("%d approvals succeeded, want 1", succeeded)
+	}
+	approved, err := f.repos.WorkerShift.CountWorkerShiftsByShiftIDs([]int64{shift.ID}, model.WORKER_SHIFT_APPROVED)
+	if err != nil {
+		t.Fatalf("CountWorkerShiftsByShiftIDs: %v", err)
+	}
+	if approved[shift.ID] != 1 {
+		t.Errorf("%d requests approved on a shift of headcount 1", approved[shift.ID])
+	}
+}
+
+func TestConcurrentRequestsFileOnce(t *testing.T) {
+	now := time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC)
+	f := newFixture(t, now)
+	shift := f.shift(t, now.Add(24*time.Hour), 4*time.Hour)
+	svc := f.shiftService()
+
+	var wg sync.WaitGroup
+	results := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results <- svc.RequestShift(context.Background(), shift.ID, f.worker)
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	var failures []error
+	for err := range results {
+		if err != nil {
+			failures = append(failures, err)
+		}
+	}
+	if len(failures) != 1 || !errors.Is(failures[0], errs.ErrAlreadyOnShift) {
+		t.Errorf("failures %v, want one ErrAlreadyOnShift", failures)
+	}
+	requests, err := f.repos.WorkerShift.ListWorkerShiftsByShift(shift.ID)
+	if err != nil {
+		t.Fatalf("ListWorkerShiftsByShift: %v", err)
+	}
+	if len(requests) != 1 {
+		t.Errorf("%d requests filed, want 1", len(requests))
+	}
+}
+
+func TestRejectShiftRequest(t *testing.T) {
+	now := time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC)
+	f := newFixture(t, now)
+	shift := f.shift(t, now.Add(24*time.Hour), 4*time.Hour)
+	pending := f.request(t, shift.ID, f.worker, model.WORKER_SHIFT_PENDING)
+	svc := f.shiftService()
+
+	if err := svc.RejectShiftRequest(context.Background(), shift.ID, f.worker); err != nil {
+		t.Fatalf("RejectShiftRequest: %v", err)
+	}
+	if got := f.status(t, pending); got != model.WORKER_SHIFT_REJECTED {
+		t.Errorf("request is %s, want REJECTED", got)
+	}
+	if err := svc.RejectShiftRequest(context.Background(), shift.ID, f.worker); !errors.Is(err, errs.ErrNoPendingRequest) {
+		t.Errorf("RejectShiftRequest of a decided request: err = %v, want ErrNoPendingRequest", err)
+	}
+	if err := svc.RejectShiftRequest(context.Background(), shift.ID+100, f.worker); !errors.Is(err, errs.ErrShiftNotFound) {
+		t.Errorf("RejectShiftRequest of a missing shift: err = %v, want ErrShiftNotFound", err)
+	}
+}
+
+func TestGetAvailableShiftsLeavesOutStartedShifts(t *testing.T) {
+	now := time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC)
+	f := newFixture(t, now)
+	started := f.shift(t, now.Add(-time.Hour), 4*time.Hour)
+	upcoming := f.shift(t, now.Add(time.Hour), 4*time.Hour)
+
+	shifts, err := f.shiftService().GetAvailableShifts(context.Background(), f.worker)
+	if err != nil {
+		t.Fatalf("GetAvailableShifts: %v", err)
+	}
+	if len(shifts) != 1 || shifts[0].ID != upcoming.ID {
+		ids := make([]int64, len(shifts))
+		for i, shift := range shifts {
+			ids[i] = shift.ID
+		}
+		t.Errorf("available shifts %v, want only %d and not the started %d", ids, upcoming.ID, started.ID)
+	}
+}
@@ -0,0 +1,418 @@
+package service
+
+import (
+	"context"
+	"dailyworkerroster/auth"
+	"dailyworkerroster/clock"
+	errs "dailyworkerroster/error"
+	"dailyworkerroster/model"
+	"dailyworkerroster/repository"
+	"dailyworkerroster/rules"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+)
+
+type ShiftTransferServiceItf interface {
+	// Worker
+	OfferShift(ctx context.Context, workerShiftID, workerID int64, toUserID *int64) (int64, error)
+	GetWorkerTransfers(ctx context.Context, workerID int64) (*model.WorkerTransfers, error)
+	AcceptTransfer(ctx context.Context, transferID, workerID int64) error
+	CancelTransfer(ctx context.Context, transferID, workerID int64) error
+
+	// Admin
+	GetTransfers(ctx context.Context, status *string) ([]model.ShiftTransferDetail, error)
+	ApproveTransfer(ctx context.Context, transferID int64) error
+	RejectTransfer(ctx context.Context, transferID int64) error
+	GetWorkerShiftHistory(ctx context.Context, workerShiftID int64) ([]model.WorkerShiftHistory, error)
+}
+
+type ShiftTransferService struct {
+	ShiftTransferRepo repository.ShiftTransferRepoItf
+	WorkerShiftRepo   repository.WorkerShiftRepoItf
+	UnitOfWork        repository.UnitOfWorkItf
+	Rules             *rules.Engine
+	Clock             clock.Clock
+}
+
+func NewShiftTransferService(
+	shiftTransferRepo repository.ShiftTransferRepoItf,
+	workerShiftRepo repository.WorkerShiftRepoItf,
+	unitOfWork repository.UnitOfWorkItf,
+	engine *rules.Engine,
+	clk clock.Clock) ShiftTransferServiceItf {
+	return &ShiftTransferService{
+		ShiftTransferRepo: shiftTransferRepo,
+		WorkerShiftRepo:   workerShiftRepo,
+		UnitOfWork:        unitOfWork,
+		Rules:             engine,
+		Clock:             clk,
+	}
+}
+
+// OfferShift posts an approved worker shift for transfer, either to the
+// named colleague or, when toUserID is nil, to any worker.
+func (s *ShiftTransferService) OfferShift(ctx context.Context, workerShiftID, workerID int64, toUserID *int64) (int64, error) {
+	funcName := "/service/shift_transfer/OfferShift"
+
+	if toUserID != nil && *toUserID == workerID {
+		return 0, fmt.Errorf("%w: cannot offer a shift to yourself", errs.ErrInvalidShiftTransfer)
+	}
+
+	var transferID int64
+	err := s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		ws, shift, err := lockTransferableWorkerShift(repos, workerShiftID, workerID, s.Clock.Now())
+		if err != nil {
+			log.Printf("%s: lockTransferableWorkerShift error: %v", funcName, err)
+			return err
+		}
+
+		active, err := repos.ShiftTransfer.ListActiveTransfersByWorkerShift(ws.ID)
+		if err != nil {
+			log.Printf("%s: ListActiveTransfersByWorkerShift error: %v", funcName, err)
+			return err
+		}
+		if len(active) > 0 {
+			return fmt.Errorf("%w: shift is already offered", errs.ErrShiftTransferState)
+		}
+
+		if toUserID != nil {
+			if err := checkTransferTaker(repos, s.Rules, shift, *toUserID); err != nil {
+				log.Printf("%s: checkTransferTaker error: %v", funcName, err)
+				return err
+			}
+		}
+
+		transferID, err = repos.ShiftTransfer.CreateShiftTransfer(&model.ShiftTransfer{
+			WorkerShiftID: ws.ID,
+			FromUserID:    workerID,
+			ToUserID:      toUserID,
+			Status:        model.TRANSFER_OFFERED,
+		})
+		if err != nil {
+			log.Printf("%s: CreateShiftTransfer error: %v", funcName, err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return transferID, nil
+}
+
+func (s *ShiftTransferService) GetWorkerTransfers(ctx context.Context, workerID int64) (*model.WorkerTransfers, error) {
+	funcName := "/service/shift_transfer/GetWorkerTransfers"
+
+	offered, err := s.ShiftTransferRepo.ListShiftTransfers(model.ShiftTransferQuery{FromUserID: &workerID})
+	if err != nil {
+		log.Printf("%s: ListShiftTransfers error: %v", funcName, err)
+		return nil, err
+	}
+
+	status := model.TRANSFER_OFFERED
+	available, err := s.ShiftTransferRepo.ListShiftTransfers(model.ShiftTransferQuery{Status: &status, OpenFor: &workerID})
+	if err != nil {
+		log.Printf("%s: ListShiftTransfers error: %v", funcName, err)
+		return nil, err
+	}
+
+	return &model.WorkerTransfers{
+		Offered:   nonNilTransfers(offered),
+		Available: nonNilTransfers(available),
+	}, nil
+}
+
+// AcceptTransfer records that workerID wants to take the offered shift. The
+// taker is checked against the same rules as a shift request, and checked
+// again when an admin approves the transfer.
+func (s *ShiftTransferService) AcceptTransfer(ctx context.Context, transferID, workerID int64) error {
+	funcName := "/service/shift_transfer/AcceptTransfer"
+
+	return s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		transfer, err := lockShiftTransfer(repos, transferID)
+		if err != nil {
+			log.Printf("%s: lockShiftTransfer error: %v", funcName, err)
+			return err
+		}
+		if transfer.Status != model.TRANSFER_OFFERED {
+			return fmt.Errorf("%w: transfer is %s", errs.ErrShiftTransferState, transfer.Status)
+		}
+		if transfer.FromUserID == workerID ||
+			(transfer.ToUserID != nil && *transfer.ToUserID != workerID) {
+			return errs.ErrShiftTransferNotAllowed
+		}
+
+		_, shift, err := lockTransferableWorkerShift(repos, transfer.WorkerShiftID, transfer.FromUserID, s.Clock.Now())
+		if err != nil {
+			log.Printf("%s: lockTransferableWorkerShift error: %v", funcName, err)
+			return err
+		}
+		if err := checkTransferTaker(repos, s.Rules, shift, workerID); err != nil {
+			log.Printf("%s: checkTransferTaker error: %v", funcName, err)
+			return err
+		}
+
+		transfer.TakerID = &workerID
+		transfer.Status = model.TRANSFER_ACCEPTED
+		if err := repos.ShiftTransfer.UpdateShiftTransfer(&transfer.ShiftTransfer); err != nil {
+			log.Printf("%s: UpdateShiftTransfer error: %v", funcName, err)
+			return err
+		}
+		return nil
+	})
+}
+
+// CancelTransfer withdraws an offer that has not been decided yet.
+func (s *ShiftTransferService) CancelTransfer(ctx context.Context, transferID, workerID int64) error {
+	funcName := "/service/shift_transfer/CancelTransfer"
+
+	return s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		transfer, err := lockShiftTransfer(repos, transferID)
+		if err != nil {
+			log.Printf("%s: lockShiftTransfer error: %v", funcName, err)
+			return err
+		}
+		if transfer.FromUserID != workerID {
+			return errs.ErrShiftTransferNotAllowed
+		}
+		if !isActiveTransfer(transfer.Status) {
+			return fmt.Errorf("%w: transfer is %s", errs.ErrShiftTransferState, transfer.Status)
+		}
+
+		transfer.Status = model.TRANSFER_CANCELLED
+		if err := repos.ShiftTransfer.UpdateShiftTransfer(&transfer.ShiftTransfer); err != nil {
+			log.Printf("%s: UpdateShiftTransfer error: %v", funcName, err)
+			return err
+		}
+		return nil
+	})
+}
+
+func (s *ShiftTransferService) GetTransfers(ctx context.Context, status *string) ([]model.ShiftTransferDetail, error) {
+	funcName := "/service/shift_transfer/GetTransfers"
+
+	transfers, err := s.ShiftTransferRepo.ListShiftTransfers(model.ShiftTransferQuery{Status: status})
+	if err != nil {
+		log.Printf("%s: ListShiftTransfers error: %v", funcName, err)
+		return nil, err
+	}
+	return nonNilTransfers(transfers), nil
+}
+
+// ApproveTransfer reassigns the worker shift to the taker of an accepted
+// transfer and records the change in the worker shift history, all in one
+// transaction.
+func (s *ShiftTransferService) ApproveTransfer(ctx context.Context, transferID int64) error {
+	funcName := "/service/shift_transfer/ApproveTransfer"
+
+	adminID := auth.UserID(ctx)
+
+	return s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		transfer, err := lockShiftTransfer(repos, transferID)
+		if err != nil {
+			log.Printf("%s: lockShiftTransfer error: %v", funcName, err)
+			return err
+		}
+		if transfer.Status != model.TRANSFER_ACCEPTED || transfer.TakerID == nil {
+			return fmt.Errorf("%w: transfer is %s", errs.ErrShiftTransferState, transfer.Status)
+		}
+		takerID := *transfer.TakerID
+
+		ws, shift, err := lockTransferableWorkerShift(repos, transfer.WorkerShiftID, transfer.FromUserID, s.Clock.Now())
+		if err != nil {
+			log.Printf("%s: lockTransferableWorkerShift error: %v", funcName, err)
+			return err
+		}
+
+		// Lock both workers in id order so two transfers between the same
+		// pair cannot deadlock, then re-check the taker's limits.
+		userIDs := []int64{transfer.FromUserID, takerID}
+		sort.Slice(userIDs, func(i, j int) bool { return userIDs[i] < userIDs[j] })
+		for _, userID := range userIDs {
+			if _, err := repos.User.GetUserByIDForUpdate(userID); err != nil {
+				log.Printf("%s: GetUserByIDForUpdate error: %v", funcName, err)
+				return err
+			}
+		}
+		if err := checkTransferTaker(repos, s.Rules, shift, takerID); err != nil {
+			log.Printf("%s: checkTransferTaker error: %v", funcName, err)
+			return err
+		}
+
+		if err := repos.WorkerShift.ReassignWorkerShift(ws.ID, takerID, adminID); err != nil {
+			log.Printf("%s: ReassignWorkerShift error: %v", funcName, err)
+			return err
+		}
+		_, err = repos.WorkerShift.CreateWorkerShiftHistory(&model.WorkerShiftHistory{
+			WorkerShiftID: ws.ID,
+			FromUserID:    transfer.FromUserID,
+			ToUserID:      takerID,
+			TransferID:    &transfer.ID,
+			ChangedBy:     adminID,
+		})
+		if err != nil {
+			log.Printf("%s: CreateWorkerShiftHistory error: %v", funcName, err)
+			return err
+		}
+
+		// A pending or waitlisted request of the taker for the same shift
+		// is now moot; left open, it could win the taker a second place.
+		workerShifts, err := repos.WorkerShift.ListWorkerShiftsByShift(shift.ID)
+		if err != nil {
+			log.Printf("%s: ListWorkerShiftsByShift error: %v", funcName, err)
+			return err
+		}
+		for _, other := range workerShifts {
+			if other.ID != ws.ID && other.UserAccountID == takerID &&
+				(other.Status == model.WORKER_SHIFT_PENDING || other.Status == model.WORKER_SHIFT_WAITLISTED) {
+				err := repos.WorkerShift.UpdatesWorkerShiftStatus(other.ID, model.WORKER_SHIFT_REJECTED, nil)
+				if err != nil {
+					log.Printf("%s: Reject error for wsID %d: %v", funcName, other.ID, err)
+					return err
+				}
+			}
+		}
+
+		transfer.Status = model.TRANSFER_APPROVED
+		transfer.DecidedBy = adminID
+		if err := repos.ShiftTransfer.UpdateShiftTransfer(&transfer.ShiftTransfer); err != nil {
+			log.Printf("%s: UpdateShiftTransfer error: %v", funcName, err)
+			return err
+		}
+		return nil
+	})
+}
+
+func (s *ShiftTransferService) RejectTransfer(ctx context.Context, transferID int64) error {
+	funcName := "/service/shift_transfer/RejectTransfer"
+
+	adminID := auth.UserID(ctx)
+
+	return s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		transfer, err := lockShiftTransfer(repos, transferID)
+		if err != nil {
+			log.Printf("%s: lockShiftTransfer error: %v", funcName, err)
+			return err
+		}
+		if !isActiveTransfer(transfer.Status) {
+			return fmt.Errorf("%w: transfer is %s", errs.ErrShiftTransferState, transfer.Status)
+		}
+
+		transfer.Status = model.TRANSFER_REJECTED
+		transfer.DecidedBy = adminID
+		if err := repos.ShiftTransfer.UpdateShiftTransfer(&transfer.ShiftTransfer); err != nil {
+			log.Printf("%s: UpdateShiftTransfer error: %v", funcName, err)
+			return err
+		}
+		return nil
+	})
+}
+
+func (s *ShiftTransferService) GetWorkerShiftHistory(ctx context.Context, workerShiftID int64) ([]model.WorkerShiftHistory, error) {
+	funcName := "/service/shift_transfer/GetWorkerShiftHistory"
+
+	if _, err := s.WorkerShiftRepo.GetWorkerShiftByID(workerShiftID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrWorkerShiftNotFound
+		}
+		log.Printf("%s: GetWorkerShiftByID error: %v", funcName, err)
+		return nil, err
+	}
+
+	history, err := s.WorkerShiftRepo.ListWorkerShiftHistory(workerShiftID)
+	if err != nil {
+		log.Printf("%s: ListWorkerShiftHistory error: %v", funcName, err)
+		return nil, err
+	}
+	if history == nil {
+		history = []model.WorkerShiftHistory{}
+	}
+	return history, nil
+}
+
+func lockShiftTransfer(repos *repository.Repositories, transferID int64) (*model.ShiftTransferDetail, error) {
+	transfer, err := repos.ShiftTransfer.GetShiftTransferByIDForUpdate(transferID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errs.ErrShiftTransferNotFound
+	}
+	return transfer, err
+}
+
+// lockTransferableWorkerShift locks the shift and the worker shift being
+// transferred and checks that it is approved for ownerID and has not
+// started yet.
+func lockTransferableWorkerShift(repos *repository.Repositories, workerShiftID, ownerID int64, now time.Time) (*model.WorkerShift, *model.Shift, error) {
+	ws, err := repos.WorkerShift.GetWorkerShiftByID(workerShiftID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, errs.ErrWorkerShiftNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Shift first, then worker shift: the same order as the shift request flow.
+	shift, err := repos.Shift.GetShiftByIDForUpdate(ws.ShiftID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, errs.ErrShiftNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	ws, err = repos.WorkerShift.GetWorkerShiftByIDForUpdate(workerShiftID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if ws.UserAccountID != ownerID {
+		return nil, nil, errs.ErrShiftTransferNotAllowed
+	}
+	if ws.Status != model.WORKER_SHIFT_APPROVED {
+		return nil, nil, fmt.Errorf("%w: worker shift is %s", errs.ErrShiftTransferState, ws.Status)
+	}
+	if !now.Before(shift.StartAt) {
+		return nil, nil, fmt.Errorf("%w: shift has started", errs.ErrShiftTransferState)
+	}
+	return ws, shift, nil
+}
+
+// checkTransferTaker checks that takerID is a worker who could be assigned
+// the shift through a regular request and holds no place on it yet, approved
+// or offered. A pending or waitlisted request does not stand in the way; the
+// approval of the transfer closes it.
+func checkTransferTaker(repos *repository.Repositories, engine *rules.Engine, shift *model.Shift, takerID int64) error {
+	taker, err := repos.User.GetUserByIDForUpdate(takerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: worker %d not found", errs.ErrInvalidShiftTransfer, takerID)
+	}
+	if err != nil {
+		return err
+	}
+	if taker.Role != model.ROLE_WORKER {
+		return fmt.Errorf("%w: user %d is not a worker", errs.ErrInvalidShiftTransfer, takerID)
+	}
+
+	workerShifts, err := repos.WorkerShift.ListWorkerShiftsByShift(shift.ID)
+	if err != nil {
+		return err
+	}
+	if holdsOtherPlace(workerShifts, takerID, 0) {
+		return errs.ErrAlreadyOnShift
+	}
+
+	return checkWorkerEligibility(repos, engine, shift, taker)
+}
+
+func isActiveTransfer(status string) bool {
+	return status == model.TRANSFER_OFFERED || status == model.TRANSFER_ACCEPTED
+}
+
+func nonNilTransfers(transfers []model.ShiftTransferDetail) []model.ShiftTransferDetail {
+	if transfers == nil {
+		return []model.ShiftTransferDetail{}
+	}
+	return transfers
+}
@@ -0,0 +1,137 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	errs "dailyworkerroster/error"
+	"dailyworkerroster/model"
+	"dailyworkerroster/rules"
+	"dailyworkerroster/service"
+)
+
+func (f *fixture) transferService() service.ShiftTransferServiceItf {
+	return service.NewShiftTransferService(f.repos.ShiftTransfer, f.repos.WorkerShift, f.uow,
+		rules.NewEngine(f.cfg.Rules), f.clock)
+}
+
+func TestStartedShiftCannotBeTransferred(t *testing.T) {
+	start := time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC)
+	f := newFixture(t, start.Add(-time.Hour))
+	shift := f.shift(t, start, 4*time.Hour)
+	approved := f.request(t, shift.ID, f.worker, model.WORKER_SHIFT_APPROVED)
+	taker := f.user(t, "bob", model.ROLE_WORKER)
+	svc := f.transferService()
+
+	transferID, err := svc.OfferShift(context.Background(), approved, f.worker, nil)
+	if err != nil {
+		t.Fatalf("OfferShift before start: %v", err)
+	}
+
+	// Later on the same day, once the shift is under way
+	f.clock.Set(start.Add(time.Hour))
+	if err := svc.AcceptTransfer(context.Background(), transferID, taker); !errors.Is(err, errs.ErrShiftTransferState) {
+		t.Errorf("AcceptTransfer after start: err = %v, want ErrShiftTransferState", err)
+	}
+	if err := svc.CancelTransfer(context.Background(), transferID, f.worker); err != nil {
+		t.Fatalf("CancelTransfer: %v", err)
+	}
+	if _, err := svc.OfferShift(context.Background(), approved, f.worker, nil); !errors.Is(err, errs.ErrShiftTransferState) {
+		t.Errorf("OfferShift after start: err = %v, want ErrShiftTransferState", err)
+	}
+	if got := f.status(t, approved); got != model.WORKER_SHIFT_APPROVED {
+		t.Errorf("worker shift is %s, want it to stay APPROVED", got)
+	}
+}
+
+func TestTransferTakerCannotWinSecondPlaceFromWaitlist(t *testing.T) {
+	now := time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC)
+	f := newFixture(t, now)
+	shift := f.shift(t, now.Add(72*time.Hour), 4*time.Hour)
+	shift.Headcount = 2
+	shift.IsAvailable = false
+	if err := f.repos.Shift.UpdateShiftByID(shift); err != nil {
+		t.Fatalf("UpdateShiftByID: %v", err)
+	}
+	bob := f.user(t, "bob", model.ROLE_WORKER)
+	carol := f.user(t, "carol", model.ROLE_WORKER)
+	offered := f.request(t, shift.ID, f.worker, model.WORKER_SHIFT_APPROVED)
+	f.request(t, shift.ID, carol, model.WORKER_SHIFT_APPROVED)
+	waitlisted := f.request(t, shift.ID, bob, model.WORKER_SHIFT_WAITLISTED)
+
+	transfers := f.transferService()
+	transferID, err := transfers.OfferShift(context.Background(), offered, f.worker, nil)
+	if err != nil {
+		t.Fatalf("OfferShift: %v", err)
+	}
+	if err := transfers.AcceptTransfer(context.Background(), transferID, bob); err != nil {
+		t.Fatalf("AcceptTransfer by a waitlisted worker: %v", err)
+	}
+	if err := transfers.ApproveTransfer(context.Background(), transferID); err != nil {
+		t.Fatalf("ApproveTransfer: %v", err)
+	}
+	if got := f.status(t, waitlisted); got != model.WORKER_SHIFT_REJECTED {
+		t.Errorf("taker's waitlisted request is %s, want REJECTED", got)
+	}
+
+	// Carol's place opens up; bob already has one
+	shifts := f.shiftService()
+	if err := shifts.CancelShift(context.Background(), shift.ID, carol); err != nil {
+		t.Fatalf("CancelShift: %v", err)
+	}
+	if err := shifts.AcceptOffer(context.Background(), shift.ID, bob); !errors.Is(err, errs.ErrNoOffer) {
+		t.Errorf("AcceptOffer by the taker: err = %v, want ErrNoOffer", err)
+	}
+
+	requests, err := f.repos.WorkerShift.ListWorkerShiftsByShift(shift.ID)
+	if err != nil {
+		t.Fatalf("ListWorkerShiftsByShift: %v", err)
+	}
+	places := 0
+	for _, ws := range requests {
+		if ws.UserAccountID == bob && (ws.Status == model.WORKER_SHIFT_APPROVED || ws.Status == model.WORKER_SHIFT_OFFERED) {
+			places++
+		}
+	}
+	if places != 1 {
+		t.Errorf("bob holds %d places on the shift, want 1", places)
+	}
+	if !f.isAvailable(t, shift.ID) {
+		t.Error("shift with a free place is not open")
+	}
+}
+
+func TestWorkerHoldingPlaceGetsNoSecondOne(t *testing.T) {
+	now := time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC)
+	f := newFixture(t, now)
+	bob := f.user(t, "bob", model.ROLE_WORKER)
+	shifts := f.shiftService()
+
+	// Requests left over from before bob got a place
+	shift := f.shift(t, now.Add(72*time.Hour), 4*time.Hour)
+	shift.Headcount = 2
+	if err := f.repos.Shift.UpdateShiftByID(shift); err != nil {
+		t.Fatalf("UpdateShiftByID: %v", err)
+	}
+	f.request(t, shift.ID, bob, model.WORKER_SHIFT_APPROVED)
+	waitlisted := f.request(t, shift.ID, bob, model.WORKER_SHIFT_WAITLISTED)
+	f.request(t, shift.ID, f.worker, model.WORKER_SHIFT_APPROVED)
+	if err := shifts.CancelShift(context.Background(), shift.ID, f.worker); err != nil {
+		t.Fatalf("CancelShift: %v", err)
+	}
+	if got := f.status(t, waitlisted); got != model.WORKER_SHIFT_WAITLISTED {
+		t.Errorf("waitlisted request of a worker holding a place is %s, want it passed over", got)
+	}
+
+	other := f.shift(t, now.Add(96*time.Hour), 4*time.Hour)
+	f.request(t, other.ID, bob, model.WORKER_SHIFT_APPROVED)
+	stale := f.request(t, other.ID, bob, model.WORKER_SHIFT_OFFERED)
+	if err := shifts.AcceptOffer(context.Background(), other.ID, bob); !errors.Is(err, errs.ErrAlreadyOnShift) {
+		t.Errorf("AcceptOffer while holding a place: err = %v, want ErrAlreadyOnShift", err)
+	}
+	if got := f.status(t, stale); got != model.WORKER_SHIFT_OFFERED {
+		t.Errorf("stale offer is %s, want it untouched", got)
+	}
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	errs "dailyworkerroster/error"
+	"dailyworkerroster/model"
+	"dailyworkerroster/repository"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type SkillServiceItf interface {
+	CreateSkill(ctx context.Context, skill *model.Skill) (int64, error)
+	GetSkills(ctx context.Context, active *bool) ([]*model.Skill, error)
+	GetSkillByID(ctx context.Context, skillID int64) (*model.Skill, error)
+	UpdateSkill(ctx context.Context, skill *model.Skill) error
+	DeleteSkill(ctx context.Context, skillID int64) error
+
+	GetWorkerSkills(ctx context.Context, workerID int64) ([]*model.WorkerSkill, error)
+	SetWorkerSkill(ctx context.Context, workerSkill *model.WorkerSkill) error
+	RemoveWorkerSkill(ctx context.Context, workerID, skillID int64) error
+}
+
+type SkillService struct {
+	SkillRepo       repository.SkillRepoItf
+	WorkerSkillRepo repository.WorkerSkillRepoItf
+	UnitOfWork      repository.UnitOfWorkItf
+}
+
+func NewSkillService(
+	skillRepo repository.SkillRepoItf,
+	workerSkillRepo repository.WorkerSkillRepoItf,
+	unitOfWork repository.UnitOfWorkItf) SkillServiceItf {
+	return &SkillService{
+		SkillRepo:       skillRepo,
+		WorkerSkillRepo: workerSkillRepo,
+		UnitOfWork:      unitOfWork,
+	}
+}
+
+var skillCodePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+func (s *SkillService) CreateSkill(ctx context.Context, skill *model.Skill) (int64, error) {
+	funcName := "/service/skill/CreateSkill"
+
+	skill.Code = strings.ToUpper(strings.TrimSpace(skill.Code))
+	if !skillCodePattern.MatchString(skill.Code) || len(skill.Code) > 50 {
+		return 0, fmt.Errorf("%w: code must be upper case letters, digits and underscores", errs.ErrInvalidSkill)
+	}
+	if err := normalizeSkill(skill); err != nil {
+		return 0, err
+	}
+
+	var id int64
+	err := s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		if _, err := repos.Skill.GetSkillByCode(skill.Code); err == nil {
+			return errs.ErrSkillExists
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("%s: GetSkillByCode error: %v", funcName, err)
+			return err
+		}
+
+		var err error
+		id, err = repos.Skill.CreateSkill(skill)
+		if err != nil {
+			log.Printf("%s: CreateSkill error: %v", funcName, err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (s *SkillService) GetSkills(ctx context.Context, active *bool) ([]*model.Skill, error) {
+	funcName := "/service/skill/GetSkills"
+
+	skills, err := s.SkillRepo.ListSkills(active)
+	if err != nil {
+		log.Printf("%s: ListSkills error: %v", funcName, err)
+		return nil, err
+	}
+	return skills, nil
+}
+
+func (s *SkillService) GetSkillByID(ctx context.Context, skillID int64) (*model.Skill, error) {
+	funcName := "/service/skill/GetSkillByID"
+
+	skill, err := s.SkillRepo.GetSkillByID(skillID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errs.ErrSkillNotFound
+	}
+	if err != nil {
+		log.Printf("%s: GetSkillByID error: %v", funcName, err)
+		return nil, err
+	}
+	return skill, nil
+}
+
+// UpdateSkill saves the name, description and active flag. The code is what
+// shifts refer to and cannot change.
+func (s *SkillService) UpdateSkill(ctx context.Context, skill *model.Skill) error {
+	funcName := "/service/skill/UpdateSkill"
+
+	if err := normalizeSkill(skill); err != nil {
+		return err
+	}
+
+	return s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		current, err := repos.Skill.GetSkillByIDForUpdate(skill.ID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return errs.ErrSkillNotFound
+		}
+		if err != nil {
+			log.Printf("%s: GetSkillByIDForUpdate error: %v", funcName, err)
+			return err
+		}
+		if !strings.EqualFold(skill.Code, current.Code) {
+			return fmt.Errorf("%w: the code of a skill cannot be changed", errs.ErrInvalidSkill)
+		}
+		skill.Code = current.Code
+
+		if err := repos.Skill.UpdateSkill(skill); err != nil {
+			log.Printf("%s: UpdateSkill error: %v", funcName, err)
+			return err
+		}
+		return nil
+	})
+}
+
+// DeleteSkill removes a skill no shift or template requires, together with
+// the workers' qualifications for it. Used skills can be deactivated instead.
+func (s *SkillService) DeleteSkill(ctx context.Context, skillID int64) error {
+	funcName := "/service/skill/DeleteSkill"
+
+	return s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		skill, err := repos.Skill.GetSkillByIDForUpdate(skillID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return errs.ErrSkillNotFound
+		}
+		if err != nil {
+			log.Printf("%s: GetSkillByIDForUpdate error: %v", funcName, err)
+			return err
+		}
+
+		inUse, err := repos.Skill.IsSkillInUse(skill.Code)
+		if err != nil {
+			log.Printf("%s: IsSkillInUse error: %v", funcName, err)
+			return err
+		}
+		if inUse {
+			return errs.ErrSkillInUse
+		}
+
+		if err := repos.WorkerSkill.DeleteWorkerSkillsBySkill(skillID); err != nil {
+			log.Printf("%s: DeleteWorkerSkillsBySkill error: %v", funcName, err)
+			return err
+		}
+		if err := repos.Skill.DeleteSkill(skillID); err != nil {
+			log.Printf("%s: DeleteSkill error: %v", funcName, err)
+			return err
+		}
+		return nil
+	})
+}
+
+func (s *SkillService) GetWorkerSkills(ctx context.Context, workerID int64) ([]*model.WorkerSkill, error) {
+	funcName := "/service/skill/GetWorkerSkills"
+
+	skills, err := s.WorkerSkillRepo.ListWorkerSkills(workerID)
+	if err != nil {
+		log.Printf("%s: ListWorkerSkills error: %v", funcName, err)
+		return nil, err
+	}
+	return skills, nil
+}
+
+// SetWorkerSkill qualifies a worker for an active skill, or changes the
+// expiry of a qualification they already hold.
+func (s *SkillService) SetWorkerSkill(ctx context.Context, workerSkill *model.WorkerSkill) error {
+	funcName := "/service/skill/SetWorkerSkill"
+
+	if workerSkill.ExpiresOn != nil {
+		if _, err := time.Parse(dateLayout, *workerSkill.ExpiresOn); err != nil {
+			return fmt.Errorf("%w: expires_on must be YYYY-MM-DD", errs.ErrInvalidSkill)
+		}
+	}
+
+	return s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		worker, err := repos.User.GetUserByIDForUpdate(workerSkill.UserAccountID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return errs.ErrUserNotFound
+		}
+		if err != nil {
+			log.Printf("%s: GetUserByIDForUpdate error: %v", funcName, err)
+			return err
+		}
+		if worker.Role != model.ROLE_WORKER {
+			return fmt.Errorf("%w: user %d is not a worker", errs.ErrInvalidSkill, worker.ID)
+		}
+
+		current, err := repos.WorkerSkill.GetWorkerSkill(workerSkill.UserAccountID, workerSkill.SkillID)
+		if err == nil {
+			workerSkill.ID = current.ID
+			if err := repos.WorkerSkill.UpdateWorkerSkill(workerSkill); err != nil {
+				log.Printf("%s: UpdateWorkerSkill error: %v", funcName, err)
+				return err
+			}
+			return nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("%s: GetWorkerSkill error: %v", funcName, err)
+			return err
+		}
+
+		skill, err := repos.Skill.GetSkillByID(workerSkill.SkillID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return errs.ErrSkillNotFound
+		}
+		if err != nil {
+			log.Printf("%s: GetSkillByID error: %v", funcName, err)
+			return err
+		}
+		if !skill.Active {
+			return fmt.Errorf("%w: skill %s is inactive", errs.ErrInvalidSkill, skill.Code)
+		}
+
+		if _, err := repos.WorkerSkill.CreateWorkerSkill(workerSkill); err != nil {
+			log.Printf("%s: CreateWorkerSkill error: %v", funcName, err)
+			return err
+		}
+		return nil
+	})
+}
+
+func (s *SkillService) RemoveWorkerSkill(ctx context.Context, workerID, skillID int64) error {
+	funcName := "/service/skill/RemoveWorkerSkill"
+
+	return s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		if _, err := repos.WorkerSkill.GetWorkerSkill(workerID, skillID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errs.ErrWorkerSkillNotFound
+			}
+			log.Printf("%s: GetWorkerSkill error: %v", funcName, err)
+			return err
+		}
+		if err := repos.WorkerSkill.DeleteWorkerSkill(workerID, skillID); err != nil {
+			log.Printf("%s: DeleteWorkerSkill error: %v", funcName, err)
+			return err
+		}
+		return nil
+	})
+}
+
+func normalizeSkill(skill *model.Skill) error {
+	skill.Name = strings.TrimSpace(skill.Name)
+	skill.Description = strings.TrimSpace(skill.Description)
+	if skill.Name == "" {
+		return fmt.Errorf("%w: name is required", errs.ErrInvalidSkill)
+	}
+	return nil
+}
+
+// validateRole normalises the role code of a shift or template and checks
+// it names a skill of the catalogue. Only the role it has already,
+// currentCode, may name an inactive skill.
+func validateRole(skills repository.SkillRepoItf, role *string, currentCode string) error {
+	*role = strings.ToUpper(strings.TrimSpace(*role))
+	if *role == "" {
+		return fmt.Errorf("%w: role_assignment is required", errs.ErrInvalidSkill)
+	}
+	skill, err := skills.GetSkillByCode(*role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: unknown role %q", errs.ErrInvalidSkill, *role)
+	}
+	if err != nil {
+		return err
+	}
+	if !skill.Active && skill.Code != currentCode {
+		return fmt.Errorf("%w: role %s is inactive", errs.ErrInvalidSkill, skill.Code)
+	}
+	return nil
+}
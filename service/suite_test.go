@@ -0,0 +1,168 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"dailyworkerroster/clock"
+	"dailyworkerroster/config"
+	"dailyworkerroster/migration"
+	"dailyworkerroster/model"
+	"dailyworkerroster/repository"
+)
+
+func TestMain(m *testing.M) {
+	// Services log every error they return; the tests check them instead
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// fixture is a migrated in-memory database with a location, an admin and a
+// cleaner, and a fake clock the services under test read.
+type fixture struct {
+	db     *sql.DB
+	repos  *repository.Repositories
+	uow    repository.UnitOfWorkItf
+	clock  *clock.Fake
+	cfg    *config.Config
+	zone   *time.Location
+	store  int64 // location in zone
+	admin  int64
+	worker int64
+}
+
+func newFixture(t *testing.T, now time.Time) *fixture {
+	t.Helper()
+
+	db, dialect, err := repository.Open(config.DatabaseConfig{DSN: "sqlite://:memory:"})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	migrator, err := migration.NewMigrator(db, dialect, time.UTC)
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+
+	zone, err := clock.LoadLocation("Europe/Amsterdam", time.UTC)
+	if err != nil {
+		t.Fatalf("load zone: %v", err)
+	}
+	f := &fixture{
+		db:    db,
+		repos: repository.NewRepositories(db, dialect, time.UTC),
+		uow:   repository.NewUnitOfWork(db, dialect, time.UTC),
+		clock: clock.NewFake(now),
+		cfg:   config.Default(),
+		zone:  zone,
+	}
+	f.store, err = f.repos.Location.CreateLocation(&model.Location{Name: "Store", TimeZone: zone.String(), Active: true})
+	if err != nil {
+		t.Fatalf("CreateLocation: %v", err)
+	}
+	f.admin = f.user(t, "admin", model.ROLE_ADMIN)
+	f.worker = f.user(t, "ann", model.ROLE_WORKER)
+	return f
+}
+
+func (f *fixture) user(t *testing.T, name, role string) int64 {
+	t.Helper()
+
+	id, err := f.repos.User.SignUp(&model.User{
+		Name: name, Username: name, Email: name + "@example.com", Password: "hash", Role: role,
+	})
+	if err != nil {
+		t.Fatalf("SignUp %s: %v", name, err)
+	}
+	if role == model.ROLE_WORKER {
+		cleaner, err := f.repos.Skill.GetSkillByCode("CLEANER")
+		if err != nil {
+			t.Fatalf("GetSkillByCode: %v", err)
+		}
+		if _, err := f.repos.WorkerSkill.CreateWorkerSkill(&model.WorkerSkill{UserAccountID: id, SkillID: cleaner.ID}); err != nil {
+			t.Fatalf("CreateWorkerSkill: %v", err)
+		}
+	}
+	return id
+}
+
+// shift creates an open cleaner shift at the store from start for length.
+func (f *fixture) shift(t *testing.T, start time.Time, length time.Duration) *model.Shift {
+	t.Helper()
+
+	start = start.In(f.zone)
+	end := start.Add(length)
+	shift := &model.Shift{
+		Date:           start.Format("2006-01-02"),
+		StartTime:      start.Format("15:04:05"),
+		EndTime:        end.Format("15:04:05"),
+		StartAt:        start,
+		EndAt:          end,
+		RoleAssignment: "CLEANER",
+		LocationID:     f.store,
+		IsAvailable:    true,
+		Headcount:      1,
+	}
+	id, err := f.repos.Shift.CreateShift(shift)
+	if err != nil {
+		t.Fatalf("CreateShift: %v", err)
+	}
+	shift.ID = id
+	return shift
+}
+
+// request files a request for the shift in the given status.
+func (f *fixture) request(t *testing.T, shiftID, workerID int64, status string) int64 {
+	t.Helper()
+
+	ws := &model.WorkerShift{ShiftID: shiftID, UserAccountID: workerID, Status: status}
+	if status != model.WORKER_SHIFT_PENDING && status != model.WORKER_SHIFT_WAITLISTED {
+		ws.ApprovedBy = &f.admin
+	}
+	id, err := f.repos.WorkerShift.CreateWorkerShift(ws)
+	if err != nil {
+		t.Fatalf("CreateWorkerShift: %v", err)
+	}
+	return id
+}
+
+// failUpdates makes every later update of the shift's requests fail, like
+// a database error in the middle of a transaction.
+func (f *fixture) failUpdates(t *testing.T, shiftID int64) {
+	t.Helper()
+
+	_, err := f.db.Exec(fmt.Sprintf(`CREATE TRIGGER fail_shift_%d BEFORE UPDATE ON worker_shift
+		WHEN NEW.shift_id = %d BEGIN SELECT RAISE(ABORT, 'update failed'); END`, shiftID, shiftID))
+	if err != nil {
+		t.Fatalf("create trigger: %v", err)
+	}
+}
+
+func (f *fixture) status(t *testing.T, workerShiftID int64) string {
+	t.Helper()
+
+	ws, err := f.repos.WorkerShift.GetWorkerShiftByID(workerShiftID)
+	if err != nil {
+		t.Fatalf("GetWorkerShiftByID: %v", err)
+	}
+	return ws.Status
+}
+
+func (f *fixture) isAvailable(t *testing.T, shiftID int64) bool {
+	t.Helper()
+
+	shift, err := f.repos.Shift.GetShiftByID(shiftID)
+	if err != nil {
+		t.Fatalf("GetShiftByID: %v", err)
+	}
+	return shift.IsAvailable
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"dailyworkerroster/auth"
+	errs "dailyworkerroster/error"
+	"dailyworkerroster/model"
+	"dailyworkerroster/repository"
+	"dailyworkerroster/rules"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// maxTimeOffDays is the longest leave a single request may cover
+const maxTimeOffDays = 366
+
+var timeOffTypes = map[string]bool{
+	model.TIME_OFF_VACATION: true,
+	model.TIME_OFF_SICK:     true,
+	model.TIME_OFF_PERSONAL: true,
+	model.TIME_OFF_UNPAID:   true,
+}
+
+type TimeOffServiceItf interface {
+	// Worker
+	RequestTimeOff(ctx context.Context, timeOff *model.TimeOff) (int64, error)
+	GetWorkerTimeOff(ctx context.Context, workerID int64) ([]*model.TimeOff, error)
+
+	// Admin
+	GetTimeOffRequests(ctx context.Context, queryParam model.TimeOffQuery) ([]*model.TimeOff, error)
+	ApproveTimeOff(ctx context.Context, timeOffID int64) ([]model.WorkerShiftDetail, error)
+	RejectTimeOff(ctx context.Context, timeOffID int64) error
+}
+
+type TimeOffService struct {
+	TimeOffRepo repository.TimeOffRepoItf
+	UnitOfWork  repository.UnitOfWorkItf
+}
+
+func NewTimeOffService(
+	timeOffRepo repository.TimeOffRepoItf,
+	unitOfWork repository.UnitOfWorkItf) TimeOffServiceItf {
+	return &TimeOffService{
+		TimeOffRepo: timeOffRepo,
+		UnitOfWork:  unitOfWork,
+	}
+}
+
+// RequestTimeOff submits leave for an admin to approve. It may not overlap
+// leave of the worker that is pending or approved.
+func (s *TimeOffService) RequestTimeOff(ctx context.Context, timeOff *model.TimeOff) (int64, error) {
+	funcName := "/service/time_off/RequestTimeOff"
+
+	if err := normalizeTimeOff(timeOff); err != nil {
+		return 0, err
+	}
+	timeOff.Status = model.TIME_OFF_PENDING
+
+	var id int64
+	err := s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		// Locking the worker serializes their requests, so two of them
+		// cannot both pass the overlap check.
+		worker, err := repos.User.GetUserByIDForUpdate(timeOff.UserAccountID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return errs.ErrUserNotFound
+		}
+		if err != nil {
+			log.Printf("%s: GetUserByIDForUpdate error: %v", funcName, err)
+			return err
+		}
+		if worker.Role != model.ROLE_WORKER {
+			return fmt.Errorf("%w: user %d is not a worker", errs.ErrInvalidTimeOff, worker.ID)
+		}
+
+		existing, err := repos.TimeOff.ListTimeOff(model.TimeOffQuery{
+			UserAccountID: &timeOff.UserAccountID,
+			DateFrom:      &timeOff.StartDate,
+			DateTo:        &timeOff.EndDate,
+		})
+		if err != nil {
+			log.Printf("%s: ListTimeOff error: %v", funcName, err)
+			return err
+		}
+		for _, other := range existing {
+			if other.Status == model.TIME_OFF_PENDING || other.Status == model.TIME_OFF_APPROVED {
+				return fmt.Errorf("%w: %s from %s to %s", errs.ErrTimeOffOverlap, other.Status, other.StartDate, other.EndDate)
+			}
+		}
+
+		id, err = repos.TimeOff.CreateTimeOff(timeOff)
+		if err != nil {
+			log.Printf("%s: CreateTimeOff error: %v", funcName, err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (s *TimeOffService) GetWorkerTimeOff(ctx context.Context, workerID int64) ([]*model.TimeOff, error) {
+	funcName := "/service/time_off/GetWorkerTimeOff"
+
+	list, err := s.TimeOffRepo.ListTimeOff(model.TimeOffQuery{UserAccountID: &workerID})
+	if err != nil {
+		log.Printf("%s: ListTimeOff error: %v", funcName, err)
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *TimeOffService) GetTimeOffRequests(ctx context.Context, queryParam model.TimeOffQuery) ([]*model.TimeOff, error) {
+	funcName := "/service/time_off/GetTimeOffRequests"
+
+	list, err := s.TimeOffRepo.ListTimeOff(queryParam)
+	if err != nil {
+		log.Printf("%s: ListTimeOff error: %v", funcName, err)
+		return nil, err
+	}
+	return list, nil
+}
+
+// ApproveTimeOff approves pending leave. From then on the worker cannot
+// request or be given shifts on those days. Shifts already approved for them
+// on those days are kept but flagged for reassignment, and returned.
+func (s *TimeOffService) ApproveTimeOff(ctx context.Context, timeOffID int64) ([]model.WorkerShiftDetail, error) {
+	funcName := "/service/time_off/ApproveTimeOff"
+
+	flagged := make([]model.WorkerShiftDetail, 0)
+	err := s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		timeOff, err := lockPendingTimeOff(repos, timeOffID)
+		if err != nil {
+			return err
+		}
+		if _, err := repos.User.GetUserByIDForUpdate(timeOff.UserAccountID); err != nil {
+			log.Printf("%s: GetUserByIDForUpdate error: %v", funcName, err)
+			return err
+		}
+
+		if err := repos.TimeOff.UpdateTimeOffStatus(timeOff.ID, model.TIME_OFF_APPROVED, auth.UserID(ctx)); err != nil {
+			log.Printf("%s: UpdateTimeOffStatus error: %v", funcName, err)
+			return err
+		}
+
+		// Night shifts starting the day before may reach into the leave
+		start, err := time.Parse(dateLayout, timeOff.StartDate)
+		if err != nil {
+			return err
+		}
+		dateFrom := start.AddDate(0, 0, -1).Format(dateLayout)
+		status := model.WORKER_SHIFT_APPROVED
+		assigned, err := repos.WorkerShift.GetWorkerShiftDetailListByFilter(&model.WorkerShiftDetailQuery{
+			UserAccountID: &timeOff.UserAccountID,
+			Status:        &status,
+			DateFrom:      &dateFrom,
+			DateTo:        &timeOff.EndDate,
+		})
+		if err != nil {
+			log.Printf("%s: GetWorkerShiftDetailListByFilter error: %v", funcName, err)
+			return err
+		}
+		for _, detail := range assigned {
+			if !rules.DuringTimeOff(detail.AsShift(), timeOff) {
+				continue
+			}
+			if err := repos.WorkerShift.FlagForReassignment(detail.ID); err != nil {
+				log.Printf("%s: FlagForReassignment error for wsID %d: %v", funcName, detail.ID, err)
+				return err
+			}
+			detail.NeedsReassignment = true
+			flagged = append(flagged, detail)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return flagged, nil
+}
+
+func (s *TimeOffService) RejectTimeOff(ctx context.Context, timeOffID int64) error {
+	funcName := "/service/time_off/RejectTimeOff"
+
+	return s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		timeOff, err := lockPendingTimeOff(repos, timeOffID)
+		if err != nil {
+			return err
+		}
+		if err := repos.TimeOff.UpdateTimeOffStatus(timeOff.ID, model.TIME_OFF_REJECTED, auth.UserID(ctx)); err != nil {
+			log.Printf("%s: UpdateTimeOffStatus error: %v", funcName, err)
+			return err
+		}
+		return nil
+	})
+}
+
+func lockPendingTimeOff(repos *repository.Repositories, timeOffID int64) (*model.TimeOff, error) {
+	timeOff, err := repos.TimeOff.GetTimeOffByIDForUpdate(timeOffID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errs.ErrTimeOffNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if timeOff.Status != model.TIME_OFF_PENDING {
+		return nil, fmt.Errorf("%w: it is %s", errs.ErrTimeOffState, timeOff.Status)
+	}
+	return timeOff, nil
+}
+
+func normalizeTimeOff(timeOff *model.TimeOff) error {
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: %s", errs.ErrInvalidTimeOff, fmt.Sprintf(format, args...))
+	}
+
+	timeOff.Type = strings.ToUpper(strings.TrimSpace(timeOff.Type))
+	if !timeOffTypes[timeOff.Type] {
+		return invalid("unknown type %q", timeOff.Type)
+	}
+	start, err := time.Parse(dateLayout, timeOff.StartDate)
+	if err != nil {
+		return invalid("start_date must be YYYY-MM-DD")
+	}
+	end, err := time.Parse(dateLayout, timeOff.EndDate)
+	if err != nil {
+		return invalid("end_date must be YYYY-MM-DD")
+	}
+	if end.Before(start) {
+		return invalid("end_date is before start_date")
+	}
+	if end.Sub(start) >= maxTimeOffDays*24*time.Hour {
+		return invalid("time off lasts more than %d days", maxTimeOffDays)
+	}
+	timeOff.Reason = strings.TrimSpace(timeOff.Reason)
+	if len(timeOff.Reason) > 255 {
+		return invalid("reason is longer than 255 characters")
+	}
+	return nil
+}
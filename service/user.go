@@ -1,11 +1,18 @@
 package service
 
 import (
+	"context"
+	"dailyworkerroster/auth"
+	"dailyworkerroster/config"
+	errs "dailyworkerroster/error"
 	"dailyworkerroster/middleware"
 	"dailyworkerroster/model"
 	"dailyworkerroster/repository"
+	"database/sql"
 	"errors"
-	"fmt"
+	"log"
+	"strings"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -15,20 +22,41 @@ type UserServiceItf interface {
 	Login(identifier, password string) (*model.User, error)
 	GetAllWorkers() ([]*model.User, error)
 	GetWorkerByID(workerID int64) (*model.User, error)
+
+	// Sessions
+	RefreshToken(ctx context.Context, refreshToken string) (*model.TokenPair, error)
+	Logout(ctx context.Context) error
+	RevokeUserSessions(ctx context.Context, userID int64) (int64, error)
 }
 
 type UserService struct {
-	UserRepo repository.UserRepoItf
+	UserRepo        repository.UserRepoItf
+	UserSessionRepo repository.UserSessionRepoItf
+	UnitOfWork      repository.UnitOfWorkItf
+	Config          config.AuthConfig
 }
 
 func NewUserService(
-	userRepo repository.UserRepoItf) UserServiceItf {
+	userRepo repository.UserRepoItf,
+	userSessionRepo repository.UserSessionRepoItf,
+	unitOfWork repository.UnitOfWorkItf,
+	cfg config.AuthConfig) UserServiceItf {
 	return &UserService{
-		UserRepo: userRepo,
+		UserRepo:        userRepo,
+		UserSessionRepo: userSessionRepo,
+		UnitOfWork:      unitOfWork,
+		Config:          cfg,
 	}
 }
 
 func (s *UserService) SignUp(user *model.User) (int64, error) {
+	if user.DateOfBirth != nil {
+		birth, err := time.Parse(dateLayout, *user.DateOfBirth)
+		if err != nil || !birth.Before(time.Now()) {
+			return 0, errs.ErrInvalidDateOfBirth
+		}
+	}
+
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return 0, err
@@ -42,7 +70,6 @@ func (s *UserService) Login(identifier, password string) (*model.User, error) {
 	if err != nil {
 		return nil, errors.New("failed to get login credentials")
 	}
-	fmt.Println("halo", user.Password, " ", password)
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
 	if err != nil {
@@ -50,16 +77,142 @@ func (s *UserService) Login(identifier, password string) (*model.User, error) {
 	}
 
 	user.Password = ""
-	user.JWTToken, err = middleware.GenerateJWT(user.ID, user.Name, user.Role)
+
+	// Every login opens its own session; the refresh token is
+	// "<session id>.<secret>" and only the secret's hash is stored.
+	secret := auth.NewToken(32)
+	session := &model.UserSession{
+		ID:               auth.NewToken(16),
+		UserAccountID:    user.ID,
+		RefreshTokenHash: auth.HashToken(secret),
+		ExpiresAt:        time.Now().Add(s.Config.RefreshTokenTTL.Std()),
+	}
+	if err := s.UserSessionRepo.CreateUserSession(session); err != nil {
+		return nil, err
+	}
+
+	user.JWTToken, err = middleware.GenerateJWT([]byte(s.Config.JWTSecret), user.ID, user.Name, user.Role, session.ID, s.Config.AccessTokenTTL.Std())
 	if err != nil {
 		return nil, err
 	}
+	user.RefreshToken = session.ID + "." + secret
+	user.ExpiresIn = int64(s.Config.AccessTokenTTL.Std().Seconds())
 
 	return user, nil
 }
 
+// RefreshToken trades a refresh token for a new access token and a new
+// refresh token. Each refresh token works once: presenting the one the last
+// rotation replaced means it leaked, so the whole session is revoked. Any
+// other wrong secret is refused without touching the session, so a garbled
+// or forged token cannot log the user out.
+func (s *UserService) RefreshToken(ctx context.Context, refreshToken string) (*model.TokenPair, error) {
+	funcName := "/service/user/RefreshToken"
+
+	sessionID, secret, ok := strings.Cut(refreshToken, ".")
+	if !ok || sessionID == "" || secret == "" {
+		return nil, errs.ErrInvalidRefreshToken
+	}
+
+	var pair *model.TokenPair
+	reused := false
+	err := s.UnitOfWork.WithinTx(ctx, func(repos *repository.Repositories) error {
+		now := time.Now()
+		session, err := repos.UserSession.GetUserSessionByIDForUpdate(sessionID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return errs.ErrInvalidRefreshToken
+		}
+		if err != nil {
+			log.Printf("%s: GetUserSessionByIDForUpdate error: %v", funcName, err)
+			return err
+		}
+		if session.RevokedAt != nil || !now.Before(session.ExpiresAt) {
+			return errs.ErrInvalidRefreshToken
+		}
+
+		hash := auth.HashToken(secret)
+		if hash != session.RefreshTokenHash {
+			if session.PreviousTokenHash == nil || hash != *session.PreviousTokenHash {
+				return errs.ErrInvalidRefreshToken
+			}
+			// Commit the revocation, the error is returned after the transaction
+			reused = true
+			return repos.UserSession.RevokeUserSession(session.ID, now)
+		}
+
+		user, err := repos.User.GetUserByID(session.UserAccountID)
+		if err != nil {
+			log.Printf("%s: GetUserByID error: %v", funcName, err)
+			return err
+		}
+
+		newSecret := auth.NewToken(32)
+		err = repos.UserSession.RotateUserSession(session.ID, auth.HashToken(newSecret), now.Add(s.Config.RefreshTokenTTL.Std()))
+		if err != nil {
+			log.Printf("%s: RotateUserSession error: %v", funcName, err)
+			return err
+		}
+
+		accessToken, err := middleware.GenerateJWT([]byte(s.Config.JWTSecret), user.ID, user.Name, user.Role, session.ID, s.Config.AccessTokenTTL.Std())
+		if err != nil {
+			return err
+		}
+		pair = &model.TokenPair{
+			JWTToken:     accessToken,
+			RefreshToken: session.ID + "." + newSecret,
+			ExpiresIn:    int64(s.Config.AccessTokenTTL.Std().Seconds()),
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if reused {
+		log.Printf("%s: refresh token reuse detected, session %s revoked", funcName, sessionID)
+		return nil, errs.ErrInvalidRefreshToken
+	}
+	return pair, nil
+}
+
+// Logout revokes the session of the calling user's access token.
+func (s *UserService) Logout(ctx context.Context) error {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return errors.New("no authenticated user")
+	}
+	return s.UserSessionRepo.RevokeUserSession(principal.SessionID, time.Now())
+}
+
+// RevokeUserSessions logs a user out everywhere and returns how many
+// sessions were revoked.
+func (s *UserService) RevokeUserSessions(ctx context.Context, userID int64) (int64, error) {
+	funcName := "/service/user/RevokeUserSessions"
+
+	if _, err := s.UserRepo.GetUserByID(userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, errs.ErrUserNotFound
+		}
+		log.Printf("%s: GetUserByID error: %v", funcName, err)
+		return 0, err
+	}
+
+	revoked, err := s.UserSessionRepo.RevokeUserSessionsByUser(userID, time.Now())
+	if err != nil {
+		log.Printf("%s: RevokeUserSessionsByUser error: %v", funcName, err)
+		return 0, err
+	}
+	return revoked, nil
+}
+
 func (s *UserService) GetAllWorkers() ([]*model.User, error) {
-	return s.UserRepo.GetUsersByRole(model.ROLE_WORKER)
+	users, err := s.UserRepo.GetUsersByRole(model.ROLE_WORKER)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		user.Password = ""
+	}
+	return users, nil
 }
 
 func (s *UserService) GetWorkerByID(workerID int64) (*model.User, error) {
@@ -0,0 +1,104 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"dailyworkerroster/auth"
+	errs "dailyworkerroster/error"
+	"dailyworkerroster/model"
+	"dailyworkerroster/service"
+)
+
+func (f *fixture) userService() service.UserServiceItf {
+	return service.NewUserService(f.repos.User, f.repos.UserSession, f.uow, f.cfg.Auth)
+}
+
+// login signs up a worker and logs them in, returning the refresh token.
+func (f *fixture) login(t *testing.T, svc service.UserServiceItf) string {
+	t.Helper()
+
+	_, err := svc.SignUp(&model.User{
+		Name: "dan", Username: "dan", Email: "dan@example.com", Password: "secret", Role: model.ROLE_WORKER,
+	})
+	if err != nil {
+		t.Fatalf("SignUp: %v", err)
+	}
+	user, err := svc.Login("dan", "secret")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	return user.RefreshToken
+}
+
+func TestRefreshTokenRotates(t *testing.T) {
+	f := newFixture(t, time.Now())
+	svc := f.userService()
+	first := f.login(t, svc)
+
+	pair, err := svc.RefreshToken(context.Background(), first)
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	if pair.RefreshToken == first || pair.JWTToken == "" {
+		t.Errorf("pair %+v, want a new refresh token and an access token", pair)
+	}
+	if _, err := svc.RefreshToken(context.Background(), pair.RefreshToken); err != nil {
+		t.Errorf("RefreshToken with the replacement: %v", err)
+	}
+}
+
+func TestRefreshTokenReuseRevokesSession(t *testing.T) {
+	f := newFixture(t, time.Now())
+	svc := f.userService()
+	first := f.login(t, svc)
+	pair, err := svc.RefreshToken(context.Background(), first)
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+
+	if _, err := svc.RefreshToken(context.Background(), first); !errors.Is(err, errs.ErrInvalidRefreshToken) {
+		t.Errorf("RefreshToken with a used token: err = %v, want ErrInvalidRefreshToken", err)
+	}
+	if _, err := svc.RefreshToken(context.Background(), pair.RefreshToken); !errors.Is(err, errs.ErrInvalidRefreshToken) {
+		t.Errorf("RefreshToken after reuse: err = %v, want the session revoked", err)
+	}
+}
+
+func TestRefreshTokenWrongSecretKeepsSession(t *testing.T) {
+	f := newFixture(t, time.Now())
+	svc := f.userService()
+	token := f.login(t, svc)
+	sessionID, _, _ := strings.Cut(token, ".")
+
+	for _, bad := range []string{sessionID + ".forged", "unknown." + auth.NewToken(32), "garbled"} {
+		if _, err := svc.RefreshToken(context.Background(), bad); !errors.Is(err, errs.ErrInvalidRefreshToken) {
+			t.Errorf("RefreshToken(%q): err = %v, want ErrInvalidRefreshToken", bad, err)
+		}
+	}
+	if _, err := svc.RefreshToken(context.Background(), token); err != nil {
+		t.Errorf("RefreshToken after a forged one: %v, want the session kept", err)
+	}
+}
+
+func TestRefreshTokenExpired(t *testing.T) {
+	f := newFixture(t, time.Now())
+	secret := auth.NewToken(32)
+	session := &model.UserSession{
+		ID:               auth.NewToken(16),
+		UserAccountID:    f.worker,
+		RefreshTokenHash: auth.HashToken(secret),
+		ExpiresAt:        time.Now().Add(-time.Minute),
+	}
+	if err := f.repos.UserSession.CreateUserSession(session); err != nil {
+		t.Fatalf("CreateUserSession: %v", err)
+	}
+
+	_, err := f.userService().RefreshToken(context.Background(), session.ID+"."+secret)
+	if !errors.Is(err, errs.ErrInvalidRefreshToken) {
+		t.Errorf("RefreshToken of an expired session: err = %v, want ErrInvalidRefreshToken", err)
+	}
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	errs "dailyworkerroster/error"
+	"dailyworkerroster/model"
+	"dailyworkerroster/repository"
+	"dailyworkerroster/rules"
+	"math"
+	"sort"
+	"time"
+)
+
+// holdsPlace reports whether a request takes up one of its shift's places:
+// it is approved, or a place was offered to it and is not answered yet.
+func holdsPlace(ws *model.WorkerShift) bool {
+	return ws.Status == model.WORKER_SHIFT_APPROVED || ws.Status == model.WORKER_SHIFT_OFFERED
+}
+
+// holdsOtherPlace reports whether the worker holds one of the shift's places
+// through a request other than exceptID.
+func holdsOtherPlace(workerShifts []*model.WorkerShift, workerID, exceptID int64) bool {
+	for _, ws := range workerShifts {
+		if ws.ID != exceptID && ws.UserAccountID == workerID && holdsPlace(ws) {
+			return true
+		}
+	}
+	return false
+}
+
+// onRecord reports whether a request holds or once held one of its shift's
+// places. Its worker's history and time entries refer to the shift, so the
+// shift can neither be deleted nor moved.
+func onRecord(ws *model.WorkerShift) bool {
+	switch ws.Status {
+	case model.WORKER_SHIFT_APPROVED, model.WORKER_SHIFT_OFFERED, model.WORKER_SHIFT_DONE,
+		model.WORKER_SHIFT_NO_SHOW, model.WORKER_SHIFT_CANCELLED:
+		return true
+	}
+	return false
+}
+
+// checkShiftRemovable fails when deleting the shift would lose a worker's
+// record or silently drop a request still waiting for an answer. Pending and
+// waitlisted requests have to be rejected first, so their workers are told.
+func checkShiftRemovable(workerShifts []*model.WorkerShift) error {
+	waiting := false
+	for _, ws := range workerShifts {
+		if onRecord(ws) {
+			return errs.ErrShiftHasAssignments
+		}
+		if ws.Status == model.WORKER_SHIFT_PENDING || ws.Status == model.WORKER_SHIFT_WAITLISTED {
+			waiting = true
+		}
+	}
+	if waiting {
+		return errs.ErrShiftHasOpenRequests
+	}
+	return nil
+}
+
+// closeIfFull marks the shift unavailable once all its places are taken,
+// and moves its pending requests, in request order, to the end of the
+// waitlist. It returns the requests it waitlisted. workerShifts are the
+// shift's requests and are updated in place.
+func closeIfFull(repos *repository.Repositories, shift *model.Shift, workerShifts []*model.WorkerShift) ([]*model.WorkerShift, error) {
+	taken, last := 0, 0
+	var pending []*model.WorkerShift
+	for _, ws := range workerShifts {
+		if holdsPlace(ws) {
+			taken++
+		}
+		if ws.WaitlistPosition != nil && *ws.WaitlistPosition > last {
+			last = *ws.WaitlistPosition
+		}
+		if ws.Status == model.WORKER_SHIFT_PENDING {
+			pending = append(pending, ws)
+		}
+	}
+	if taken < shift.Headcount {
+		return nil, nil
+	}
+
+	if shift.IsAvailable {
+		shift.IsAvailable = false
+		if err := repos.Shift.UpdateShiftByID(shift); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(pending, func(i, j int) bool { return pending[i].ID < pending[j].ID })
+	for _, ws := range pending {
+		last++
+		position := last
+		if err := repos.WorkerShift.WaitlistWorkerShift(ws.ID, position); err != nil {
+			return nil, err
+		}
+		ws.Status = model.WORKER_SHIFT_WAITLISTED
+		ws.WaitlistPosition = &position
+	}
+	return pending, nil
+}
+
+// offerVacancies offers the shift's free places to its waitlisted workers
+// in waitlist order, until now+ttl or the start of the shift, whichever
+// comes first. Workers the labour rules now keep off the shift, or who
+// already hold a place on it, are passed over and stay on the waitlist. The shift takes new requests again when
+// places remain free. It returns the requests offered a place.
+func offerVacancies(repos *repository.Repositories, engine *rules.Engine, shift *model.Shift, workerShifts []*model.WorkerShift, now time.Time, ttl time.Duration) ([]*model.WorkerShift, error) {
+	start, err := shiftStart(shift)
+	if err != nil {
+		return nil, err
+	}
+	if !now.Before(start) {
+		return nil, nil
+	}
+	deadline := now.Add(ttl)
+	if start.Before(deadline) {
+		deadline = start
+	}
+
+	free := shift.Headcount
+	var waitlisted []*model.WorkerShift
+	for _, ws := range workerShifts {
+		if holdsPlace(ws) {
+			free--
+		}
+		if ws.Status == model.WORKER_SHIFT_WAITLISTED {
+			waitlisted = append(waitlisted, ws)
+		}
+	}
+	sort.SliceStable(waitlisted, func(i, j int) bool {
+		return waitlistPosition(waitlisted[i]) < waitlistPosition(waitlisted[j])
+	})
+
+	var offered []*model.WorkerShift
+	for _, ws := range waitlisted {
+		if free <= 0 {
+			break
+		}
+		if holdsOtherPlace(workerShifts, ws.UserAccountID, ws.ID) {
+			continue
+		}
+		worker, err := repos.User.GetUserByIDForUpdate(ws.UserAccountID)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkWorkerEligibility(repos, engine, shift, worker); err != nil {
+			if _, ok := rules.AsViolations(err); ok {
+				continue
+			}
+			return nil, err
+		}
+		if err := repos.WorkerShift.OfferWorkerShift(ws.ID, deadline); err != nil {
+			return nil, err
+		}
+		expiresAt := deadline
+		ws.Status = model.WORKER_SHIFT_OFFERED
+		ws.OfferExpiresAt = &expiresAt
+		offered = append(offered, ws)
+		free--
+	}
+
+	if available := free > 0; shift.IsAvailable != available {
+		shift.IsAvailable = available
+		if err := repos.Shift.UpdateShiftByID(shift); err != nil {
+			return nil, err
+		}
+	}
+	return offered, nil
+}
+
+func waitlistPosition(ws *model.WorkerShift) int {
+	if ws.WaitlistPosition == nil {
+		return math.MaxInt
+	}
+	return *ws.WaitlistPosition
+}